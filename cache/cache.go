@@ -0,0 +1,219 @@
+// Package cache provides read-through caching for user and
+// organization lookups.
+//
+// The caching clients embed the regular API clients, so they can be
+// used in their place. Only the Get methods are cached. Any other
+// request for a resource that goes through the caching client
+// invalidates the resource's cache entries. Changes that are made
+// elsewhere can be propagated with webhook events.
+//
+//	users := cache.NewUserClient(user.NewClient(config), &cache.Params{TTL: time.Minute})
+//	router.On(webhook.EventUserUpdated, users.InvalidateEvent)
+//	router.On(webhook.EventUserDeleted, users.InvalidateEvent)
+//
+// Cached resources are shared between callers and must not be
+// modified.
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/clerk/clerk-sdk-go/v2"
+	"github.com/clerk/clerk-sdk-go/v2/organization"
+	"github.com/clerk/clerk-sdk-go/v2/user"
+	"github.com/clerk/clerk-sdk-go/v2/webhook"
+)
+
+// UserClient is a user.Client which caches users that are retrieved
+// with Get.
+type UserClient struct {
+	*user.Client
+	cache *store[*clerk.User]
+}
+
+// NewUserClient returns a UserClient which wraps the client. The
+// default Backend will be used if client is nil.
+func NewUserClient(client *user.Client, params *Params) *UserClient {
+	c := &UserClient{
+		cache: newStore[*clerk.User](params),
+	}
+	var backend clerk.Backend
+	if client != nil {
+		backend = client.Backend
+	}
+	c.Client = &user.Client{
+		Backend: newInvalidatingBackend(backend, "/users", c.Invalidate),
+	}
+	return c
+}
+
+// Get retrieves the user from the cache, or from the Clerk API if
+// it's not cached.
+func (c *UserClient) Get(ctx context.Context, id string) (*clerk.User, error) {
+	return c.cache.get(ctx, id, func(ctx context.Context) (*clerk.User, string, error) {
+		usr, err := c.Client.Get(ctx, id)
+		if err != nil {
+			return nil, "", err
+		}
+		return usr, usr.ID, nil
+	})
+}
+
+// Invalidate removes the user from the cache.
+func (c *UserClient) Invalidate(id string) {
+	c.cache.invalidate(id)
+}
+
+// Purge removes all users from the cache.
+func (c *UserClient) Purge() {
+	c.cache.purge()
+}
+
+// InvalidateEvent removes the user of a user webhook event from the
+// cache. It can be registered as a webhook.Router handler.
+func (c *UserClient) InvalidateEvent(_ context.Context, event *webhook.Event) error {
+	if !strings.HasPrefix(event.Type, "user.") {
+		return nil
+	}
+	id, err := eventResourceID(event)
+	if err != nil {
+		return err
+	}
+	c.Invalidate(id)
+	return nil
+}
+
+// OrganizationClient is an organization.Client which caches
+// organizations that are retrieved with Get or GetWithParams.
+type OrganizationClient struct {
+	*organization.Client
+	cache *store[*clerk.Organization]
+}
+
+// NewOrganizationClient returns an OrganizationClient which wraps
+// the client. The default Backend will be used if client is nil.
+func NewOrganizationClient(client *organization.Client, params *Params) *OrganizationClient {
+	c := &OrganizationClient{
+		cache: newStore[*clerk.Organization](params),
+	}
+	var backend clerk.Backend
+	if client != nil {
+		backend = client.Backend
+	}
+	c.Client = &organization.Client{
+		Backend: newInvalidatingBackend(backend, "/organizations", c.Invalidate),
+	}
+	return c
+}
+
+// Get retrieves the organization from the cache, or from the Clerk
+// API if it's not cached.
+// The organization can be fetched by either the ID or its slug.
+func (c *OrganizationClient) Get(ctx context.Context, idOrSlug string) (*clerk.Organization, error) {
+	return c.GetWithParams(ctx, idOrSlug, &organization.GetParams{})
+}
+
+// GetWithParams retrieves the organization from the cache, or from
+// the Clerk API if it's not cached. Organizations are cached
+// separately for each set of params.
+// The organization can be fetched by either the ID or its slug.
+func (c *OrganizationClient) GetWithParams(ctx context.Context, idOrSlug string, params *organization.GetParams) (*clerk.Organization, error) {
+	key := idOrSlug
+	if query := params.ToQuery().Encode(); query != "" {
+		key += "?" + query
+	}
+	return c.cache.get(ctx, key, func(ctx context.Context) (*clerk.Organization, string, error) {
+		org, err := c.Client.GetWithParams(ctx, idOrSlug, params)
+		if err != nil {
+			return nil, "", err
+		}
+		return org, org.ID, nil
+	})
+}
+
+// Invalidate removes the organization from the cache, including
+// entries that were fetched by slug.
+func (c *OrganizationClient) Invalidate(id string) {
+	c.cache.invalidate(id)
+}
+
+// Purge removes all organizations from the cache.
+func (c *OrganizationClient) Purge() {
+	c.cache.purge()
+}
+
+// InvalidateEvent removes the organization of an organization or
+// organizationMembership webhook event from the cache. Membership
+// events are included because they change the organization's
+// members count. It can be registered as a webhook.Router handler.
+func (c *OrganizationClient) InvalidateEvent(_ context.Context, event *webhook.Event) error {
+	switch {
+	case strings.HasPrefix(event.Type, "organization."):
+		id, err := eventResourceID(event)
+		if err != nil {
+			return err
+		}
+		c.Invalidate(id)
+	case strings.HasPrefix(event.Type, "organizationMembership."):
+		membershipEvent, err := event.OrganizationMembershipEvent()
+		if err != nil {
+			return err
+		}
+		if org := membershipEvent.OrganizationMembership.Organization; org != nil {
+			c.Invalidate(org.ID)
+		}
+	}
+	return nil
+}
+
+// Returns the ID of the resource in the event data.
+func eventResourceID(event *webhook.Event) (string, error) {
+	var resource struct {
+		ID string `json:"id"`
+	}
+	err := json.Unmarshal(event.Data, &resource)
+	return resource.ID, err
+}
+
+// A clerk.Backend which invalidates the cache entries for a resource
+// on every request that is not a GET request. Paths for the resource
+// have the format <prefix>/<id>[/...].
+type invalidatingBackend struct {
+	backend    clerk.Backend
+	prefix     string
+	invalidate func(id string)
+}
+
+func newInvalidatingBackend(backend clerk.Backend, prefix string, invalidate func(string)) *invalidatingBackend {
+	return &invalidatingBackend{
+		backend:    backend,
+		prefix:     prefix,
+		invalidate: invalidate,
+	}
+}
+
+func (b *invalidatingBackend) Call(ctx context.Context, req *clerk.APIRequest, resource clerk.ResponseReader) error {
+	backend := b.backend
+	if backend == nil {
+		backend = clerk.GetBackend()
+	}
+	err := backend.Call(ctx, req, resource)
+	if req.Method != http.MethodGet {
+		if id := b.resourceID(req.Path); id != "" {
+			b.invalidate(id)
+		}
+	}
+	return err
+}
+
+func (b *invalidatingBackend) resourceID(path string) string {
+	rest := strings.TrimPrefix(path, b.prefix+"/")
+	if rest == path {
+		return ""
+	}
+	id, _, _ := strings.Cut(rest, "/")
+	return id
+}
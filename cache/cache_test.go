@@ -0,0 +1,107 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/clerk/clerk-sdk-go/v2"
+	"github.com/clerk/clerk-sdk-go/v2/organization"
+	"github.com/clerk/clerk-sdk-go/v2/user"
+	"github.com/clerk/clerk-sdk-go/v2/webhook"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUserClient(t *testing.T) {
+	t.Parallel()
+	requests := map[string]int{}
+	clerkAPI := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests[r.Method+" "+r.URL.Path]++
+		_, err := w.Write([]byte(`{"id":"user_123","object":"user"}`))
+		require.NoError(t, err)
+	}))
+	defer clerkAPI.Close()
+	config := &clerk.ClientConfig{}
+	config.HTTPClient = clerkAPI.Client()
+	config.URL = &clerkAPI.URL
+	client := NewUserClient(user.NewClient(config), nil)
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		usr, err := client.Get(ctx, "user_123")
+		require.NoError(t, err)
+		require.Equal(t, "user_123", usr.ID)
+	}
+	require.Equal(t, 1, requests["GET /users/user_123"])
+
+	// Updates through the client invalidate the cache.
+	_, err := client.Ban(ctx, "user_123")
+	require.NoError(t, err)
+	_, err = client.Get(ctx, "user_123")
+	require.NoError(t, err)
+	require.Equal(t, 2, requests["GET /users/user_123"])
+
+	// Webhook events invalidate the cache.
+	err = client.InvalidateEvent(ctx, &webhook.Event{
+		Type: webhook.EventUserUpdated,
+		Data: json.RawMessage(`{"object":"user","id":"user_123"}`),
+	})
+	require.NoError(t, err)
+	_, err = client.Get(ctx, "user_123")
+	require.NoError(t, err)
+	require.Equal(t, 3, requests["GET /users/user_123"])
+}
+
+func TestOrganizationClient(t *testing.T) {
+	t.Parallel()
+	requests := map[string]int{}
+	clerkAPI := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests[r.Method+" "+r.URL.Path+"?"+r.URL.RawQuery]++
+		_, err := w.Write([]byte(`{"id":"org_123","slug":"acme","object":"organization"}`))
+		require.NoError(t, err)
+	}))
+	defer clerkAPI.Close()
+	config := &clerk.ClientConfig{}
+	config.HTTPClient = clerkAPI.Client()
+	config.URL = &clerkAPI.URL
+	client := NewOrganizationClient(organization.NewClient(config), nil)
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		_, err := client.Get(ctx, "org_123")
+		require.NoError(t, err)
+		_, err = client.Get(ctx, "acme")
+		require.NoError(t, err)
+		_, err = client.GetWithParams(ctx, "org_123", &organization.GetParams{
+			IncludeMembersCount: clerk.Bool(true),
+		})
+		require.NoError(t, err)
+	}
+	require.Equal(t, map[string]int{
+		"GET /organizations/org_123?":                           1,
+		"GET /organizations/acme?":                              1,
+		"GET /organizations/org_123?include_members_count=true": 1,
+	}, requests)
+
+	// Membership events invalidate all entries of the organization,
+	// including the ones fetched by slug.
+	err := client.InvalidateEvent(ctx, &webhook.Event{
+		Type: webhook.EventOrganizationMembershipCreated,
+		Data: json.RawMessage(`{"object":"organization_membership","organization":{"id":"org_123"}}`),
+	})
+	require.NoError(t, err)
+	_, err = client.Get(ctx, "acme")
+	require.NoError(t, err)
+	_, err = client.GetWithParams(ctx, "org_123", &organization.GetParams{
+		IncludeMembersCount: clerk.Bool(true),
+	})
+	require.NoError(t, err)
+	require.Equal(t, 2, requests["GET /organizations/acme?"])
+	require.Equal(t, 2, requests["GET /organizations/org_123?include_members_count=true"])
+
+	// Events for other resources are ignored.
+	err = client.InvalidateEvent(ctx, &webhook.Event{Type: webhook.EventUserCreated})
+	require.NoError(t, err)
+}
@@ -0,0 +1,208 @@
+package cache
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+
+	"github.com/clerk/clerk-sdk-go/v2"
+	"github.com/clerk/clerk-sdk-go/v2/internal/contextutil"
+)
+
+const (
+	defaultTTL          = time.Minute
+	defaultMaxEntries   = 10000
+	defaultFetchTimeout = 30 * time.Second
+)
+
+type Params struct {
+	// TTL is the duration for which fetched resources are cached.
+	// Defaults to one minute.
+	TTL time.Duration
+	// MaxEntries is the maximum number of cached resources. The
+	// least recently used resources are evicted when the cache is
+	// full. Defaults to 10000.
+	MaxEntries int
+	// Clock can be used to keep track of time and will replace usage
+	// of the [time] package.
+	Clock clerk.Clock
+	// FetchTimeout is the maximum duration of a request to the Clerk
+	// API on a cache miss. The request is shared by all callers that
+	// miss the same entry, so it isn't canceled when a caller's
+	// context is done. Defaults to 30 seconds.
+	FetchTimeout time.Duration
+}
+
+// A least recently used cache with expiring entries. Concurrent
+// misses for the same key share a single fetch.
+// Each entry is tagged with the ID of the resource it holds, so
+// that all entries for a resource can be invalidated together.
+type store[V any] struct {
+	ttl          time.Duration
+	maxEntries   int
+	fetchTimeout time.Duration
+	clock        clerk.Clock
+
+	mu      sync.Mutex
+	entries map[string]*list.Element
+	lru     *list.List
+	tags    map[string]map[string]struct{}
+	calls   map[string]*call[V]
+	// Incremented on every invalidation, so that fetches which
+	// started before an invalidation don't store stale values.
+	generation uint64
+}
+
+type entry[V any] struct {
+	key       string
+	tag       string
+	value     V
+	expiresAt time.Time
+}
+
+// An in-flight or completed fetch.
+type call[V any] struct {
+	done  chan struct{}
+	value V
+	err   error
+}
+
+func newStore[V any](params *Params) *store[V] {
+	if params == nil {
+		params = &Params{}
+	}
+	s := &store[V]{
+		ttl:          params.TTL,
+		maxEntries:   params.MaxEntries,
+		fetchTimeout: params.FetchTimeout,
+		clock:        params.Clock,
+		entries:      map[string]*list.Element{},
+		lru:          list.New(),
+		tags:         map[string]map[string]struct{}{},
+		calls:        map[string]*call[V]{},
+	}
+	if s.ttl <= 0 {
+		s.ttl = defaultTTL
+	}
+	if s.maxEntries <= 0 {
+		s.maxEntries = defaultMaxEntries
+	}
+	if s.fetchTimeout <= 0 {
+		s.fetchTimeout = defaultFetchTimeout
+	}
+	if s.clock == nil {
+		s.clock = clerk.NewClock()
+	}
+	return s
+}
+
+// Returns the cached value for the key, or calls fetch on a miss.
+// The fetch returns the value and its tag.
+// The fetch is shared by all callers, so it runs in the background
+// with the values of the first caller's context, but without its
+// cancellation. Each caller stops waiting when its own context is
+// done.
+func (s *store[V]) get(ctx context.Context, key string, fetch func(context.Context) (V, string, error)) (V, error) {
+	s.mu.Lock()
+	if elem, ok := s.entries[key]; ok {
+		e := elem.Value.(*entry[V])
+		if e.expiresAt.After(s.clock.Now()) {
+			s.lru.MoveToFront(elem)
+			s.mu.Unlock()
+			return e.value, nil
+		}
+		s.remove(elem)
+	}
+	c, ok := s.calls[key]
+	if !ok {
+		c = &call[V]{done: make(chan struct{})}
+		s.calls[key] = c
+		go s.fetch(ctx, key, c, s.generation, fetch)
+	}
+	s.mu.Unlock()
+
+	select {
+	case <-c.done:
+		return c.value, c.err
+	case <-ctx.Done():
+		var zero V
+		return zero, ctx.Err()
+	}
+}
+
+// Runs the fetch for the call and stores the value, unless the cache
+// was invalidated since the generation.
+func (s *store[V]) fetch(ctx context.Context, key string, c *call[V], generation uint64, fetch func(context.Context) (V, string, error)) {
+	ctx, cancel := context.WithTimeout(contextutil.WithoutCancel(ctx), s.fetchTimeout)
+	defer cancel()
+	var tag string
+	c.value, tag, c.err = fetch(ctx)
+
+	s.mu.Lock()
+	delete(s.calls, key)
+	if c.err == nil && generation == s.generation {
+		s.add(key, tag, c.value)
+	}
+	s.mu.Unlock()
+	close(c.done)
+}
+
+// Removes all entries with the tag.
+func (s *store[V]) invalidate(tag string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.generation++
+	for key := range s.tags[tag] {
+		if elem, ok := s.entries[key]; ok {
+			s.remove(elem)
+		}
+	}
+}
+
+// Removes all entries.
+func (s *store[V]) purge() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.generation++
+	s.entries = map[string]*list.Element{}
+	s.lru.Init()
+	s.tags = map[string]map[string]struct{}{}
+}
+
+func (s *store[V]) len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.lru.Len()
+}
+
+func (s *store[V]) add(key, tag string, value V) {
+	if elem, ok := s.entries[key]; ok {
+		s.remove(elem)
+	}
+	for s.lru.Len() >= s.maxEntries {
+		s.remove(s.lru.Back())
+	}
+	e := &entry[V]{
+		key:       key,
+		tag:       tag,
+		value:     value,
+		expiresAt: s.clock.Now().Add(s.ttl),
+	}
+	s.entries[key] = s.lru.PushFront(e)
+	if s.tags[tag] == nil {
+		s.tags[tag] = map[string]struct{}{}
+	}
+	s.tags[tag][key] = struct{}{}
+}
+
+func (s *store[V]) remove(elem *list.Element) {
+	e := s.lru.Remove(elem).(*entry[V])
+	delete(s.entries, e.key)
+	if keys, ok := s.tags[e.tag]; ok {
+		delete(keys, e.key)
+		if len(keys) == 0 {
+			delete(s.tags, e.tag)
+		}
+	}
+}
@@ -0,0 +1,159 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/clerk/clerk-sdk-go/v2/clerktest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStore(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	clock := clerktest.NewClockAt(time.Now().UTC())
+	s := newStore[string](&Params{TTL: time.Minute, MaxEntries: 2, Clock: clock})
+	fetches := 0
+	fetch := func(value string) func(context.Context) (string, string, error) {
+		return func(context.Context) (string, string, error) {
+			fetches++
+			return value, "tag_" + value, nil
+		}
+	}
+
+	v, err := s.get(ctx, "a", fetch("a"))
+	require.NoError(t, err)
+	require.Equal(t, "a", v)
+	_, err = s.get(ctx, "a", fetch("a"))
+	require.NoError(t, err)
+	require.Equal(t, 1, fetches)
+
+	// Entries expire.
+	clock.Advance(2 * time.Minute)
+	_, err = s.get(ctx, "a", fetch("a"))
+	require.NoError(t, err)
+	require.Equal(t, 2, fetches)
+
+	// The least recently used entry is evicted.
+	_, err = s.get(ctx, "b", fetch("b"))
+	require.NoError(t, err)
+	_, err = s.get(ctx, "a", fetch("a"))
+	require.NoError(t, err)
+	_, err = s.get(ctx, "c", fetch("c"))
+	require.NoError(t, err)
+	require.Equal(t, 2, s.len())
+	require.Equal(t, 4, fetches)
+	_, err = s.get(ctx, "a", fetch("a"))
+	require.NoError(t, err)
+	require.Equal(t, 4, fetches)
+
+	// Invalidation removes the entries by tag.
+	s.invalidate("tag_a")
+	_, err = s.get(ctx, "a", fetch("a"))
+	require.NoError(t, err)
+	require.Equal(t, 5, fetches)
+
+	s.purge()
+	require.Equal(t, 0, s.len())
+
+	// Errors are not cached.
+	_, err = s.get(ctx, "err", func(context.Context) (string, string, error) {
+		return "", "", fmt.Errorf("oops")
+	})
+	require.Error(t, err)
+	require.Equal(t, 0, s.len())
+}
+
+func TestStore_Singleflight(t *testing.T) {
+	t.Parallel()
+	s := newStore[string](nil)
+	var fetches int32
+	release := make(chan struct{})
+	fetch := func(context.Context) (string, string, error) {
+		atomic.AddInt32(&fetches, 1)
+		<-release
+		return "value", "tag", nil
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			v, err := s.get(context.Background(), "key", fetch)
+			require.NoError(t, err)
+			require.Equal(t, "value", v)
+		}()
+	}
+	// Wait until the first fetch has started, then give the other
+	// goroutines a chance to join it.
+	require.Eventually(t, func() bool { return atomic.LoadInt32(&fetches) == 1 }, time.Second, time.Millisecond)
+	time.Sleep(10 * time.Millisecond)
+	close(release)
+	wg.Wait()
+	require.Equal(t, int32(1), atomic.LoadInt32(&fetches))
+}
+
+func TestStore_InvalidateDuringFetch(t *testing.T) {
+	t.Parallel()
+	s := newStore[string](nil)
+	_, err := s.get(context.Background(), "key", func(context.Context) (string, string, error) {
+		// The resource changes while it's being fetched.
+		s.invalidate("tag")
+		return "stale", "tag", nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, 0, s.len())
+}
+
+func TestStore_CanceledCaller(t *testing.T) {
+	t.Parallel()
+	s := newStore[string](nil)
+	type ctxKey struct{}
+	started := make(chan struct{})
+	release := make(chan struct{})
+	fetch := func(ctx context.Context) (string, string, error) {
+		close(started)
+		<-release
+		// The fetch keeps the values of the first caller's context,
+		// but not its cancellation.
+		require.Equal(t, "value", ctx.Value(ctxKey{}))
+		return "value", "tag", ctx.Err()
+	}
+
+	ctx, cancel := context.WithCancel(context.WithValue(context.Background(), ctxKey{}, "value"))
+	errs := make(chan error)
+	go func() {
+		_, err := s.get(ctx, "key", fetch)
+		errs <- err
+	}()
+	<-started
+
+	waiter := make(chan string)
+	go func() {
+		v, err := s.get(context.Background(), "key", fetch)
+		require.NoError(t, err)
+		waiter <- v
+	}()
+
+	// The first caller gives up, the other one gets the value.
+	cancel()
+	require.ErrorIs(t, <-errs, context.Canceled)
+	close(release)
+	require.Equal(t, "value", <-waiter)
+	require.Equal(t, 1, s.len())
+}
+
+func TestStore_FetchTimeout(t *testing.T) {
+	t.Parallel()
+	s := newStore[string](&Params{FetchTimeout: time.Millisecond})
+	_, err := s.get(context.Background(), "key", func(ctx context.Context) (string, string, error) {
+		<-ctx.Done()
+		return "", "", ctx.Err()
+	})
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+}
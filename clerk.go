@@ -37,6 +37,19 @@ func SetKey(key string) {
 	secretKey = key
 }
 
+// IsDevelopmentKey returns true if the provided secret key belongs
+// to a Clerk development instance. Development instance secret keys
+// have the "sk_test_" prefix.
+func IsDevelopmentKey(key string) bool {
+	return strings.HasPrefix(key, "sk_test_")
+}
+
+// IsDevelopmentInstance returns true if the secret key that was set
+// with SetKey belongs to a Clerk development instance.
+func IsDevelopmentInstance() bool {
+	return IsDevelopmentKey(secretKey)
+}
+
 // APIResource describes a Clerk API resource and contains fields and
 // methods common to all resources.
 type APIResource struct {
@@ -96,6 +96,13 @@ type Clock struct {
 	mu sync.RWMutex
 	// The current time of this test clock.
 	time time.Time
+	// Functions scheduled with AfterFunc that haven't run yet.
+	timers []*clockTimer
+}
+
+type clockTimer struct {
+	at time.Time
+	f  func()
 }
 
 // NewClockAt returns a Clock initialized at the given time.
@@ -105,12 +112,50 @@ func NewClockAt(t time.Time) *Clock {
 
 // Now returns the clock's current time.
 func (c *Clock) Now() time.Time {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
 	return c.time
 }
 
-// Advance moves the test clock to a new point in time.
+// Advance moves the test clock to a new point in time. Functions
+// scheduled with AfterFunc that are due by then are run in their
+// own goroutine.
 func (c *Clock) Advance(d time.Duration) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 	c.time = c.time.Add(d)
+	pending := c.timers[:0]
+	for _, timer := range c.timers {
+		if timer.at.After(c.time) {
+			pending = append(pending, timer)
+			continue
+		}
+		go timer.f()
+	}
+	c.timers = pending
+}
+
+// AfterFunc schedules f to run once the clock has been advanced by
+// at least d. The returned function cancels the call and reports
+// whether it was still pending, like time.Timer.Stop.
+func (c *Clock) AfterFunc(d time.Duration, f func()) func() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	timer := &clockTimer{at: c.time.Add(d), f: f}
+	if d <= 0 {
+		go f()
+		return func() bool { return false }
+	}
+	c.timers = append(c.timers, timer)
+	return func() bool {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		for i, t := range c.timers {
+			if t == timer {
+				c.timers = append(c.timers[:i], c.timers[i+1:]...)
+				return true
+			}
+		}
+		return false
+	}
 }
@@ -68,14 +68,6 @@ func main() {
 			continue
 		}
 
-		// We've reached a line containing a method definition. Let's
-		// write the method godoc comments.
-		_, err := b.WriteString(comments.String())
-		if err != nil {
-			log.Fatal(fmt.Errorf("cannot write comments: %w", err))
-		}
-		comments.Reset()
-
 		// Gather all exported *Client functions
 		vars := getFuncVars(line)
 		isExported := false
@@ -86,8 +78,19 @@ func main() {
 			isExported = unicode.IsUpper(r)
 		}
 		if !isExported {
+			// The comments document an unexported method.
+			comments.Reset()
 			continue
 		}
+
+		// We've reached a line containing a method definition. Let's
+		// write the method godoc comments.
+		_, err := b.WriteString(comments.String())
+		if err != nil {
+			log.Fatal(fmt.Errorf("cannot write comments: %w", err))
+		}
+		comments.Reset()
+
 		err = funcTempl.Execute(&b, vars)
 		if err != nil {
 			log.Fatal(fmt.Errorf("write func from %s: %w", line, err))
@@ -0,0 +1,127 @@
+// This program compares a Clerk instance with a declarative
+// configuration spec and applies the changes.
+//
+//	CLERK_SECRET_KEY=sk_live_... go run ./cmd/instanceconfig plan -f clerk.yaml
+//	CLERK_SECRET_KEY=sk_live_... go run ./cmd/instanceconfig apply -f clerk.yaml
+//
+// See the instanceconfig package for the format of the spec.
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/clerk/clerk-sdk-go/v2"
+	"github.com/clerk/clerk-sdk-go/v2/instanceconfig"
+)
+
+const usage = `Usage: instanceconfig <command> [flags]
+
+Commands:
+  plan   Print the changes that are needed to match the spec
+  apply  Apply the changes that are needed to match the spec
+
+The Clerk secret key is read from the CLERK_SECRET_KEY environment
+variable.
+`
+
+// Exit code of the plan command for plans with changes, when the
+// -detailed-exitcode flag is set.
+const exitCodeChanges = 2
+
+func main() {
+	os.Exit(run(context.Background(), os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+func run(ctx context.Context, args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		fmt.Fprint(stderr, usage)
+		return 1
+	}
+	command := args[0]
+	flags := flag.NewFlagSet(command, flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	file := flags.String("f", "clerk.yaml", "path of the YAML or JSON spec")
+	apiURL := flags.String("api-url", "", "base URL of the Clerk API, for example https://api.clerk.com/v1")
+	var detailedExitCode, autoApprove *bool
+	switch command {
+	case "plan":
+		detailedExitCode = flags.Bool("detailed-exitcode", false, "exit with code 2 if there are changes")
+	case "apply":
+		autoApprove = flags.Bool("auto-approve", false, "apply the changes without asking for confirmation")
+	default:
+		fmt.Fprint(stderr, usage)
+		return 1
+	}
+	if err := flags.Parse(args[1:]); err != nil {
+		return 1
+	}
+
+	secretKey := os.Getenv("CLERK_SECRET_KEY")
+	if secretKey == "" {
+		fmt.Fprintln(stderr, "CLERK_SECRET_KEY is not set")
+		return 1
+	}
+	config := &clerk.BackendConfig{Key: clerk.String(secretKey)}
+	if *apiURL != "" {
+		config.URL = apiURL
+	}
+	manager := instanceconfig.NewManager(clerk.NewBackend(config))
+
+	spec, err := instanceconfig.LoadFile(*file)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+	plan, err := manager.Plan(ctx, spec)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+	fmt.Fprint(stdout, plan)
+
+	if command == "plan" {
+		if *detailedExitCode && !plan.Empty() {
+			return exitCodeChanges
+		}
+		return 0
+	}
+
+	if plan.Empty() {
+		return 0
+	}
+	if !*autoApprove {
+		approved, err := confirm(stdin, stdout)
+		if err != nil {
+			fmt.Fprintln(stderr, err)
+			return 1
+		}
+		if !approved {
+			fmt.Fprintln(stdout, "Apply cancelled.")
+			return 1
+		}
+	}
+	err = manager.Apply(ctx, plan)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+	fmt.Fprintln(stdout, "Apply complete.")
+	return 0
+}
+
+// Asks for confirmation before the changes are applied.
+func confirm(stdin io.Reader, stdout io.Writer) (bool, error) {
+	fmt.Fprint(stdout, "\nApply these changes? Only 'yes' will be accepted: ")
+	answer, err := bufio.NewReader(stdin).ReadString('\n')
+	if err != nil && !errors.Is(err, io.EOF) {
+		return false, err
+	}
+	return strings.TrimSpace(answer) == "yes", nil
+}
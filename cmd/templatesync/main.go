@@ -0,0 +1,148 @@
+// This program keeps the email and SMS templates of a Clerk instance
+// in a directory, so that changes can be code reviewed.
+//
+//	CLERK_SECRET_KEY=sk_live_... go run ./cmd/templatesync pull -dir templates
+//	CLERK_SECRET_KEY=sk_live_... go run ./cmd/templatesync diff -dir templates -preview
+//	CLERK_SECRET_KEY=sk_live_... go run ./cmd/templatesync push -dir templates
+//
+// See the templatesync package for the layout of the directory.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/clerk/clerk-sdk-go/v2"
+	"github.com/clerk/clerk-sdk-go/v2/templatesync"
+)
+
+const usage = `Usage: templatesync <command> [flags]
+
+Commands:
+  pull  Write the live templates to the directory
+  diff  Validate the templates and print the ones that changed
+  push  Validate the templates and update the ones that changed
+
+The Clerk secret key is read from the CLERK_SECRET_KEY environment
+variable.
+`
+
+// Exit code of the diff command for templates with changes, when the
+// -detailed-exitcode flag is set.
+const exitCodeChanges = 2
+
+func main() {
+	os.Exit(run(context.Background(), os.Args[1:], os.Stdout, os.Stderr))
+}
+
+func run(ctx context.Context, args []string, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		fmt.Fprint(stderr, usage)
+		return 1
+	}
+	command := args[0]
+	flags := flag.NewFlagSet(command, flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	dir := flags.String("dir", "templates", "directory of the template files")
+	apiURL := flags.String("api-url", "", "base URL of the Clerk API, for example https://api.clerk.com/v1")
+	var preview, detailedExitCode *bool
+	switch command {
+	case "pull":
+	case "diff":
+		preview = flags.Bool("preview", false, "render the changed templates with the Clerk API")
+		detailedExitCode = flags.Bool("detailed-exitcode", false, "exit with code 2 if there are changes")
+	case "push":
+		preview = flags.Bool("preview", false, "render the changed templates with the Clerk API before pushing")
+	default:
+		fmt.Fprint(stderr, usage)
+		return 1
+	}
+	if err := flags.Parse(args[1:]); err != nil {
+		return 1
+	}
+
+	secretKey := os.Getenv("CLERK_SECRET_KEY")
+	if secretKey == "" {
+		fmt.Fprintln(stderr, "CLERK_SECRET_KEY is not set")
+		return 1
+	}
+	config := &clerk.BackendConfig{Key: clerk.String(secretKey)}
+	if *apiURL != "" {
+		config.URL = apiURL
+	}
+	syncer := templatesync.NewSyncer(clerk.NewBackend(config))
+
+	if command == "pull" {
+		templates, err := syncer.Pull(ctx)
+		if err != nil {
+			fmt.Fprintln(stderr, err)
+			return 1
+		}
+		err = templatesync.Write(*dir, templates)
+		if err != nil {
+			fmt.Fprintln(stderr, err)
+			return 1
+		}
+		fmt.Fprintf(stdout, "Wrote %d templates to %s.\n", len(templates), *dir)
+		return 0
+	}
+
+	templates, err := templatesync.Load(*dir)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+	changes, err := syncer.Diff(ctx, templates)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+	if *preview {
+		err = syncer.Preview(ctx, changes)
+		if err != nil {
+			fmt.Fprintln(stderr, err)
+			return 1
+		}
+	}
+	printChanges(stdout, changes)
+
+	if command == "diff" {
+		if *detailedExitCode && len(changes) > 0 {
+			return exitCodeChanges
+		}
+		return 0
+	}
+
+	err = syncer.Push(ctx, changes)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+	fmt.Fprintf(stdout, "Pushed %d templates.\n", len(changes))
+	return 0
+}
+
+// Prints the changed templates and their previews.
+func printChanges(w io.Writer, changes []*templatesync.Change) {
+	if len(changes) == 0 {
+		fmt.Fprintln(w, "No changes. The templates match the instance.")
+		return
+	}
+	for _, change := range changes {
+		fmt.Fprintf(w, "~ %s/%s (%s)\n", change.Template.TemplateType, change.Template.Slug, strings.Join(change.Fields, ", "))
+		if change.Preview == nil {
+			continue
+		}
+		if change.Preview.Subject != "" {
+			fmt.Fprintf(w, "    Subject: %s\n", change.Preview.Subject)
+		}
+		for _, line := range strings.Split(strings.TrimRight(change.Preview.Body, "\n"), "\n") {
+			fmt.Fprintf(w, "    %s\n", line)
+		}
+	}
+	fmt.Fprintf(w, "\n%d templates changed.\n", len(changes))
+}
@@ -0,0 +1,88 @@
+package clerk
+
+import (
+	"context"
+	"sync"
+)
+
+const (
+	clerkUser         = key("clerkUser")
+	clerkOrganization = key("clerkOrganization")
+)
+
+// ContextWithUser returns a new context which includes the user.
+func ContextWithUser(ctx context.Context, user *User) context.Context {
+	return context.WithValue(ctx, clerkUser, &contextLoader[User]{value: user, loaded: true})
+}
+
+// ContextWithUserLoader returns a new context which includes a
+// function that loads the user. The function is called at most once,
+// the first time UserFromContext is called, and the loaded user is
+// returned by all subsequent calls.
+func ContextWithUserLoader(ctx context.Context, load func() (*User, error)) context.Context {
+	return context.WithValue(ctx, clerkUser, &contextLoader[User]{load: load})
+}
+
+// UserFromContext returns the user from the context. If the user is
+// loaded lazily and loading fails, the second return value is false.
+// The same user is returned for every call with the context, so it
+// must not be modified.
+func UserFromContext(ctx context.Context) (*User, bool) {
+	loader, ok := ctx.Value(clerkUser).(*contextLoader[User])
+	if !ok {
+		return nil, false
+	}
+	return loader.Get()
+}
+
+// ContextWithOrganization returns a new context which includes the
+// organization.
+func ContextWithOrganization(ctx context.Context, organization *Organization) context.Context {
+	return context.WithValue(ctx, clerkOrganization, &contextLoader[Organization]{value: organization, loaded: true})
+}
+
+// ContextWithOrganizationLoader returns a new context which includes
+// a function that loads the organization. The function is called at
+// most once, the first time OrganizationFromContext is called, and
+// the loaded organization is returned by all subsequent calls.
+func ContextWithOrganizationLoader(ctx context.Context, load func() (*Organization, error)) context.Context {
+	return context.WithValue(ctx, clerkOrganization, &contextLoader[Organization]{load: load})
+}
+
+// OrganizationFromContext returns the organization from the context.
+// If the organization is loaded lazily and loading fails, the second
+// return value is false.
+// The same organization is returned for every call with the context,
+// so it must not be modified.
+func OrganizationFromContext(ctx context.Context) (*Organization, bool) {
+	loader, ok := ctx.Value(clerkOrganization).(*contextLoader[Organization])
+	if !ok {
+		return nil, false
+	}
+	return loader.Get()
+}
+
+// Holds a context value which is either provided directly, or
+// loaded once on first access.
+type contextLoader[T any] struct {
+	once   sync.Once
+	load   func() (*T, error)
+	value  *T
+	loaded bool
+}
+
+// Get returns the value, loading it if needed.
+func (l *contextLoader[T]) Get() (*T, bool) {
+	l.once.Do(func() {
+		if l.loaded || l.load == nil {
+			return
+		}
+		value, err := l.load()
+		if err != nil {
+			return
+		}
+		l.value = value
+		l.loaded = true
+	})
+	return l.value, l.loaded && l.value != nil
+}
@@ -0,0 +1,41 @@
+package clerk
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestUserFromContext(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	_, ok := UserFromContext(ctx)
+	require.False(t, ok)
+
+	user, ok := UserFromContext(ContextWithUser(ctx, &User{ID: "user_123"}))
+	require.True(t, ok)
+	require.Equal(t, "user_123", user.ID)
+
+	calls := 0
+	ctx = ContextWithUserLoader(ctx, func() (*User, error) {
+		calls++
+		return &User{ID: "user_456"}, nil
+	})
+	for i := 0; i < 2; i++ {
+		user, ok = UserFromContext(ctx)
+		require.True(t, ok)
+		require.Equal(t, "user_456", user.ID)
+	}
+	require.Equal(t, 1, calls)
+}
+
+func TestOrganizationFromContext_LoaderError(t *testing.T) {
+	t.Parallel()
+	ctx := ContextWithOrganizationLoader(context.Background(), func() (*Organization, error) {
+		return nil, fmt.Errorf("oops")
+	})
+	_, ok := OrganizationFromContext(ctx)
+	require.False(t, ok)
+}
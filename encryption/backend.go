@@ -0,0 +1,205 @@
+package encryption
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+
+	"github.com/clerk/clerk-sdk-go/v2"
+	"github.com/clerk/clerk-sdk-go/v2/organization"
+	"github.com/clerk/clerk-sdk-go/v2/user"
+)
+
+// NewBackend returns a clerk.Backend which encrypts private metadata
+// fields before user and organization create, update and metadata
+// update requests are sent, and decrypts private metadata of users
+// and organizations in responses.
+//
+// Encrypted values are bound to the resource ID, which is not known
+// before a user or organization is created. Private metadata with
+// fields to encrypt are therefore omitted from create requests and
+// set with a metadata update right after the resource is created.
+//
+//	backend := encryption.NewBackend(clerk.GetBackend(), encryptor)
+//	users := &user.Client{Backend: backend}
+func NewBackend(backend clerk.Backend, encryptor *Encryptor) clerk.Backend {
+	return &encryptionBackend{
+		backend:   backend,
+		encryptor: encryptor,
+	}
+}
+
+type encryptionBackend struct {
+	backend   clerk.Backend
+	encryptor *Encryptor
+}
+
+// Call encrypts the request params, calls the underlying Backend and
+// decrypts the response.
+func (b *encryptionBackend) Call(ctx context.Context, req *clerk.APIRequest, resource clerk.ResponseReader) error {
+	req, deferred, err := b.encryptParams(ctx, req)
+	if err != nil {
+		return err
+	}
+	err = b.backend.Call(ctx, req, resource)
+	if err != nil {
+		return err
+	}
+	if deferred != nil {
+		err = b.updatePrivateMetadata(ctx, resource, *deferred)
+		if err != nil {
+			return err
+		}
+	}
+	return b.decryptResource(ctx, resource)
+}
+
+// Returns a copy of the request with encrypted params, leaving the
+// caller's params intact. For create requests with fields to
+// encrypt, the private metadata are removed from the request and
+// returned, so that they can be set once the resource ID is known.
+func (b *encryptionBackend) encryptParams(ctx context.Context, req *clerk.APIRequest) (*clerk.APIRequest, *json.RawMessage, error) {
+	var privateMetadata **json.RawMessage
+	var params clerk.Params
+	create := false
+	switch p := req.Params.(type) {
+	case *user.CreateParams:
+		cp := *p
+		privateMetadata, params, create = &cp.PrivateMetadata, &cp, true
+	case *user.UpdateParams:
+		cp := *p
+		privateMetadata, params = &cp.PrivateMetadata, &cp
+	case *user.UpdateMetadataParams:
+		cp := *p
+		privateMetadata, params = &cp.PrivateMetadata, &cp
+	case *organization.CreateParams:
+		cp := *p
+		privateMetadata, params, create = &cp.PrivateMetadata, &cp, true
+	case *organization.UpdateParams:
+		cp := *p
+		privateMetadata, params = &cp.PrivateMetadata, &cp
+	case *organization.UpdateMetadataParams:
+		cp := *p
+		privateMetadata, params = &cp.PrivateMetadata, &cp
+	default:
+		return req, nil, nil
+	}
+	if *privateMetadata == nil {
+		return req, nil, nil
+	}
+
+	if create {
+		hasFields, err := b.encryptor.hasFields(**privateMetadata)
+		if err != nil || !hasFields {
+			return req, nil, err
+		}
+		deferred := *privateMetadata
+		*privateMetadata = nil
+		createReq := *req
+		createReq.SetParams(params)
+		return &createReq, deferred, nil
+	}
+
+	// Update requests have paths like /users/{id} or
+	// /users/{id}/metadata.
+	segments := strings.Split(strings.Trim(req.Path, "/"), "/")
+	if len(segments) < 2 {
+		return nil, nil, fmt.Errorf("encryption: missing resource ID in %s", req.Path)
+	}
+	encrypted, err := b.encryptor.Encrypt(ctx, segments[1], **privateMetadata)
+	if err != nil {
+		return nil, nil, err
+	}
+	*privateMetadata = &encrypted
+	encryptedReq := *req
+	encryptedReq.SetParams(params)
+	return &encryptedReq, nil, nil
+}
+
+// Encrypts the private metadata for the created resource and sets
+// them with a metadata update. The resource is replaced with the
+// updated one.
+func (b *encryptionBackend) updatePrivateMetadata(ctx context.Context, resource clerk.ResponseReader, privateMetadata json.RawMessage) error {
+	var path, id string
+	var params clerk.Params
+	var encrypted json.RawMessage
+	var err error
+	switch r := resource.(type) {
+	case *clerk.User:
+		path, id = "/users", r.ID
+		encrypted, err = b.encryptor.Encrypt(ctx, id, privateMetadata)
+		params = &user.UpdateMetadataParams{PrivateMetadata: &encrypted}
+	case *clerk.Organization:
+		path, id = "/organizations", r.ID
+		encrypted, err = b.encryptor.Encrypt(ctx, id, privateMetadata)
+		params = &organization.UpdateMetadataParams{PrivateMetadata: &encrypted}
+	default:
+		return fmt.Errorf("encryption: unexpected resource %T", resource)
+	}
+	if err != nil {
+		return err
+	}
+	path, err = clerk.JoinPath(path, id, "/metadata")
+	if err != nil {
+		return err
+	}
+	req := clerk.NewAPIRequest(http.MethodPatch, path)
+	req.SetParams(params)
+	return b.backend.Call(ctx, req, resource)
+}
+
+func (b *encryptionBackend) decryptResource(ctx context.Context, resource clerk.ResponseReader) error {
+	switch r := resource.(type) {
+	case *clerk.User:
+		return b.encryptor.DecryptUser(ctx, r)
+	case *clerk.UserList:
+		for _, u := range r.Users {
+			if err := b.encryptor.DecryptUser(ctx, u); err != nil {
+				return err
+			}
+		}
+	case *clerk.Organization:
+		return b.encryptor.DecryptOrganization(ctx, r)
+	case *clerk.OrganizationList:
+		for _, org := range r.Organizations {
+			if err := b.encryptor.DecryptOrganization(ctx, org); err != nil {
+				return err
+			}
+		}
+	default:
+		// Some lists are decoded into slice types of the resource
+		// packages, like the pages of user.List.
+		if users, ok := resourceSlice[*clerk.User](resource); ok {
+			for _, u := range users {
+				if err := b.encryptor.DecryptUser(ctx, u); err != nil {
+					return err
+				}
+			}
+		}
+		if orgs, ok := resourceSlice[*clerk.Organization](resource); ok {
+			for _, org := range orgs {
+				if err := b.encryptor.DecryptOrganization(ctx, org); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// Returns the elements of the resource if it's a pointer to a slice
+// type of T elements.
+func resourceSlice[T any](resource clerk.ResponseReader) ([]T, bool) {
+	v := reflect.ValueOf(resource)
+	if v.Kind() != reflect.Pointer || v.Elem().Kind() != reflect.Slice {
+		return nil, false
+	}
+	sliceType := reflect.TypeOf([]T(nil))
+	if !v.Elem().Type().ConvertibleTo(sliceType) {
+		return nil, false
+	}
+	return v.Elem().Convert(sliceType).Interface().([]T), true
+}
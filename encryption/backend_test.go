@@ -0,0 +1,127 @@
+package encryption
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/clerk/clerk-sdk-go/v2"
+	"github.com/clerk/clerk-sdk-go/v2/user"
+	"github.com/stretchr/testify/require"
+)
+
+// A fake Clerk API for a single user, which stores whatever private
+// metadata it receives and records the requests.
+type userAPI struct {
+	mu       sync.Mutex
+	requests []string
+	stored   json.RawMessage
+}
+
+func (api *userAPI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	api.mu.Lock()
+	defer api.mu.Unlock()
+	api.requests = append(api.requests, r.Method+" "+r.URL.Path)
+	if r.Method != http.MethodGet {
+		body, _ := io.ReadAll(r.Body)
+		params := map[string]json.RawMessage{}
+		_ = json.Unmarshal(body, &params)
+		api.stored = params["private_metadata"]
+	}
+	var res any = map[string]any{
+		"id":               "user_123",
+		"private_metadata": api.stored,
+	}
+	if r.Method == http.MethodGet && r.URL.Path == "/users" {
+		res = []any{res}
+	}
+	_ = json.NewEncoder(w).Encode(res)
+}
+
+// Returns a user client which encrypts the "ssn" field with the
+// encryption Backend, the fake Clerk API it talks to and the
+// Encryptor.
+func newTestUserClient(t *testing.T) (*user.Client, *userAPI, *Encryptor) {
+	t.Helper()
+	api := &userAPI{}
+	clerkAPI := httptest.NewServer(api)
+	t.Cleanup(clerkAPI.Close)
+
+	config := &clerk.ClientConfig{}
+	config.HTTPClient = clerkAPI.Client()
+	config.URL = &clerkAPI.URL
+	encryptor := NewEncryptor(&EncryptorParams{
+		KeyProvider: newTestKeyProvider(),
+		Fields:      []string{"ssn"},
+	})
+	client := &user.Client{
+		Backend: NewBackend(clerk.NewBackend(&config.BackendConfig), encryptor),
+	}
+	return client, api, encryptor
+}
+
+func TestBackend(t *testing.T) {
+	t.Parallel()
+	client, api, _ := newTestUserClient(t)
+
+	metadata := json.RawMessage(`{"ssn":"123-45-6789","plan":"pro"}`)
+	params := &user.UpdateMetadataParams{
+		PrivateMetadata: &metadata,
+	}
+	usr, err := client.UpdateMetadata(context.Background(), "user_123", params)
+	require.NoError(t, err)
+	// The caller's params are not modified.
+	require.Equal(t, `{"ssn":"123-45-6789","plan":"pro"}`, string(*params.PrivateMetadata))
+	// The Clerk API receives the encrypted value.
+	require.NotContains(t, string(api.stored), "123-45-6789")
+	require.Contains(t, string(api.stored), "clerk_enc:v1:")
+	// The response is decrypted.
+	require.JSONEq(t, string(metadata), string(usr.PrivateMetadata))
+
+	usr, err = client.Get(context.Background(), "user_123")
+	require.NoError(t, err)
+	require.JSONEq(t, string(metadata), string(usr.PrivateMetadata))
+}
+
+func TestBackend_Create(t *testing.T) {
+	t.Parallel()
+	client, api, encryptor := newTestUserClient(t)
+
+	// The private metadata are set once the user ID is known, since
+	// encrypted values are bound to the user.
+	metadata := json.RawMessage(`{"ssn":"123-45-6789","plan":"pro"}`)
+	usr, err := client.Create(context.Background(), &user.CreateParams{
+		PrivateMetadata: &metadata,
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{"POST /users", "PATCH /users/user_123/metadata"}, api.requests)
+	require.NotContains(t, string(api.stored), "123-45-6789")
+	require.JSONEq(t, string(metadata), string(usr.PrivateMetadata))
+
+	decrypted, err := encryptor.Decrypt(context.Background(), "user_123", api.stored)
+	require.NoError(t, err)
+	require.JSONEq(t, string(metadata), string(decrypted))
+	_, err = encryptor.Decrypt(context.Background(), "user_456", api.stored)
+	require.Error(t, err)
+}
+
+func TestBackend_List(t *testing.T) {
+	t.Parallel()
+	client, api, _ := newTestUserClient(t)
+
+	metadata := json.RawMessage(`{"ssn":"123-45-6789","plan":"pro"}`)
+	_, err := client.UpdateMetadata(context.Background(), "user_123", &user.UpdateMetadataParams{
+		PrivateMetadata: &metadata,
+	})
+	require.NoError(t, err)
+	require.Contains(t, string(api.stored), "clerk_enc:v1:")
+
+	list, err := client.List(context.Background(), &user.ListParams{SkipTotalCount: true})
+	require.NoError(t, err)
+	require.Equal(t, 1, len(list.Users))
+	require.JSONEq(t, string(metadata), string(list.Users[0].PrivateMetadata))
+}
@@ -0,0 +1,367 @@
+// Package encryption provides client-side envelope encryption for
+// fields of private metadata.
+//
+// Each field value is encrypted with AES-GCM using a random data
+// key. The data key is encrypted with a key encryption key that is
+// supplied by a KeyProvider and is stored next to the ciphertext,
+// together with the ID of the key encryption key. Keys can be
+// rotated by adding a new primary key to the KeyProvider, while
+// keeping the old ones around for decryption.
+//
+// Encrypted values are stored as strings, so that they are replaced
+// and not deep-merged by metadata updates. They are bound to the ID
+// of the user or organization and to their field, so they cannot be
+// decrypted after being copied to another resource or field.
+package encryption
+
+import (
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/clerk/clerk-sdk-go/v2"
+)
+
+// Prefix of encrypted field values.
+const envelopePrefix = "clerk_enc:v1:"
+
+const dataKeySize = 32
+
+// ErrKeyNotFound is returned by a KeyProvider when there's no key
+// with the requested ID.
+var ErrKeyNotFound = errors.New("encryption: key not found")
+
+// KeyProvider supplies the key encryption keys. Keys must be 16, 24
+// or 32 bytes long, to select AES-128, AES-192 or AES-256.
+type KeyProvider interface {
+	// PrimaryKey returns the key that is used to encrypt new values
+	// and its ID.
+	PrimaryKey(ctx context.Context) (keyID string, key []byte, err error)
+	// Key returns the key with the provided ID, which is used to
+	// decrypt values.
+	Key(ctx context.Context, keyID string) ([]byte, error)
+}
+
+// StaticKeyProvider is a KeyProvider for a fixed set of keys.
+type StaticKeyProvider struct {
+	// PrimaryKeyID is the ID of the key that is used for encryption.
+	PrimaryKeyID string
+	// Keys holds all available keys by their ID.
+	Keys map[string][]byte
+}
+
+// PrimaryKey returns the key with the PrimaryKeyID.
+func (p *StaticKeyProvider) PrimaryKey(ctx context.Context) (string, []byte, error) {
+	key, err := p.Key(ctx, p.PrimaryKeyID)
+	return p.PrimaryKeyID, key, err
+}
+
+// Key returns the key with the provided ID.
+func (p *StaticKeyProvider) Key(_ context.Context, keyID string) ([]byte, error) {
+	key, ok := p.Keys[keyID]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrKeyNotFound, keyID)
+	}
+	return key, nil
+}
+
+type EncryptorParams struct {
+	// KeyProvider supplies the key encryption keys. Required.
+	KeyProvider KeyProvider
+	// Fields holds the paths of the private metadata fields that
+	// will be encrypted. Nested fields are separated by dots, for
+	// example "billing.iban".
+	Fields []string
+}
+
+// Encryptor encrypts and decrypts private metadata fields.
+type Encryptor struct {
+	keyProvider KeyProvider
+	fields      [][]string
+}
+
+// NewEncryptor returns an Encryptor configured with the provided
+// params.
+func NewEncryptor(params *EncryptorParams) *Encryptor {
+	encryptor := &Encryptor{
+		keyProvider: params.KeyProvider,
+	}
+	for _, field := range params.Fields {
+		encryptor.fields = append(encryptor.fields, strings.Split(field, "."))
+	}
+	return encryptor
+}
+
+// Encrypt encrypts the configured fields of the metadata of the
+// resource with the provided ID. Fields that are missing, null or
+// already encrypted are left untouched, so partial documents for
+// metadata merge updates can be encrypted too.
+func (e *Encryptor) Encrypt(ctx context.Context, resourceID string, metadata json.RawMessage) (json.RawMessage, error) {
+	if isEmpty(metadata) || len(e.fields) == 0 {
+		return metadata, nil
+	}
+	doc := map[string]any{}
+	err := decode(metadata, &doc)
+	if err != nil {
+		return nil, err
+	}
+
+	var keyID string
+	var key []byte
+	changed := false
+	for _, field := range e.fields {
+		parent, name, ok := lookupParent(doc, field)
+		if !ok {
+			continue
+		}
+		value, ok := parent[name]
+		if !ok || value == nil || isEnvelope(value) {
+			continue
+		}
+		if key == nil {
+			keyID, key, err = e.keyProvider.PrimaryKey(ctx)
+			if err != nil {
+				return nil, err
+			}
+		}
+		parent[name], err = encryptValue(keyID, key, additionalData(resourceID, strings.Join(field, ".")), value)
+		if err != nil {
+			return nil, err
+		}
+		changed = true
+	}
+	if !changed {
+		return metadata, nil
+	}
+	return json.Marshal(doc)
+}
+
+// Decrypt decrypts all encrypted values in the metadata of the
+// resource with the provided ID, regardless of the configured fields.
+func (e *Encryptor) Decrypt(ctx context.Context, resourceID string, metadata json.RawMessage) (json.RawMessage, error) {
+	if isEmpty(metadata) || !bytes.Contains(metadata, []byte(envelopePrefix)) {
+		return metadata, nil
+	}
+	doc := map[string]any{}
+	err := decode(metadata, &doc)
+	if err != nil {
+		return nil, err
+	}
+	err = e.decryptObject(ctx, resourceID, doc, "")
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(doc)
+}
+
+func (e *Encryptor) decryptObject(ctx context.Context, resourceID string, doc map[string]any, path string) error {
+	for name, value := range doc {
+		fieldPath := name
+		if path != "" {
+			fieldPath = path + "." + name
+		}
+		switch v := value.(type) {
+		case string:
+			if !strings.HasPrefix(v, envelopePrefix) {
+				continue
+			}
+			decrypted, err := e.decryptValue(ctx, resourceID, fieldPath, v)
+			if err != nil {
+				return err
+			}
+			doc[name] = decrypted
+		case map[string]any:
+			err := e.decryptObject(ctx, resourceID, v, fieldPath)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// DecryptUser decrypts the user's private metadata in place.
+func (e *Encryptor) DecryptUser(ctx context.Context, user *clerk.User) error {
+	if user == nil {
+		return nil
+	}
+	var err error
+	user.PrivateMetadata, err = e.Decrypt(ctx, user.ID, user.PrivateMetadata)
+	return err
+}
+
+// DecryptOrganization decrypts the organization's private metadata
+// in place.
+func (e *Encryptor) DecryptOrganization(ctx context.Context, organization *clerk.Organization) error {
+	if organization == nil {
+		return nil
+	}
+	var err error
+	organization.PrivateMetadata, err = e.Decrypt(ctx, organization.ID, organization.PrivateMetadata)
+	return err
+}
+
+// Returns whether any of the configured fields is set in the
+// metadata, so that it would be encrypted by Encrypt.
+func (e *Encryptor) hasFields(metadata json.RawMessage) (bool, error) {
+	if isEmpty(metadata) || len(e.fields) == 0 {
+		return false, nil
+	}
+	doc := map[string]any{}
+	err := decode(metadata, &doc)
+	if err != nil {
+		return false, err
+	}
+	for _, field := range e.fields {
+		parent, name, ok := lookupParent(doc, field)
+		if !ok {
+			continue
+		}
+		if value, ok := parent[name]; ok && value != nil && !isEnvelope(value) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// The additional authenticated data of an encrypted value binds it
+// to the resource and the field path.
+func additionalData(resourceID, path string) []byte {
+	return []byte(resourceID + "\x00" + path)
+}
+
+// Encrypted values have the format
+// clerk_enc:v1:<key ID>:<encrypted data key>:<ciphertext>, with all
+// parts encoded in unpadded base64url. The resource ID and field
+// path are used as additional authenticated data, so values cannot
+// be moved between resources or fields.
+func encryptValue(keyID string, key []byte, aad []byte, value any) (string, error) {
+	plaintext, err := json.Marshal(value)
+	if err != nil {
+		return "", err
+	}
+	dataKey := make([]byte, dataKeySize)
+	if _, err := io.ReadFull(rand.Reader, dataKey); err != nil {
+		return "", err
+	}
+	encryptedKey, err := seal(key, dataKey, []byte(keyID))
+	if err != nil {
+		return "", err
+	}
+	ciphertext, err := seal(dataKey, plaintext, aad)
+	if err != nil {
+		return "", err
+	}
+	enc := base64.RawURLEncoding
+	return envelopePrefix + enc.EncodeToString([]byte(keyID)) + ":" +
+		enc.EncodeToString(encryptedKey) + ":" +
+		enc.EncodeToString(ciphertext), nil
+}
+
+func (e *Encryptor) decryptValue(ctx context.Context, resourceID, path, envelope string) (any, error) {
+	parts := strings.Split(strings.TrimPrefix(envelope, envelopePrefix), ":")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("encryption: invalid encrypted value for %s", path)
+	}
+	decoded := make([][]byte, len(parts))
+	for i, part := range parts {
+		var err error
+		decoded[i], err = base64.RawURLEncoding.DecodeString(part)
+		if err != nil {
+			return nil, fmt.Errorf("encryption: invalid encrypted value for %s: %w", path, err)
+		}
+	}
+	keyID := string(decoded[0])
+	key, err := e.keyProvider.Key(ctx, keyID)
+	if err != nil {
+		return nil, err
+	}
+	dataKey, err := open(key, decoded[1], []byte(keyID))
+	if err != nil {
+		return nil, fmt.Errorf("encryption: decrypt data key for %s: %w", path, err)
+	}
+	plaintext, err := open(dataKey, decoded[2], additionalData(resourceID, path))
+	if err != nil {
+		return nil, fmt.Errorf("encryption: decrypt %s: %w", path, err)
+	}
+	var value any
+	err = decode(plaintext, &value)
+	return value, err
+}
+
+// Encrypts the plaintext with AES-GCM and prepends the nonce.
+func seal(key, plaintext, additionalData []byte) ([]byte, error) {
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+	return aead.Seal(nonce, nonce, plaintext, additionalData), nil
+}
+
+func open(key, ciphertext, additionalData []byte) ([]byte, error) {
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(ciphertext) < aead.NonceSize() {
+		return nil, fmt.Errorf("ciphertext too short")
+	}
+	nonce, ciphertext := ciphertext[:aead.NonceSize()], ciphertext[aead.NonceSize():]
+	return aead.Open(nil, nonce, ciphertext, additionalData)
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// Returns the object that holds the field and the field name, if the
+// field's parents exist.
+func lookupParent(doc map[string]any, field []string) (map[string]any, string, bool) {
+	parent := doc
+	for _, name := range field[:len(field)-1] {
+		child, ok := parent[name].(map[string]any)
+		if !ok {
+			return nil, "", false
+		}
+		parent = child
+	}
+	return parent, field[len(field)-1], true
+}
+
+func isEnvelope(value any) bool {
+	s, ok := value.(string)
+	return ok && strings.HasPrefix(s, envelopePrefix)
+}
+
+func isEmpty(data json.RawMessage) bool {
+	data = bytes.TrimSpace(data)
+	return len(data) == 0 || bytes.Equal(data, []byte("null"))
+}
+
+// Decodes JSON data. Numbers are decoded as json.Number, so that
+// large integers are not rounded when the data are encoded again.
+func decode(data []byte, v any) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	err := decoder.Decode(v)
+	if err != nil {
+		return fmt.Errorf("encryption: decode metadata: %w", err)
+	}
+	return nil
+}
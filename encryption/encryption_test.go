@@ -0,0 +1,124 @@
+package encryption
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func newTestKeyProvider() *StaticKeyProvider {
+	return &StaticKeyProvider{
+		PrimaryKeyID: "key_1",
+		Keys: map[string][]byte{
+			"key_1": []byte("0123456789abcdef0123456789abcdef"),
+			"key_2": []byte("fedcba9876543210fedcba9876543210"),
+		},
+	}
+}
+
+func TestEncryptor(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	keyProvider := newTestKeyProvider()
+	encryptor := NewEncryptor(&EncryptorParams{
+		KeyProvider: keyProvider,
+		Fields:      []string{"ssn", "billing.iban", "missing.field"},
+	})
+
+	metadata := json.RawMessage(`{"ssn":"123-45-6789","billing":{"iban":{"country":"DE","number":"123"},"plan":"pro"},"visible":true}`)
+	encrypted, err := encryptor.Encrypt(ctx, "user_123", metadata)
+	require.NoError(t, err)
+	require.NotContains(t, string(encrypted), "123-45-6789")
+	require.NotContains(t, string(encrypted), `"country"`)
+	require.Contains(t, string(encrypted), `"plan":"pro"`)
+	require.Contains(t, string(encrypted), `"visible":true`)
+
+	// Encrypting again leaves the encrypted values untouched.
+	reencrypted, err := encryptor.Encrypt(ctx, "user_123", encrypted)
+	require.NoError(t, err)
+	require.Equal(t, string(encrypted), string(reencrypted))
+
+	decrypted, err := encryptor.Decrypt(ctx, "user_123", encrypted)
+	require.NoError(t, err)
+	require.JSONEq(t, string(metadata), string(decrypted))
+
+	// After rotating the primary key, values encrypted with the old
+	// key can still be decrypted.
+	keyProvider.PrimaryKeyID = "key_2"
+	rotated, err := encryptor.Encrypt(ctx, "user_123", json.RawMessage(`{"ssn":"987-65-4321"}`))
+	require.NoError(t, err)
+	decrypted, err = encryptor.Decrypt(ctx, "user_123", rotated)
+	require.NoError(t, err)
+	require.JSONEq(t, `{"ssn":"987-65-4321"}`, string(decrypted))
+	decrypted, err = encryptor.Decrypt(ctx, "user_123", encrypted)
+	require.NoError(t, err)
+	require.JSONEq(t, string(metadata), string(decrypted))
+
+	// Values cannot be decrypted without the key.
+	delete(keyProvider.Keys, "key_1")
+	_, err = encryptor.Decrypt(ctx, "user_123", encrypted)
+	require.ErrorIs(t, err, ErrKeyNotFound)
+}
+
+func TestEncryptor_PartialMetadata(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	encryptor := NewEncryptor(&EncryptorParams{
+		KeyProvider: newTestKeyProvider(),
+		Fields:      []string{"ssn", "token"},
+	})
+	// Deleted and missing fields are not encrypted.
+	metadata := json.RawMessage(`{"ssn":null,"other":1}`)
+	encrypted, err := encryptor.Encrypt(ctx, "user_123", metadata)
+	require.NoError(t, err)
+	require.Equal(t, string(metadata), string(encrypted))
+
+	for _, empty := range []json.RawMessage{nil, json.RawMessage("null")} {
+		encrypted, err = encryptor.Encrypt(ctx, "user_123", empty)
+		require.NoError(t, err)
+		require.Equal(t, empty, encrypted)
+	}
+}
+
+func TestEncryptor_Tampering(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	encryptor := NewEncryptor(&EncryptorParams{
+		KeyProvider: newTestKeyProvider(),
+		Fields:      []string{"a"},
+	})
+	encrypted, err := encryptor.Encrypt(ctx, "user_123", json.RawMessage(`{"a":"secret"}`))
+	require.NoError(t, err)
+
+	// Encrypted values are bound to their field.
+	moved := strings.Replace(string(encrypted), `"a"`, `"b"`, 1)
+	_, err = encryptor.Decrypt(ctx, "user_123", json.RawMessage(moved))
+	require.Error(t, err)
+
+	// Encrypted values are bound to their resource.
+	_, err = encryptor.Decrypt(ctx, "user_456", encrypted)
+	require.Error(t, err)
+
+	_, err = encryptor.Decrypt(ctx, "user_123", json.RawMessage(`{"a":"clerk_enc:v1:invalid"}`))
+	require.Error(t, err)
+}
+
+func TestEncryptor_LargeIntegers(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	encryptor := NewEncryptor(&EncryptorParams{
+		KeyProvider: newTestKeyProvider(),
+		Fields:      []string{"account"},
+	})
+	// 2^53 + 1 can't be represented as a float64.
+	metadata := json.RawMessage(`{"account":9007199254740993,"id":9007199254740995}`)
+	encrypted, err := encryptor.Encrypt(ctx, "user_123", metadata)
+	require.NoError(t, err)
+	require.Contains(t, string(encrypted), `"id":9007199254740995`)
+	decrypted, err := encryptor.Decrypt(ctx, "user_123", encrypted)
+	require.NoError(t, err)
+	require.Equal(t, string(metadata), string(decrypted))
+}
@@ -5,11 +5,11 @@ go 1.19
 require (
 	github.com/go-jose/go-jose/v3 v3.0.3
 	github.com/stretchr/testify v1.8.2
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
 	github.com/davecgh/go-spew v1.1.1 // indirect
 	github.com/pmezard/go-difflib v1.0.0 // indirect
 	golang.org/x/crypto v0.19.0 // indirect
-	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
@@ -0,0 +1,262 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/clerk/clerk-sdk-go/v2"
+	"github.com/clerk/clerk-sdk-go/v2/jwt"
+)
+
+const (
+	// ConnectionProtocol is the WebSocket subprotocol which signifies
+	// that the next value in the Sec-WebSocket-Protocol header is the
+	// session token.
+	// Browsers can't set an Authorization header on WebSocket upgrades,
+	// but they can pass subprotocols.
+	//	new WebSocket(url, ["clerk.session_token", token])
+	ConnectionProtocol = "clerk.session_token"
+	// ConnectionTokenQueryParam is the name of the query string
+	// parameter that can hold the session token. Useful for
+	// EventSource (Server-Sent Events) requests.
+	ConnectionTokenQueryParam = "session_token"
+)
+
+// RequireConnectionAuthorization authorizes long-lived connections,
+// like WebSocket upgrades and Server-Sent Events requests.
+// The session token is read from the Authorization header, the
+// Sec-WebSocket-Protocol header, following the ConnectionProtocol
+// value, or the ConnectionTokenQueryParam query string parameter.
+// The token is verified in the same way as WithHeaderAuthorization
+// does and the same options apply.
+//
+// Requests without a valid session token are handled by the
+// AuthorizationFailureHandler. For valid tokens, the active session
+// claims and a *Connection are written to the http.Request context.
+// The request context is canceled when the session token expires,
+// unless the Connection is re-authenticated with a fresh token.
+func RequireConnectionAuthorization(opts ...AuthorizationOption) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			params, err := newAuthorizationParams(
+				append([]AuthorizationOption{AuthorizationJWTExtractor(connectionJWTExtractor)}, opts...)...,
+			)
+			if err != nil {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+
+			ctx, cancel := context.WithCancel(r.Context())
+			defer cancel()
+			conn := &Connection{
+				params: params,
+				cancel: cancel,
+			}
+			if hasConnectionProtocol(r) {
+				conn.protocol = ConnectionProtocol
+			}
+			claims, err := conn.verify(ctx, params.AuthorizationJWTExtractor(r))
+			if err != nil {
+				params.AuthorizationFailureHandler.ServeHTTP(w, r)
+				return
+			}
+			conn.setClaims(claims)
+			defer conn.stop()
+
+			ctx = clerk.ContextWithSessionClaims(ctx, claims)
+			ctx = context.WithValue(ctx, clerkConnection, conn)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+type connectionKey string
+
+const clerkConnection = connectionKey("clerkConnection")
+
+// ConnectionFromContext returns the authorized Connection from the
+// context. The Connection is available to handlers that are guarded
+// by the RequireConnectionAuthorization middleware.
+func ConnectionFromContext(ctx context.Context) (*Connection, bool) {
+	conn, ok := ctx.Value(clerkConnection).(*Connection)
+	return conn, ok
+}
+
+// Connection holds the authorization state of a long-lived
+// connection.
+// Session tokens are short-lived and expire long before connections
+// like WebSockets close. Clients can send a fresh session token over
+// the connection, which can then be passed to Reauthenticate in
+// order to keep the connection open.
+type Connection struct {
+	params   *AuthorizationParams
+	protocol string
+	cancel   context.CancelFunc
+
+	mu        sync.RWMutex
+	claims    *clerk.SessionClaims
+	expiresAt time.Time
+	stopTimer func() bool
+	expired   bool
+}
+
+// Claims returns the claims of the latest verified session token.
+func (c *Connection) Claims() *clerk.SessionClaims {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.claims
+}
+
+// ExpiresAt returns the expiration time of the latest verified
+// session token. The request context is canceled after this time,
+// plus any configured leeway.
+// The zero value means that the token doesn't expire.
+func (c *Connection) ExpiresAt() time.Time {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.expiresAt
+}
+
+// Protocol returns the WebSocket subprotocol that the server should
+// select in its handshake response. It is the ConnectionProtocol if
+// the session token was passed in the Sec-WebSocket-Protocol header,
+// or an empty string otherwise.
+func (c *Connection) Protocol() string {
+	return c.protocol
+}
+
+// Reauthenticate verifies a fresh session token for the connection.
+// The token must belong to the same session as the one that
+// authorized the connection. On success, the connection's claims are
+// replaced and its expiration is extended.
+// Reauthenticate returns an error if the connection has already
+// expired.
+func (c *Connection) Reauthenticate(ctx context.Context, token string) error {
+	claims, err := c.verify(ctx, token)
+	if err != nil {
+		return err
+	}
+	current := c.Claims()
+	if claims.SessionID != current.SessionID || claims.Subject != current.Subject {
+		return fmt.Errorf("session token belongs to a different session %s", claims.SessionID)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.expired {
+		return fmt.Errorf("connection has expired")
+	}
+	c.setClaimsLocked(claims)
+	return nil
+}
+
+// Verify the token using the connection's authorization params.
+func (c *Connection) verify(ctx context.Context, token string) (*clerk.SessionClaims, error) {
+	if token == "" {
+		return nil, fmt.Errorf("missing session token")
+	}
+	decoded, err := jwt.Decode(ctx, &jwt.DecodeParams{Token: token})
+	if err != nil {
+		return nil, err
+	}
+	return verifyToken(ctx, c.params, token, decoded.KeyID)
+}
+
+func (c *Connection) setClaims(claims *clerk.SessionClaims) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.setClaimsLocked(claims)
+}
+
+// Sets the claims and schedules the connection's expiration
+// based on the token's exp claim. Must be called with the lock held.
+func (c *Connection) setClaimsLocked(claims *clerk.SessionClaims) {
+	c.claims = claims
+	if c.stopTimer != nil {
+		c.stopTimer()
+		c.stopTimer = nil
+	}
+	c.expiresAt = time.Time{}
+	if claims.Expiry == nil {
+		return
+	}
+	c.expiresAt = time.Unix(*claims.Expiry, 0).UTC()
+	d := c.expiresAt.Add(c.params.Leeway).Sub(c.params.Clock.Now().UTC())
+	c.stopTimer = afterFunc(c.params.Clock, d, c.expire)
+}
+
+// Clocks that can schedule functions, like the clerktest.Clock,
+// are used for the expiration timer, so that expiration follows the
+// clock's time.
+type timerClock interface {
+	AfterFunc(d time.Duration, f func()) (stop func() bool)
+}
+
+// Calls f after the duration d has elapsed on the clock and returns
+// a function that stops the call.
+func afterFunc(clock clerk.Clock, d time.Duration, f func()) func() bool {
+	if clock, ok := clock.(timerClock); ok {
+		return clock.AfterFunc(d, f)
+	}
+	return time.AfterFunc(d, f).Stop
+}
+
+func (c *Connection) expire() {
+	c.mu.Lock()
+	c.expired = true
+	c.mu.Unlock()
+	c.cancel()
+}
+
+func (c *Connection) stop() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.stopTimer != nil {
+		c.stopTimer()
+	}
+}
+
+// Extracts the session token from the Authorization header, the
+// Sec-WebSocket-Protocol header or the query string, in that order.
+func connectionJWTExtractor(r *http.Request) string {
+	token := defaultAuthorizationJWTExtractor(r)
+	if token != "" {
+		return token
+	}
+	protocols := websocketProtocols(r)
+	for i, protocol := range protocols {
+		if protocol == ConnectionProtocol && i+1 < len(protocols) {
+			return protocols[i+1]
+		}
+	}
+	return r.URL.Query().Get(ConnectionTokenQueryParam)
+}
+
+func hasConnectionProtocol(r *http.Request) bool {
+	for _, protocol := range websocketProtocols(r) {
+		if protocol == ConnectionProtocol {
+			return true
+		}
+	}
+	return false
+}
+
+// Returns all the values of the Sec-WebSocket-Protocol header. The
+// header can appear multiple times and each occurrence can hold a
+// comma separated list of values.
+func websocketProtocols(r *http.Request) []string {
+	var protocols []string
+	for _, header := range r.Header.Values("Sec-WebSocket-Protocol") {
+		for _, protocol := range strings.Split(header, ",") {
+			protocol = strings.TrimSpace(protocol)
+			if protocol != "" {
+				protocols = append(protocols, protocol)
+			}
+		}
+	}
+	return protocols
+}
@@ -0,0 +1,159 @@
+package http
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/clerk/clerk-sdk-go/v2"
+	"github.com/clerk/clerk-sdk-go/v2/clerktest"
+	"github.com/go-jose/go-jose/v3"
+	"github.com/go-jose/go-jose/v3/jwt"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequireConnectionAuthorization(t *testing.T) {
+	token, pubKey := clerktest.GenerateJWT(t, map[string]any{
+		"sid": "sess_123",
+		"sub": "user_123",
+		"iss": "https://clerk.example.com",
+		"exp": time.Now().Add(time.Hour).Unix(),
+	}, "kid")
+	middleware := RequireConnectionAuthorization(JSONWebKey(publicKeyToPEM(t, pubKey)))
+	ts := httptest.NewServer(middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, ok := ConnectionFromContext(r.Context())
+		require.True(t, ok)
+		require.Equal(t, "sess_123", conn.Claims().SessionID)
+		require.False(t, conn.ExpiresAt().IsZero())
+		claims, ok := clerk.SessionClaimsFromContext(r.Context())
+		require.True(t, ok)
+		require.Equal(t, "user_123", claims.Subject)
+		_, err := w.Write([]byte(conn.Protocol()))
+		require.NoError(t, err)
+	})))
+	defer ts.Close()
+
+	// Request without a session token
+	req, err := http.NewRequest(http.MethodGet, ts.URL, nil)
+	require.NoError(t, err)
+	res, err := ts.Client().Do(req)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusUnauthorized, res.StatusCode)
+
+	// Request with an invalid token in the query string
+	req, err = http.NewRequest(http.MethodGet, ts.URL+"?session_token=whatever", nil)
+	require.NoError(t, err)
+	res, err = ts.Client().Do(req)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusUnauthorized, res.StatusCode)
+
+	// Request with the token in the query string
+	req, err = http.NewRequest(http.MethodGet, ts.URL+"?session_token="+token, nil)
+	require.NoError(t, err)
+	res, err = ts.Client().Do(req)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, res.StatusCode)
+
+	// Request with the token in the Sec-WebSocket-Protocol header
+	req, err = http.NewRequest(http.MethodGet, ts.URL, nil)
+	require.NoError(t, err)
+	req.Header.Set("Sec-WebSocket-Protocol", "chat, clerk.session_token, "+token)
+	res, err = ts.Client().Do(req)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, res.StatusCode)
+	body, err := io.ReadAll(res.Body)
+	require.NoError(t, err)
+	require.Equal(t, ConnectionProtocol, string(body))
+}
+
+func TestRequireConnectionAuthorization_Expiration(t *testing.T) {
+	privKey, pubKey := generateKeyPair(t)
+	newToken := func(claims map[string]any) string {
+		return signToken(t, privKey, claims)
+	}
+	clock := clerktest.NewClockAt(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	token := newToken(map[string]any{
+		"sid": "sess_123",
+		"iss": "https://clerk.example.com",
+		"exp": clock.Now().Add(time.Hour).Unix(),
+	})
+
+	done := make(chan struct{})
+	middleware := RequireConnectionAuthorization(JSONWebKey(publicKeyToPEM(t, pubKey)), Clock(clock))
+	handler := middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		defer close(done)
+		conn, ok := ConnectionFromContext(r.Context())
+		require.True(t, ok)
+
+		// Tokens for a different session are rejected.
+		err := conn.Reauthenticate(r.Context(), newToken(map[string]any{
+			"sid": "sess_456",
+			"iss": "https://clerk.example.com",
+			"exp": clock.Now().Add(2 * time.Hour).Unix(),
+		}))
+		require.Error(t, err)
+
+		// Re-authenticate with a fresh token, right before the first
+		// token expires.
+		clock.Advance(59 * time.Minute)
+		require.NoError(t, r.Context().Err())
+		expiresAt := clock.Now().Add(time.Hour)
+		err = conn.Reauthenticate(r.Context(), newToken(map[string]any{
+			"sid": "sess_123",
+			"iss": "https://clerk.example.com",
+			"exp": expiresAt.Unix(),
+		}))
+		require.NoError(t, err)
+		require.Equal(t, expiresAt, conn.ExpiresAt())
+
+		// The expiration of the first token doesn't cancel the
+		// connection anymore, the expiration of the fresh token does.
+		clock.Advance(59 * time.Minute)
+		require.NoError(t, r.Context().Err())
+		clock.Advance(time.Minute)
+		select {
+		case <-r.Context().Done():
+		case <-time.After(5 * time.Second):
+			t.Fatal("context was not canceled on token expiration")
+		}
+
+		// Expired connections cannot be re-authenticated.
+		err = conn.Reauthenticate(context.Background(), newToken(map[string]any{
+			"sid": "sess_123",
+			"iss": "https://clerk.example.com",
+			"exp": clock.Now().Add(time.Hour).Unix(),
+		}))
+		require.ErrorContains(t, err, "connection has expired")
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/?session_token="+token, nil)
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+	<-done
+}
+
+// Generates an RSA key pair, so that multiple tokens can be signed
+// with the same key.
+func generateKeyPair(t *testing.T) (*rsa.PrivateKey, crypto.PublicKey) {
+	t.Helper()
+	privKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	return privKey, privKey.Public()
+}
+
+func signToken(t *testing.T, privKey *rsa.PrivateKey, claims map[string]any) string {
+	t.Helper()
+	signer, err := jose.NewSigner(
+		jose.SigningKey{Algorithm: jose.RS256, Key: privKey},
+		(&jose.SignerOptions{}).WithType("JWT").WithHeader("kid", "kid"),
+	)
+	require.NoError(t, err)
+	token, err := jwt.Signed(signer).Claims(claims).CompactSerialize()
+	require.NoError(t, err)
+	return token
+}
@@ -0,0 +1,57 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/clerk/clerk-sdk-go/v2"
+)
+
+// RequirePlan will respond with HTTP 403 Forbidden unless the active
+// session claims contain at least one of the provided plans.
+// The middleware needs the active session claims, so it must be used
+// after the WithHeaderAuthorization middleware.
+// See clerk.SessionClaims.HasPlan for the plan format.
+func RequirePlan(plans ...string) func(http.Handler) http.Handler {
+	return RequireEntitlement(func(claims *clerk.SessionClaims) bool {
+		for _, plan := range plans {
+			if claims.HasPlan(plan) {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+// RequireFeature will respond with HTTP 403 Forbidden unless the
+// active session claims contain all of the provided features.
+// The middleware needs the active session claims, so it must be used
+// after the WithHeaderAuthorization middleware.
+// See clerk.SessionClaims.HasFeature for the feature format.
+func RequireFeature(features ...string) func(http.Handler) http.Handler {
+	return RequireEntitlement(func(claims *clerk.SessionClaims) bool {
+		for _, feature := range features {
+			if !claims.HasFeature(feature) {
+				return false
+			}
+		}
+		return true
+	})
+}
+
+// RequireEntitlement will respond with HTTP 403 Forbidden unless the
+// provided check passes for the active session claims. Use it for
+// custom combinations of plan and feature checks.
+// The middleware needs the active session claims, so it must be used
+// after the WithHeaderAuthorization middleware.
+func RequireEntitlement(check func(*clerk.SessionClaims) bool) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			claims, ok := clerk.SessionClaimsFromContext(r.Context())
+			if !ok || claims == nil || !check(claims) {
+				w.WriteHeader(http.StatusForbidden)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,49 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/clerk/clerk-sdk-go/v2"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequirePlanAndFeature(t *testing.T) {
+	t.Parallel()
+	claims := &clerk.SessionClaims{}
+	err := json.Unmarshal([]byte(`{"sub":"user_123","org_id":"org_123","pla":"u:free,o:pro","fea":"o:teams,o:audit_logs,u:profile"}`), claims)
+	require.NoError(t, err)
+
+	ok := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	for _, tc := range []struct {
+		name       string
+		middleware func(http.Handler) http.Handler
+		want       int
+	}{
+		{name: "organization plan", middleware: RequirePlan("pro"), want: http.StatusOK},
+		{name: "any plan", middleware: RequirePlan("enterprise", "o:pro"), want: http.StatusOK},
+		{name: "unscoped user plan", middleware: RequirePlan("free"), want: http.StatusOK},
+		{name: "missing plan", middleware: RequirePlan("enterprise"), want: http.StatusForbidden},
+		{name: "organization scoped user plan", middleware: RequirePlan("o:free"), want: http.StatusForbidden},
+		{name: "user plan", middleware: RequirePlan("u:free"), want: http.StatusOK},
+		{name: "all features", middleware: RequireFeature("teams", "audit_logs"), want: http.StatusOK},
+		{name: "missing feature", middleware: RequireFeature("teams", "sso"), want: http.StatusForbidden},
+		{name: "user feature", middleware: RequireFeature("u:profile"), want: http.StatusOK},
+		{name: "unscoped user feature", middleware: RequireFeature("profile", "teams"), want: http.StatusOK},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			withSessionClaims(claims)(tc.middleware(ok)).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+			require.Equal(t, tc.want, w.Code)
+		})
+	}
+
+	// Requests without session claims are forbidden.
+	w := httptest.NewRecorder()
+	RequirePlan("pro")(ok).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+	require.Equal(t, http.StatusForbidden, w.Code)
+}
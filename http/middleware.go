@@ -35,25 +35,32 @@ func RequireHeaderAuthorization(opts ...AuthorizationOption) func(http.Handler)
 // The middleware uses Bearer authentication, so the Authorization header
 // is expected to have the following format:
 // Authorization: Bearer <token>
+// See the DevelopmentInstance option for the behavior on Clerk
+// development instances.
 func WithHeaderAuthorization(opts ...AuthorizationOption) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			params := &AuthorizationParams{}
-			for _, opt := range opts {
-				err := opt(params)
-				if err != nil {
-					w.WriteHeader(http.StatusUnauthorized)
-					return
-				}
+			params, err := newAuthorizationParams(opts...)
+			if err != nil {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
 			}
-			if params.Clock == nil {
-				params.Clock = clerk.NewClock()
+			isDevelopment := clerk.IsDevelopmentInstance()
+			if params.DevelopmentInstance != nil {
+				isDevelopment = *params.DevelopmentInstance
 			}
-			if params.AuthorizationFailureHandler == nil {
-				params.AuthorizationFailureHandler = http.HandlerFunc(defaultAuthorizationFailureHandler)
+			if isDevelopment {
+				r = withDevBrowserToken(w, r)
 			}
 			if params.AuthorizationJWTExtractor == nil {
 				params.AuthorizationJWTExtractor = defaultAuthorizationJWTExtractor
+				// Cookies are sent by the browser with cross-site
+				// requests too, so session cookies are only accepted
+				// if the token's authorized party is checked.
+				if isDevelopment && params.AuthorizedPartyHandler != nil && defaultAuthorizationJWTExtractor(r) == "" {
+					params.AuthorizationJWTExtractor = sessionCookieJWTExtractor
+					params.AuthorizedPartyHandler = requireAuthorizedParty(params.AuthorizedPartyHandler)
+				}
 			}
 
 			token := params.AuthorizationJWTExtractor(r)
@@ -66,15 +73,7 @@ func WithHeaderAuthorization(opts ...AuthorizationOption) func(http.Handler) htt
 				next.ServeHTTP(w, r)
 				return
 			}
-			if params.JWK == nil {
-				params.JWK, err = getJWK(r.Context(), params.JWKSClient, decoded.KeyID, params.Clock)
-				if err != nil {
-					params.AuthorizationFailureHandler.ServeHTTP(w, r)
-					return
-				}
-			}
-			params.Token = token
-			claims, err := jwt.Verify(r.Context(), &params.VerifyParams)
+			claims, err := verifyToken(r.Context(), params, token, decoded.KeyID)
 			if err != nil {
 				params.AuthorizationFailureHandler.ServeHTTP(w, r)
 				return
@@ -87,6 +86,53 @@ func WithHeaderAuthorization(opts ...AuthorizationOption) func(http.Handler) htt
 	}
 }
 
+// Applies the options and sets default values for the clock and
+// the authorization failure handler.
+func newAuthorizationParams(opts ...AuthorizationOption) (*AuthorizationParams, error) {
+	params := &AuthorizationParams{}
+	for _, opt := range opts {
+		err := opt(params)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if params.Clock == nil {
+		params.Clock = clerk.NewClock()
+	}
+	if params.AuthorizationFailureHandler == nil {
+		params.AuthorizationFailureHandler = http.HandlerFunc(defaultAuthorizationFailureHandler)
+	}
+	return params, nil
+}
+
+// Verifies the session token and returns its claims. The JSON Web
+// Key for the provided kid is retrieved with getJWK, unless a custom
+// key was set in the params.
+// If the session revocation check is enabled, the token's session
+// must also be active.
+func verifyToken(ctx context.Context, params *AuthorizationParams, token, kid string) (*clerk.SessionClaims, error) {
+	verifyParams := params.VerifyParams
+	verifyParams.Token = token
+	if verifyParams.JWK == nil {
+		jwk, err := getJWK(ctx, params.JWKSClient, kid, params.Clock)
+		if err != nil {
+			return nil, err
+		}
+		verifyParams.JWK = jwk
+	}
+	claims, err := jwt.Verify(ctx, &verifyParams)
+	if err != nil {
+		return nil, err
+	}
+	if params.sessionRevocationChecker != nil {
+		err = params.sessionRevocationChecker.Check(ctx, claims.SessionID, params.Clock.Now().UTC())
+		if err != nil {
+			return nil, err
+		}
+	}
+	return claims, nil
+}
+
 func defaultAuthorizationFailureHandler(w http.ResponseWriter, _ *http.Request) {
 	w.WriteHeader(http.StatusUnauthorized)
 }
@@ -96,6 +142,59 @@ func defaultAuthorizationJWTExtractor(r *http.Request) string {
 	return strings.TrimPrefix(authorization, "Bearer ")
 }
 
+const (
+	// Name of the cookie that holds the session token for browser
+	// requests.
+	sessionCookieName = "__session"
+	// Name of the query string parameter and cookie that hold the dev
+	// browser token for development instances.
+	devBrowserTokenName = "__clerk_db_jwt"
+)
+
+// Development instances don't use the production cookie model, so
+// browser page loads might not have an Authorization header. Reads
+// the session token from the session cookie instead.
+// Must only be used together with an authorized party check.
+func sessionCookieJWTExtractor(r *http.Request) string {
+	cookie, err := r.Cookie(sessionCookieName)
+	if err != nil {
+		return ""
+	}
+	return cookie.Value
+}
+
+// Wraps the authorized party handler so that tokens without an azp
+// claim are rejected.
+func requireAuthorizedParty(handler jwt.AuthorizedPartyHandler) jwt.AuthorizedPartyHandler {
+	return func(azp string) bool {
+		return azp != "" && handler(azp)
+	}
+}
+
+// Reads the dev browser token from the request query string or
+// cookies and adds it to the request context.
+// A token that is passed in the query string takes precedence and
+// gets propagated to a cookie, so that subsequent requests from the
+// same browser carry it.
+func withDevBrowserToken(w http.ResponseWriter, r *http.Request) *http.Request {
+	token := r.URL.Query().Get(devBrowserTokenName)
+	if token != "" {
+		http.SetCookie(w, &http.Cookie{
+			Name:     devBrowserTokenName,
+			Value:    token,
+			Path:     "/",
+			Secure:   r.TLS != nil,
+			SameSite: http.SameSiteLaxMode,
+		})
+	} else if cookie, err := r.Cookie(devBrowserTokenName); err == nil {
+		token = cookie.Value
+	}
+	if token == "" {
+		return r
+	}
+	return r.WithContext(clerk.ContextWithDevBrowserToken(r.Context(), token))
+}
+
 // Retrieve the JSON web key for the provided token from the JWKS set.
 // Tries a cached value first, but if there's no value or the entry
 // has expired, it will fetch the JWK set from the API and cache the
@@ -134,6 +233,14 @@ type AuthorizationParams struct {
 	// AuthorizationJWTExtractor is a custom function to extract the Clerk
 	// authorization JWT from the http.Request.
 	AuthorizationJWTExtractor func(r *http.Request) string
+	// DevelopmentInstance signifies that requests are authorized
+	// against a Clerk development instance. If it's not set, the
+	// instance type is inferred from the secret key that was set with
+	// clerk.SetKey.
+	DevelopmentInstance *bool
+
+	// Set with the SessionRevocationCheck option.
+	sessionRevocationChecker *sessionRevocationChecker
 }
 
 // AuthorizationOption is a functional parameter for configuring
@@ -195,6 +302,9 @@ func AuthorizedPartyMatches(parties ...string) AuthorizationOption {
 // authority for time related operations.
 // You can use a custom clock for testing purposes, or to
 // eliminate clock skew if your code runs on different servers.
+// Connections authorized with RequireConnectionAuthorization expire
+// on the clock's time if the clock has an AfterFunc method, like the
+// clerktest.Clock does.
 func Clock(c clerk.Clock) AuthorizationOption {
 	return func(params *AuthorizationParams) error {
 		params.Clock = c
@@ -263,6 +373,29 @@ func Satellite(isSatellite bool) AuthorizationOption {
 	}
 }
 
+// DevelopmentInstance can be used to signify that requests are
+// authorized against a Clerk development instance. By default, the
+// instance type is inferred from the secret key that was set with
+// clerk.SetKey. Development instance keys have the "sk_test_" prefix.
+//
+// For development instances the middleware reads the dev browser
+// token (__clerk_db_jwt) from the query string or cookies and makes
+// it available with clerk.DevBrowserTokenFromContext. A token found
+// in the query string is propagated to a cookie.
+// If an authorized party handler is set with the AuthorizedParty or
+// AuthorizedPartyMatches options and no custom
+// AuthorizationJWTExtractor is provided, the session token is read
+// from the __session cookie when the Authorization header is missing.
+// Tokens read from the cookie must have an azp claim. Without an
+// authorized party check only the Authorization header is accepted,
+// since cookies are also sent with cross-site requests.
+func DevelopmentInstance(isDevelopment bool) AuthorizationOption {
+	return func(params *AuthorizationParams) error {
+		params.DevelopmentInstance = clerk.Bool(isDevelopment)
+		return nil
+	}
+}
+
 // JSONWebKey allows to provide a custom JSON Web Key (JWK) based on
 // which the authorization JWT will be verified.
 // When verifying the authorization JWT without a custom key, the JWK
@@ -301,6 +434,10 @@ func JWKSClient(client *jwks.Client) AuthorizationOption {
 	}
 }
 
+// Expired entries are purged from caches when they grow above this
+// size.
+const cachePurgeSize = 10000
+
 // A cache to store JSON Web Keys.
 type jwkCache struct {
 	mu      sync.RWMutex
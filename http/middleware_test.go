@@ -1,7 +1,11 @@
 package http
 
 import (
+	"crypto"
+	"crypto/x509"
+	"encoding/pem"
 	"fmt"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -283,3 +287,146 @@ func TestAuthorizationJWTExtractor(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, http.StatusForbidden, res.StatusCode)
 }
+
+func TestWithHeaderAuthorization_DevelopmentInstance(t *testing.T) {
+	token, pubKey := clerktest.GenerateJWT(t, map[string]any{
+		"sid": "sess_123",
+		"sub": "user_123",
+		"iss": "https://foo-bar-13.clerk.accounts.dev",
+		"azp": "http://localhost:3000",
+	}, "kid")
+	middleware := WithHeaderAuthorization(
+		DevelopmentInstance(true),
+		AuthorizedPartyMatches("http://localhost:3000"),
+		JSONWebKey(publicKeyToPEM(t, pubKey)),
+	)
+	ts := httptest.NewServer(middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := clerk.SessionClaimsFromContext(r.Context())
+		require.True(t, ok)
+		require.Equal(t, "sess_123", claims.SessionID)
+		devBrowserToken, ok := clerk.DevBrowserTokenFromContext(r.Context())
+		require.True(t, ok)
+		_, err := w.Write([]byte(devBrowserToken))
+		require.NoError(t, err)
+	})))
+	defer ts.Close()
+
+	// The session token is read from the cookie and the dev browser
+	// token from the query string. The dev browser token is propagated
+	// to a cookie.
+	req, err := http.NewRequest(http.MethodGet, ts.URL+"?__clerk_db_jwt=db_jwt_query", nil)
+	require.NoError(t, err)
+	req.AddCookie(&http.Cookie{Name: "__session", Value: token})
+	res, err := ts.Client().Do(req)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, res.StatusCode)
+	body, err := io.ReadAll(res.Body)
+	require.NoError(t, err)
+	require.Equal(t, "db_jwt_query", string(body))
+	cookies := res.Cookies()
+	require.Equal(t, 1, len(cookies))
+	require.Equal(t, "__clerk_db_jwt", cookies[0].Name)
+	require.Equal(t, "db_jwt_query", cookies[0].Value)
+
+	// The dev browser token can also be read from the cookie.
+	req, err = http.NewRequest(http.MethodGet, ts.URL, nil)
+	require.NoError(t, err)
+	req.AddCookie(&http.Cookie{Name: "__session", Value: token})
+	req.AddCookie(&http.Cookie{Name: "__clerk_db_jwt", Value: "db_jwt_cookie"})
+	res, err = ts.Client().Do(req)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, res.StatusCode)
+	body, err = io.ReadAll(res.Body)
+	require.NoError(t, err)
+	require.Equal(t, "db_jwt_cookie", string(body))
+	require.Equal(t, 0, len(res.Cookies()))
+}
+
+func TestWithHeaderAuthorization_ProductionInstanceIgnoresCookies(t *testing.T) {
+	token, pubKey := clerktest.GenerateJWT(t, map[string]any{
+		"sid": "sess_123",
+		"iss": "https://clerk.example.com",
+	}, "kid")
+	middleware := WithHeaderAuthorization(
+		DevelopmentInstance(false),
+		JSONWebKey(publicKeyToPEM(t, pubKey)),
+	)
+	ts := httptest.NewServer(middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, ok := clerk.SessionClaimsFromContext(r.Context())
+		require.False(t, ok)
+		_, ok = clerk.DevBrowserTokenFromContext(r.Context())
+		require.False(t, ok)
+	})))
+	defer ts.Close()
+
+	req, err := http.NewRequest(http.MethodGet, ts.URL+"?__clerk_db_jwt=db_jwt", nil)
+	require.NoError(t, err)
+	req.AddCookie(&http.Cookie{Name: "__session", Value: token})
+	res, err := ts.Client().Do(req)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, res.StatusCode)
+	require.Equal(t, 0, len(res.Cookies()))
+}
+
+func TestWithHeaderAuthorization_DevelopmentKey(t *testing.T) {
+	clerk.SetKey("sk_test_123")
+	t.Cleanup(func() { clerk.SetKey("") })
+
+	newServer := func(pubKey crypto.PublicKey, opts ...AuthorizationOption) *httptest.Server {
+		opts = append(opts, JSONWebKey(publicKeyToPEM(t, pubKey)))
+		ts := httptest.NewServer(WithHeaderAuthorization(opts...)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, ok := clerk.SessionClaimsFromContext(r.Context())
+			if !ok {
+				w.WriteHeader(http.StatusNoContent)
+				return
+			}
+			devBrowserToken, _ := clerk.DevBrowserTokenFromContext(r.Context())
+			_, _ = w.Write([]byte(devBrowserToken))
+		})))
+		t.Cleanup(ts.Close)
+		return ts
+	}
+	get := func(ts *httptest.Server, sessionToken string) *http.Response {
+		req, err := http.NewRequest(http.MethodGet, ts.URL+"?__clerk_db_jwt=db_jwt", nil)
+		require.NoError(t, err)
+		req.AddCookie(&http.Cookie{Name: "__session", Value: sessionToken})
+		res, err := ts.Client().Do(req)
+		require.NoError(t, err)
+		return res
+	}
+
+	// The instance type is inferred from the key. Session cookies are
+	// accepted only with an authorized party check.
+	token, pubKey := clerktest.GenerateJWT(t, map[string]any{
+		"sid": "sess_123",
+		"iss": "https://foo-bar-13.clerk.accounts.dev",
+		"azp": "http://localhost:3000",
+	}, "kid")
+	res := get(newServer(pubKey, AuthorizedPartyMatches("http://localhost:3000")), token)
+	require.Equal(t, http.StatusOK, res.StatusCode)
+	body, err := io.ReadAll(res.Body)
+	require.NoError(t, err)
+	require.Equal(t, "db_jwt", string(body))
+
+	// Without an authorized party check, the session cookie is
+	// ignored.
+	res = get(newServer(pubKey), token)
+	require.Equal(t, http.StatusNoContent, res.StatusCode)
+
+	// Tokens from the cookie must have an azp claim.
+	token, pubKey = clerktest.GenerateJWT(t, map[string]any{
+		"sid": "sess_123",
+		"iss": "https://foo-bar-13.clerk.accounts.dev",
+	}, "kid")
+	res = get(newServer(pubKey, AuthorizedPartyMatches("http://localhost:3000")), token)
+	require.Equal(t, http.StatusUnauthorized, res.StatusCode)
+}
+
+// Returns the PEM encoded representation of the public key, so that
+// it can be used with the JSONWebKey option.
+func publicKeyToPEM(t *testing.T, pubKey crypto.PublicKey) string {
+	t.Helper()
+	der, err := x509.MarshalPKIXPublicKey(pubKey)
+	require.NoError(t, err)
+	return string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}))
+}
@@ -0,0 +1,304 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/clerk/clerk-sdk-go/v2"
+	"github.com/clerk/clerk-sdk-go/v2/organization"
+	"github.com/clerk/clerk-sdk-go/v2/user"
+)
+
+// WithUser loads the user of the active session and writes it to
+// the http.Request context. The user can be accessed with
+// clerk.UserFromContext.
+// The middleware needs the active session claims, so it must be
+// used after the WithHeaderAuthorization middleware. Requests
+// without session claims are passed through.
+//
+// By default the user is fetched for every request, before the next
+// handler is called. Failed requests to the Clerk API are handled by
+// the LoadFailureHandler. Use the LazyLoad option to fetch the user
+// only when clerk.UserFromContext is called, and the LoadCacheTTL
+// option to cache users.
+// The loaded user is shared by all accesses for the lifetime of the
+// request, and by other requests if caching is enabled, so it must
+// not be modified.
+func WithUser(opts ...LoadOption) func(http.Handler) http.Handler {
+	params, err := newLoadParams(opts...)
+	cache := newResourceCache[clerk.User]()
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if err != nil {
+				params.LoadFailureHandler(w, r, err)
+				return
+			}
+			claims, ok := clerk.SessionClaimsFromContext(r.Context())
+			if !ok || claims == nil || claims.Subject == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+			load := func(ctx context.Context) (*clerk.User, error) {
+				return cache.Load(claims.Subject, params.CacheTTL, params.Clock, func() (*clerk.User, error) {
+					return params.userClient().Get(ctx, claims.Subject)
+				})
+			}
+			if params.Lazy {
+				ctx := r.Context()
+				newCtx := clerk.ContextWithUserLoader(ctx, func() (*clerk.User, error) {
+					usr, err := load(ctx)
+					if err != nil {
+						params.LoadFailureHandler(w, r, err)
+					}
+					return usr, err
+				})
+				next.ServeHTTP(w, r.WithContext(newCtx))
+				return
+			}
+			usr, err := load(r.Context())
+			if err != nil {
+				params.LoadFailureHandler(w, r, err)
+				return
+			}
+			next.ServeHTTP(w, r.WithContext(clerk.ContextWithUser(r.Context(), usr)))
+		})
+	}
+}
+
+// WithOrganization loads the active organization of the active
+// session and writes it to the http.Request context. The
+// organization can be accessed with clerk.OrganizationFromContext.
+// The middleware needs the active session claims, so it must be
+// used after the WithHeaderAuthorization middleware. Requests
+// without session claims or without an active organization are
+// passed through.
+//
+// Loading works in the same way as for the WithUser middleware and
+// the same options apply.
+func WithOrganization(opts ...LoadOption) func(http.Handler) http.Handler {
+	params, err := newLoadParams(opts...)
+	cache := newResourceCache[clerk.Organization]()
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if err != nil {
+				params.LoadFailureHandler(w, r, err)
+				return
+			}
+			claims, ok := clerk.SessionClaimsFromContext(r.Context())
+			if !ok || claims == nil || claims.ActiveOrganizationID == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+			orgID := claims.ActiveOrganizationID
+			load := func(ctx context.Context) (*clerk.Organization, error) {
+				return cache.Load(orgID, params.CacheTTL, params.Clock, func() (*clerk.Organization, error) {
+					return params.organizationClient().Get(ctx, orgID)
+				})
+			}
+			if params.Lazy {
+				ctx := r.Context()
+				newCtx := clerk.ContextWithOrganizationLoader(ctx, func() (*clerk.Organization, error) {
+					org, err := load(ctx)
+					if err != nil {
+						params.LoadFailureHandler(w, r, err)
+					}
+					return org, err
+				})
+				next.ServeHTTP(w, r.WithContext(newCtx))
+				return
+			}
+			org, err := load(r.Context())
+			if err != nil {
+				params.LoadFailureHandler(w, r, err)
+				return
+			}
+			next.ServeHTTP(w, r.WithContext(clerk.ContextWithOrganization(r.Context(), org)))
+		})
+	}
+}
+
+// LoadFailureHandlerFunc writes the response when loading a
+// resource from the Clerk API fails. The err is the error that
+// caused the failure.
+type LoadFailureHandlerFunc func(w http.ResponseWriter, r *http.Request, err error)
+
+type LoadParams struct {
+	// UserClient is the user.Client that will be used to fetch users.
+	// A client with the default Backend will be used if none is
+	// provided.
+	UserClient *user.Client
+	// OrganizationClient is the organization.Client that will be used
+	// to fetch organizations. A client with the default Backend will
+	// be used if none is provided.
+	OrganizationClient *organization.Client
+	// Lazy defers loading until the resource is accessed from the
+	// context.
+	Lazy bool
+	// CacheTTL is the duration for which loaded resources are cached.
+	// Resources are not cached by default.
+	CacheTTL time.Duration
+	// Clock is the authority for the cache expiration.
+	Clock clerk.Clock
+	// LoadFailureHandler gets executed when loading a resource fails.
+	// The default responds with an empty body and 500 Internal Server
+	// Error status.
+	LoadFailureHandler LoadFailureHandlerFunc
+}
+
+// LoadOption is a functional parameter for configuring the
+// WithUser and WithOrganization middleware.
+type LoadOption func(*LoadParams) error
+
+// Applies the options and sets default values.
+func newLoadParams(opts ...LoadOption) (*LoadParams, error) {
+	params := &LoadParams{}
+	var err error
+	for _, opt := range opts {
+		err = opt(params)
+		if err != nil {
+			break
+		}
+	}
+	if params.Clock == nil {
+		params.Clock = clerk.NewClock()
+	}
+	if params.LoadFailureHandler == nil {
+		params.LoadFailureHandler = defaultLoadFailureHandler
+	}
+	return params, err
+}
+
+func (params *LoadParams) userClient() *user.Client {
+	if params.UserClient != nil {
+		return params.UserClient
+	}
+	return &user.Client{Backend: clerk.GetBackend()}
+}
+
+func (params *LoadParams) organizationClient() *organization.Client {
+	if params.OrganizationClient != nil {
+		return params.OrganizationClient
+	}
+	return &organization.Client{Backend: clerk.GetBackend()}
+}
+
+func defaultLoadFailureHandler(w http.ResponseWriter, _ *http.Request, _ error) {
+	w.WriteHeader(http.StatusInternalServerError)
+}
+
+// UserClient allows to provide a custom user.Client for fetching
+// users.
+func UserClient(client *user.Client) LoadOption {
+	return func(params *LoadParams) error {
+		params.UserClient = client
+		return nil
+	}
+}
+
+// OrganizationClient allows to provide a custom organization.Client
+// for fetching organizations.
+func OrganizationClient(client *organization.Client) LoadOption {
+	return func(params *LoadParams) error {
+		params.OrganizationClient = client
+		return nil
+	}
+}
+
+// LazyLoad defers fetching the resource until it's accessed from
+// the context. Lazy loading saves requests to the Clerk API for
+// handlers that don't need the resource.
+// Since the next handler is already executing when the resource is
+// fetched, a failure calls the LoadFailureHandler from within the
+// next handler, at the first access. Accessing the resource from the
+// context then reports that it's not available, and the next handler
+// should return without writing a response.
+func LazyLoad() LoadOption {
+	return func(params *LoadParams) error {
+		params.Lazy = true
+		return nil
+	}
+}
+
+// LoadCacheTTL enables caching of loaded resources for the provided
+// duration. The cache is scoped to the middleware.
+func LoadCacheTTL(ttl time.Duration) LoadOption {
+	return func(params *LoadParams) error {
+		params.CacheTTL = ttl
+		return nil
+	}
+}
+
+// LoadClock allows to pass a clock implementation that will be the
+// authority for cache expiration.
+func LoadClock(c clerk.Clock) LoadOption {
+	return func(params *LoadParams) error {
+		params.Clock = c
+		return nil
+	}
+}
+
+// LoadFailureHandler allows to provide a function that writes the
+// response in case loading a resource fails.
+// The default behavior is a response with an empty body and 500
+// Internal Server Error status.
+func LoadFailureHandler(h LoadFailureHandlerFunc) LoadOption {
+	return func(params *LoadParams) error {
+		params.LoadFailureHandler = h
+		return nil
+	}
+}
+
+// A cache for resources that are fetched from the Clerk API.
+type resourceCache[T any] struct {
+	mu      sync.RWMutex
+	entries map[string]*resourceCacheEntry[T]
+}
+
+// Each entry in the resource cache has a value and an expiration date.
+type resourceCacheEntry[T any] struct {
+	value     *T
+	expiresAt time.Time
+}
+
+func newResourceCache[T any]() *resourceCache[T] {
+	return &resourceCache[T]{
+		entries: map[string]*resourceCacheEntry[T]{},
+	}
+}
+
+// Load returns the cached value for the key if it hasn't expired.
+// Otherwise, it calls fetch and caches the result for ttl. A zero
+// ttl disables caching.
+func (c *resourceCache[T]) Load(key string, ttl time.Duration, clock clerk.Clock, fetch func() (*T, error)) (*T, error) {
+	if ttl <= 0 {
+		return fetch()
+	}
+	now := clock.Now().UTC()
+	c.mu.RLock()
+	entry, ok := c.entries[key]
+	c.mu.RUnlock()
+	if ok && entry.expiresAt.After(now) {
+		return entry.value, nil
+	}
+
+	value, err := fetch()
+	if err != nil {
+		return nil, err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(c.entries) >= cachePurgeSize {
+		for k, entry := range c.entries {
+			if !entry.expiresAt.After(now) {
+				delete(c.entries, k)
+			}
+		}
+	}
+	c.entries[key] = &resourceCacheEntry[T]{
+		value:     value,
+		expiresAt: now.Add(ttl),
+	}
+	return value, nil
+}
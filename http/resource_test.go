@@ -0,0 +1,191 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/clerk/clerk-sdk-go/v2"
+	"github.com/clerk/clerk-sdk-go/v2/clerktest"
+	"github.com/clerk/clerk-sdk-go/v2/organization"
+	"github.com/clerk/clerk-sdk-go/v2/user"
+	"github.com/stretchr/testify/require"
+)
+
+// Adds session claims to the request context, like the authorization
+// middleware would.
+func withSessionClaims(claims *clerk.SessionClaims) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			next.ServeHTTP(w, r.WithContext(clerk.ContextWithSessionClaims(r.Context(), claims)))
+		})
+	}
+}
+
+func TestWithUser(t *testing.T) {
+	clock := clerktest.NewClockAt(time.Now().UTC())
+	totalRequests := 0
+	clerkAPI := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/users/user_123", r.URL.Path)
+		totalRequests++
+		_, err := w.Write([]byte(`{"id":"user_123","object":"user"}`))
+		require.NoError(t, err)
+	}))
+	defer clerkAPI.Close()
+	config := &clerk.ClientConfig{}
+	config.HTTPClient = clerkAPI.Client()
+	config.URL = &clerkAPI.URL
+
+	middleware := WithUser(
+		UserClient(user.NewClient(config)),
+		LoadCacheTTL(time.Minute),
+		LoadClock(clock),
+	)
+	claims := &clerk.SessionClaims{}
+	claims.Subject = "user_123"
+	handler := withSessionClaims(claims)(middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		usr, ok := clerk.UserFromContext(r.Context())
+		require.True(t, ok)
+		require.Equal(t, "user_123", usr.ID)
+	})))
+
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	require.Equal(t, 1, totalRequests)
+
+	// The user is cached.
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	require.Equal(t, 1, totalRequests)
+
+	// The cache entry expires.
+	clock.Advance(2 * time.Minute)
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	require.Equal(t, 2, totalRequests)
+}
+
+func TestWithUser_Lazy(t *testing.T) {
+	totalRequests := 0
+	clerkAPI := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		totalRequests++
+		_, err := w.Write([]byte(`{"id":"user_123","object":"user"}`))
+		require.NoError(t, err)
+	}))
+	defer clerkAPI.Close()
+	config := &clerk.ClientConfig{}
+	config.HTTPClient = clerkAPI.Client()
+	config.URL = &clerkAPI.URL
+
+	claims := &clerk.SessionClaims{}
+	claims.Subject = "user_123"
+	middleware := withSessionClaims(claims)(WithUser(UserClient(user.NewClient(config)), LazyLoad())(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Query().Get("load") == "" {
+				return
+			}
+			for i := 0; i < 2; i++ {
+				usr, ok := clerk.UserFromContext(r.Context())
+				require.True(t, ok)
+				require.Equal(t, "user_123", usr.ID)
+			}
+		}),
+	))
+
+	// The user is not accessed, so it's never fetched.
+	middleware.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	require.Equal(t, 0, totalRequests)
+
+	// The user is fetched once, even if it's accessed multiple times.
+	middleware.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/?load=1", nil))
+	require.Equal(t, 1, totalRequests)
+}
+
+func TestWithUser_LazyFailure(t *testing.T) {
+	clerkAPI := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		_, err := w.Write([]byte(`{"errors":[{"code":"resource_not_found"}]}`))
+		require.NoError(t, err)
+	}))
+	defer clerkAPI.Close()
+	config := &clerk.ClientConfig{}
+	config.HTTPClient = clerkAPI.Client()
+	config.URL = &clerkAPI.URL
+
+	failures := 0
+	claims := &clerk.SessionClaims{}
+	claims.Subject = "user_123"
+	middleware := withSessionClaims(claims)(WithUser(
+		UserClient(user.NewClient(config)),
+		LazyLoad(),
+		LoadFailureHandler(func(w http.ResponseWriter, r *http.Request, err error) {
+			failures++
+			apiErr, ok := err.(*clerk.APIErrorResponse)
+			require.True(t, ok)
+			require.Equal(t, http.StatusNotFound, apiErr.HTTPStatusCode)
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}),
+	)(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			for i := 0; i < 2; i++ {
+				_, ok := clerk.UserFromContext(r.Context())
+				require.False(t, ok)
+			}
+		}),
+	))
+
+	// The failure handler is called once, at the first access.
+	res := httptest.NewRecorder()
+	middleware.ServeHTTP(res, httptest.NewRequest(http.MethodGet, "/", nil))
+	require.Equal(t, http.StatusServiceUnavailable, res.Code)
+	require.Equal(t, 1, failures)
+}
+
+func TestWithOrganization(t *testing.T) {
+	clerkAPI := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "org_404") {
+			w.WriteHeader(http.StatusNotFound)
+			_, err := w.Write([]byte(`{"errors":[{"code":"resource_not_found"}]}`))
+			require.NoError(t, err)
+			return
+		}
+		require.Equal(t, "/organizations/org_123", r.URL.Path)
+		_, err := w.Write([]byte(`{"id":"org_123","object":"organization"}`))
+		require.NoError(t, err)
+	}))
+	defer clerkAPI.Close()
+	config := &clerk.ClientConfig{}
+	config.HTTPClient = clerkAPI.Client()
+	config.URL = &clerkAPI.URL
+
+	middleware := WithOrganization(
+		OrganizationClient(organization.NewClient(config)),
+		LoadFailureHandler(func(w http.ResponseWriter, _ *http.Request, err error) {
+			require.Error(t, err)
+			w.WriteHeader(http.StatusTeapot)
+		}),
+	)
+	for _, tc := range []struct {
+		orgID string
+		want  int
+	}{
+		{orgID: "org_123", want: http.StatusOK},
+		{orgID: "org_404", want: http.StatusTeapot},
+		{orgID: "", want: http.StatusNoContent},
+	} {
+		claims := &clerk.SessionClaims{}
+		claims.ActiveOrganizationID = tc.orgID
+		handler := withSessionClaims(claims)(middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			org, ok := clerk.OrganizationFromContext(r.Context())
+			if tc.orgID == "" {
+				require.False(t, ok)
+				w.WriteHeader(http.StatusNoContent)
+				return
+			}
+			require.True(t, ok)
+			require.Equal(t, tc.orgID, org.ID)
+		})))
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+		require.Equal(t, tc.want, w.Code)
+	}
+}
@@ -0,0 +1,169 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/clerk/clerk-sdk-go/v2"
+	"github.com/clerk/clerk-sdk-go/v2/session"
+)
+
+const (
+	defaultActiveSessionCacheTTL   = 5 * time.Second
+	defaultInactiveSessionCacheTTL = time.Minute
+)
+
+// SessionRevocationCheckParams configures the session revocation
+// check. See the SessionRevocationCheck option.
+type SessionRevocationCheckParams struct {
+	// SessionClient is the session.Client that will be used to fetch
+	// the session. A client with the default Backend will be used if
+	// none is provided.
+	SessionClient *session.Client
+	// ActiveCacheTTL is the duration for which a session that was
+	// found to be active is cached. Defaults to five seconds.
+	// Revocations are honored after this duration at the latest.
+	ActiveCacheTTL time.Duration
+	// InactiveCacheTTL is the duration for which a session that was
+	// found to be revoked, ended or missing is cached. Defaults to
+	// one minute.
+	InactiveCacheTTL time.Duration
+	// FailOpen determines the policy when the session status cannot
+	// be determined, because the Clerk API request failed. If true,
+	// the request is allowed. The default is to reject the request.
+	FailOpen bool
+}
+
+// SessionRevocationCheck enables a check that the session of a
+// verified session token is still active.
+// Session tokens are verified without any network requests and are
+// accepted until they expire, even if the session has been revoked
+// in the meantime. With the check enabled, the session is retrieved
+// with session.Client.Get and the request is authorized only if the
+// session status is active. Results are cached for a short, configurable
+// duration.
+// The check costs an API request per session and cache period, so
+// it's recommended to enable it only for high-security routes.
+func SessionRevocationCheck(params *SessionRevocationCheckParams) AuthorizationOption {
+	checker := newSessionRevocationChecker(params)
+	return func(authParams *AuthorizationParams) error {
+		authParams.sessionRevocationChecker = checker
+		return nil
+	}
+}
+
+// Checks whether sessions are active and caches the results.
+type sessionRevocationChecker struct {
+	client           *session.Client
+	activeCacheTTL   time.Duration
+	inactiveCacheTTL time.Duration
+	failOpen         bool
+
+	mu      sync.RWMutex
+	entries map[string]*sessionStatusEntry
+}
+
+// Each cache entry holds whether the session is active and an
+// expiration date.
+type sessionStatusEntry struct {
+	active    bool
+	expiresAt time.Time
+}
+
+func newSessionRevocationChecker(params *SessionRevocationCheckParams) *sessionRevocationChecker {
+	if params == nil {
+		params = &SessionRevocationCheckParams{}
+	}
+	checker := &sessionRevocationChecker{
+		client:           params.SessionClient,
+		activeCacheTTL:   params.ActiveCacheTTL,
+		inactiveCacheTTL: params.InactiveCacheTTL,
+		failOpen:         params.FailOpen,
+		entries:          map[string]*sessionStatusEntry{},
+	}
+	if checker.activeCacheTTL == 0 {
+		checker.activeCacheTTL = defaultActiveSessionCacheTTL
+	}
+	if checker.inactiveCacheTTL == 0 {
+		checker.inactiveCacheTTL = defaultInactiveSessionCacheTTL
+	}
+	return checker
+}
+
+// Check returns an error if the session is not active, or if the
+// status cannot be determined and the checker fails closed.
+func (c *sessionRevocationChecker) Check(ctx context.Context, sessionID string, now time.Time) error {
+	if sessionID == "" {
+		return fmt.Errorf("missing session ID")
+	}
+	active, ok := c.get(sessionID, now)
+	if !ok {
+		var err error
+		active, err = c.fetch(ctx, sessionID)
+		if err != nil {
+			if c.failOpen {
+				return nil
+			}
+			return err
+		}
+		c.set(sessionID, active, now)
+	}
+	if !active {
+		return fmt.Errorf("session %s is not active", sessionID)
+	}
+	return nil
+}
+
+// Retrieves the session from the Clerk API. Sessions that cannot
+// be found are considered inactive.
+func (c *sessionRevocationChecker) fetch(ctx context.Context, sessionID string) (bool, error) {
+	client := c.client
+	if client == nil {
+		client = &session.Client{
+			Backend: clerk.GetBackend(),
+		}
+	}
+	sess, err := client.Get(ctx, sessionID)
+	if err != nil {
+		var apiErr *clerk.APIErrorResponse
+		if errors.As(err, &apiErr) && apiErr.HTTPStatusCode == http.StatusNotFound {
+			return false, nil
+		}
+		return false, err
+	}
+	return sess.Status == "active", nil
+}
+
+func (c *sessionRevocationChecker) get(sessionID string, now time.Time) (bool, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	entry, ok := c.entries[sessionID]
+	if !ok || entry == nil || !entry.expiresAt.After(now) {
+		return false, false
+	}
+	return entry.active, true
+}
+
+func (c *sessionRevocationChecker) set(sessionID string, active bool, now time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(c.entries) >= cachePurgeSize {
+		for id, entry := range c.entries {
+			if !entry.expiresAt.After(now) {
+				delete(c.entries, id)
+			}
+		}
+	}
+	ttl := c.inactiveCacheTTL
+	if active {
+		ttl = c.activeCacheTTL
+	}
+	c.entries[sessionID] = &sessionStatusEntry{
+		active:    active,
+		expiresAt: now.Add(ttl),
+	}
+}
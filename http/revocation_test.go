@@ -0,0 +1,126 @@
+package http
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/clerk/clerk-sdk-go/v2"
+	"github.com/clerk/clerk-sdk-go/v2/clerktest"
+	"github.com/clerk/clerk-sdk-go/v2/session"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithHeaderAuthorization_SessionRevocationCheck(t *testing.T) {
+	clock := clerktest.NewClockAt(time.Now().UTC())
+	status := "active"
+	totalSessionRequests := 0
+	// Mock the Clerk API server. We expect requests to GET /sessions/sess_123.
+	clerkAPI := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/sessions/sess_123", r.URL.Path)
+		totalSessionRequests++
+		_, err := w.Write([]byte(fmt.Sprintf(`{"id":"sess_123","status":"%s"}`, status)))
+		require.NoError(t, err)
+	}))
+	defer clerkAPI.Close()
+	config := &clerk.ClientConfig{}
+	config.HTTPClient = clerkAPI.Client()
+	config.URL = &clerkAPI.URL
+
+	token, pubKey := clerktest.GenerateJWT(t, map[string]any{
+		"sid": "sess_123",
+		"iss": "https://clerk.example.com",
+	}, "kid")
+	middleware := RequireHeaderAuthorization(
+		Clock(clock),
+		JSONWebKey(publicKeyToPEM(t, pubKey)),
+		SessionRevocationCheck(&SessionRevocationCheckParams{
+			SessionClient:    session.NewClient(config),
+			ActiveCacheTTL:   time.Second,
+			InactiveCacheTTL: time.Minute,
+		}),
+	)
+	ts := httptest.NewServer(middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, err := w.Write([]byte("{}"))
+		require.NoError(t, err)
+	})))
+	defer ts.Close()
+
+	req, err := http.NewRequest(http.MethodGet, ts.URL, nil)
+	require.NoError(t, err)
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	// The session is active.
+	res, err := ts.Client().Do(req)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, res.StatusCode)
+	require.Equal(t, 1, totalSessionRequests)
+
+	// The session gets revoked, but the active status is cached.
+	status = "revoked"
+	res, err = ts.Client().Do(req)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, res.StatusCode)
+	require.Equal(t, 1, totalSessionRequests)
+
+	// Once the cache entry expires, the revocation is honored.
+	clock.Advance(2 * time.Second)
+	res, err = ts.Client().Do(req)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusUnauthorized, res.StatusCode)
+	require.Equal(t, 2, totalSessionRequests)
+
+	// The inactive status is cached as well.
+	clock.Advance(2 * time.Second)
+	res, err = ts.Client().Do(req)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusUnauthorized, res.StatusCode)
+	require.Equal(t, 2, totalSessionRequests)
+}
+
+func TestWithHeaderAuthorization_SessionRevocationCheckFailurePolicy(t *testing.T) {
+	// Mock the Clerk API server, which always fails.
+	clerkAPI := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+		_, err := w.Write([]byte(`{"errors":[{"code":"internal_clerk_error"}]}`))
+		require.NoError(t, err)
+	}))
+	defer clerkAPI.Close()
+	config := &clerk.ClientConfig{}
+	config.HTTPClient = clerkAPI.Client()
+	config.URL = &clerkAPI.URL
+
+	token, pubKey := clerktest.GenerateJWT(t, map[string]any{
+		"sid": "sess_123",
+		"iss": "https://clerk.example.com",
+	}, "kid")
+	for _, tc := range []struct {
+		failOpen bool
+		want     int
+	}{
+		{failOpen: true, want: http.StatusOK},
+		{failOpen: false, want: http.StatusUnauthorized},
+	} {
+		middleware := RequireHeaderAuthorization(
+			JSONWebKey(publicKeyToPEM(t, pubKey)),
+			SessionRevocationCheck(&SessionRevocationCheckParams{
+				SessionClient: session.NewClient(config),
+				FailOpen:      tc.failOpen,
+			}),
+		)
+		ts := httptest.NewServer(middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, err := w.Write([]byte("{}"))
+			require.NoError(t, err)
+		})))
+
+		req, err := http.NewRequest(http.MethodGet, ts.URL, nil)
+		require.NoError(t, err)
+		req.Header.Set("Authorization", "Bearer "+token)
+		res, err := ts.Client().Do(req)
+		require.NoError(t, err)
+		require.Equal(t, tc.want, res.StatusCode)
+		ts.Close()
+	}
+}
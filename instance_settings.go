@@ -23,3 +23,18 @@ type OrganizationSettings struct {
 	DomainsEnrollmentModes []string `json:"domains_enrollment_modes"`
 	DomainsDefaultRole     string   `json:"domains_default_role"`
 }
+
+type Instance struct {
+	APIResource
+	Object                      string   `json:"object"`
+	ID                          string   `json:"id"`
+	EnvironmentType             string   `json:"environment_type"`
+	AllowedOrigins              []string `json:"allowed_origins"`
+	TestMode                    *bool    `json:"test_mode,omitempty"`
+	HIBP                        *bool    `json:"hibp,omitempty"`
+	EnhancedEmailDeliverability *bool    `json:"enhanced_email_deliverability,omitempty"`
+	SupportEmail                *string  `json:"support_email,omitempty"`
+	ClerkJSVersion              *string  `json:"clerk_js_version,omitempty"`
+	URLBasedSessionSyncing      *bool    `json:"url_based_session_syncing,omitempty"`
+	DevelopmentOrigin           *string  `json:"development_origin,omitempty"`
+}
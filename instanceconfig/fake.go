@@ -0,0 +1,212 @@
+package instanceconfig
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/clerk/clerk-sdk-go/v2"
+)
+
+// FakeBackend is an in-memory clerk.Backend which implements the
+// parts of the Clerk API that are used by the Manager. It can be used
+// to test Specs offline.
+//
+//	backend := instanceconfig.NewFakeBackend()
+//	backend.RedirectURLs = []*clerk.RedirectURL{{ID: "redir_1", URL: "https://example.com"}}
+//	manager := instanceconfig.NewManager(backend)
+//
+// The exported fields hold the state of the fake instance. They can
+// be seeded before the backend is used and inspected afterwards, but
+// must not be accessed while requests are in progress.
+type FakeBackend struct {
+	Instance             *clerk.Instance
+	Restrictions         *clerk.InstanceRestrictions
+	OrganizationSettings *clerk.OrganizationSettings
+	Domains              []*clerk.Domain
+	RedirectURLs         []*clerk.RedirectURL
+	AllowlistIdentifiers []*clerk.AllowlistIdentifier
+	BlocklistIdentifiers []*clerk.BlocklistIdentifier
+	JWTTemplates         []*clerk.JWTTemplate
+	Templates            []*clerk.Template
+	// Requests records the method and path of every request, for
+	// example "POST /redirect_urls".
+	Requests []string
+
+	mu     sync.Mutex
+	nextID int
+}
+
+// NewFakeBackend returns a FakeBackend for an empty instance.
+func NewFakeBackend() *FakeBackend {
+	return &FakeBackend{
+		Instance:             &clerk.Instance{Object: "instance", ID: "ins_fake"},
+		Restrictions:         &clerk.InstanceRestrictions{Object: "instance_restrictions"},
+		OrganizationSettings: &clerk.OrganizationSettings{Object: "organization_settings"},
+	}
+}
+
+// Call handles the request with the in-memory state.
+func (b *FakeBackend) Call(_ context.Context, req *clerk.APIRequest, resource clerk.ResponseReader) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	path, _, _ := strings.Cut(req.Path, "?")
+	b.Requests = append(b.Requests, req.Method+" "+path)
+	var params []byte
+	if req.Params != nil {
+		var err error
+		params, err = json.Marshal(req.Params)
+		if err != nil {
+			return err
+		}
+	}
+
+	response, err := b.handle(req.Method, strings.Split(strings.Trim(path, "/"), "/"), params)
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(response)
+	if err != nil {
+		return err
+	}
+	resource.Read(&clerk.APIResponse{
+		Status:     "200 OK",
+		StatusCode: http.StatusOK,
+		RawJSON:    data,
+	})
+	return json.Unmarshal(data, resource)
+}
+
+// Routes the request to the resource. Request params are decoded
+// into the stored resources, since they share their field names.
+func (b *FakeBackend) handle(method string, segments []string, params []byte) (any, error) {
+	route := method + " " + segments[0]
+	switch {
+	case route == "GET instance" && len(segments) == 1:
+		return b.Instance, nil
+	case route == "PATCH instance" && len(segments) == 1:
+		return b.Instance, json.Unmarshal(params, b.Instance)
+	case route == "GET instance" && segments[1] == "restrictions":
+		return b.Restrictions, nil
+	case route == "PATCH instance" && segments[1] == "restrictions":
+		return b.Restrictions, json.Unmarshal(params, b.Restrictions)
+	case route == "GET instance" && segments[1] == "organization_settings":
+		return b.OrganizationSettings, nil
+	case route == "PATCH instance" && segments[1] == "organization_settings":
+		return b.OrganizationSettings, json.Unmarshal(params, b.OrganizationSettings)
+	case segments[0] == "domains":
+		return handleCollection(b, &b.Domains, "dmn", method, segments, params,
+			func(d *clerk.Domain) string { return d.ID },
+			func(d *clerk.Domain, id string) { d.ID = id; d.Object = "domain" })
+	case segments[0] == "redirect_urls":
+		return handleCollection(b, &b.RedirectURLs, "redir", method, segments, params,
+			func(r *clerk.RedirectURL) string { return r.ID },
+			func(r *clerk.RedirectURL, id string) { r.ID = id; r.Object = "redirect_url" })
+	case segments[0] == "allowlist_identifiers":
+		return handleCollection(b, &b.AllowlistIdentifiers, "alid", method, segments, params,
+			func(i *clerk.AllowlistIdentifier) string { return i.ID },
+			func(i *clerk.AllowlistIdentifier, id string) { i.ID = id; i.Object = "allowlist_identifier" })
+	case segments[0] == "blocklist_identifiers":
+		return handleCollection(b, &b.BlocklistIdentifiers, "blid", method, segments, params,
+			func(i *clerk.BlocklistIdentifier) string { return i.ID },
+			func(i *clerk.BlocklistIdentifier, id string) { i.ID = id; i.Object = "blocklist_identifier" })
+	case segments[0] == "jwt_templates":
+		return handleCollection(b, &b.JWTTemplates, "jtmp", method, segments, params,
+			func(t *clerk.JWTTemplate) string { return t.ID },
+			func(t *clerk.JWTTemplate, id string) { t.ID = id; t.Object = "jwt_template" })
+	case segments[0] == "templates" && len(segments) >= 2:
+		return b.handleTemplates(method, segments, params)
+	}
+	return nil, notFound(strings.Join(segments, "/"))
+}
+
+// Handles list, create, get, update and delete requests for a
+// collection of resources that are identified by ID.
+func handleCollection[T any](
+	b *FakeBackend,
+	items *[]*T,
+	idPrefix string,
+	method string,
+	segments []string,
+	params []byte,
+	getID func(*T) string,
+	setID func(*T, string),
+) (any, error) {
+	if len(segments) == 1 {
+		switch method {
+		case http.MethodGet:
+			return map[string]any{"data": *items, "total_count": len(*items)}, nil
+		case http.MethodPost:
+			item := new(T)
+			err := json.Unmarshal(params, item)
+			if err != nil {
+				return nil, err
+			}
+			b.nextID++
+			setID(item, fmt.Sprintf("%s_%d", idPrefix, b.nextID))
+			*items = append(*items, item)
+			return item, nil
+		}
+		return nil, notFound(segments[0])
+	}
+
+	id := segments[1]
+	for i, item := range *items {
+		if getID(item) != id {
+			continue
+		}
+		switch method {
+		case http.MethodGet:
+			return item, nil
+		case http.MethodPatch:
+			return item, json.Unmarshal(params, item)
+		case http.MethodDelete:
+			*items = append((*items)[:i], (*items)[i+1:]...)
+			return &clerk.DeletedResource{ID: id, Object: segments[0], Deleted: true}, nil
+		}
+	}
+	return nil, notFound(strings.Join(segments, "/"))
+}
+
+func (b *FakeBackend) handleTemplates(method string, segments []string, params []byte) (any, error) {
+	templateType := clerk.TemplateType(segments[1])
+	if len(segments) == 2 && method == http.MethodGet {
+		var templates []*clerk.Template
+		for _, t := range b.Templates {
+			if t.TemplateType == templateType {
+				templates = append(templates, t)
+			}
+		}
+		return map[string]any{"data": templates, "total_count": len(templates)}, nil
+	}
+	if len(segments) != 3 {
+		return nil, notFound(strings.Join(segments, "/"))
+	}
+	slug := segments[2]
+	for _, t := range b.Templates {
+		if t.TemplateType != templateType || t.Slug != slug {
+			continue
+		}
+		switch method {
+		case http.MethodGet:
+			return t, nil
+		case http.MethodPut:
+			return t, json.Unmarshal(params, t)
+		}
+	}
+	return nil, notFound(strings.Join(segments, "/"))
+}
+
+func notFound(path string) error {
+	return &clerk.APIErrorResponse{
+		HTTPStatusCode: http.StatusNotFound,
+		Errors: []clerk.Error{{
+			Code:    "resource_not_found",
+			Message: fmt.Sprintf("%s not found", path),
+		}},
+	}
+}
@@ -0,0 +1,700 @@
+package instanceconfig
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/clerk/clerk-sdk-go/v2"
+	"github.com/clerk/clerk-sdk-go/v2/allowlistidentifier"
+	"github.com/clerk/clerk-sdk-go/v2/blocklistidentifier"
+	"github.com/clerk/clerk-sdk-go/v2/domain"
+	"github.com/clerk/clerk-sdk-go/v2/instancesettings"
+	"github.com/clerk/clerk-sdk-go/v2/jwttemplate"
+	"github.com/clerk/clerk-sdk-go/v2/redirecturl"
+	"github.com/clerk/clerk-sdk-go/v2/template"
+)
+
+// Manager compares Specs with the live configuration of an instance
+// and applies the changes.
+type Manager struct {
+	instanceSettings     *instancesettings.Client
+	domains              *domain.Client
+	redirectURLs         *redirecturl.Client
+	allowlistIdentifiers *allowlistidentifier.Client
+	blocklistIdentifiers *blocklistidentifier.Client
+	jwtTemplates         *jwttemplate.Client
+	templates            *template.Client
+}
+
+// NewManager returns a Manager which calls the Clerk API with the
+// provided backend. Use a FakeBackend to plan and apply changes
+// without a Clerk instance.
+func NewManager(backend clerk.Backend) *Manager {
+	return &Manager{
+		instanceSettings:     &instancesettings.Client{Backend: backend},
+		domains:              &domain.Client{Backend: backend},
+		redirectURLs:         &redirecturl.Client{Backend: backend},
+		allowlistIdentifiers: &allowlistidentifier.Client{Backend: backend},
+		blocklistIdentifiers: &blocklistidentifier.Client{Backend: backend},
+		jwtTemplates:         &jwttemplate.Client{Backend: backend},
+		templates:            &template.Client{Backend: backend},
+	}
+}
+
+// Plan fetches the live configuration of the parts of the instance
+// that are present in the spec, and returns the changes that are
+// needed to match the spec.
+func (m *Manager) Plan(ctx context.Context, spec *Spec) (*Plan, error) {
+	if err := spec.validate(); err != nil {
+		return nil, err
+	}
+	steps := []func(context.Context, *Spec) ([]*Change, error){
+		m.planInstance,
+		m.planRestrictions,
+		m.planOrganizationSettings,
+		m.planDomains,
+		m.planRedirectURLs,
+		m.planAllowlistIdentifiers,
+		m.planBlocklistIdentifiers,
+		m.planJWTTemplates,
+		m.planEmailTemplates,
+		m.planSMSTemplates,
+	}
+	plan := &Plan{}
+	for _, step := range steps {
+		changes, err := step(ctx, spec)
+		if err != nil {
+			return nil, err
+		}
+		plan.Changes = append(plan.Changes, changes...)
+	}
+	return plan, nil
+}
+
+// Apply applies the changes of the plan in order. It stops at the
+// first change that fails. The plan can be applied by a different
+// Manager than the one that created it. Changes that were applied are not rolled
+// back; planning again returns the remaining changes.
+func (m *Manager) Apply(ctx context.Context, plan *Plan) error {
+	for _, change := range plan.Changes {
+		err := change.apply(ctx, m)
+		if err != nil {
+			if change.Name == "" {
+				return fmt.Errorf("%s %s: %w", change.Action, change.Resource, err)
+			}
+			return fmt.Errorf("%s %s %q: %w", change.Action, change.Resource, change.Name, err)
+		}
+	}
+	return nil
+}
+
+func (m *Manager) planInstance(ctx context.Context, spec *Spec) ([]*Change, error) {
+	desired := spec.Instance
+	if desired == nil {
+		return nil, nil
+	}
+	live, err := m.instanceSettings.Get(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("get instance: %w", err)
+	}
+	var fields []FieldChange
+	diffField(&fields, "test_mode", desired.TestMode, live.TestMode)
+	diffField(&fields, "hibp", desired.HIBP, live.HIBP)
+	diffField(&fields, "enhanced_email_deliverability", desired.EnhancedEmailDeliverability, live.EnhancedEmailDeliverability)
+	diffField(&fields, "support_email", desired.SupportEmail, live.SupportEmail)
+	diffField(&fields, "clerk_js_version", desired.ClerkJSVersion, live.ClerkJSVersion)
+	diffField(&fields, "url_based_session_syncing", desired.URLBasedSessionSyncing, live.URLBasedSessionSyncing)
+	diffField(&fields, "development_origin", desired.DevelopmentOrigin, live.DevelopmentOrigin)
+	if len(fields) == 0 {
+		return nil, nil
+	}
+	params := &instancesettings.UpdateParams{
+		TestMode:                    desired.TestMode,
+		HIBP:                        desired.HIBP,
+		EnhancedEmailDeliverability: desired.EnhancedEmailDeliverability,
+		SupportEmail:                desired.SupportEmail,
+		ClerkJSVersion:              desired.ClerkJSVersion,
+		URLBasedSessionSyncing:      desired.URLBasedSessionSyncing,
+		DevelopmentOrigin:           desired.DevelopmentOrigin,
+	}
+	return []*Change{{
+		Action:   ActionUpdate,
+		Resource: ResourceInstance,
+		Fields:   fields,
+		apply: func(ctx context.Context, m *Manager) error {
+			_, err := m.instanceSettings.Update(ctx, params)
+			return err
+		},
+	}}, nil
+}
+
+func (m *Manager) planRestrictions(ctx context.Context, spec *Spec) ([]*Change, error) {
+	desired := spec.Restrictions
+	if desired == nil {
+		return nil, nil
+	}
+	live, err := m.instanceSettings.GetRestrictions(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("get restrictions: %w", err)
+	}
+	var fields []FieldChange
+	diffField(&fields, "allowlist", desired.Allowlist, &live.Allowlist)
+	diffField(&fields, "blocklist", desired.Blocklist, &live.Blocklist)
+	diffField(&fields, "block_email_subaddresses", desired.BlockEmailSubaddresses, &live.BlockEmailSubaddresses)
+	diffField(&fields, "block_disposable_email_domains", desired.BlockDisposableEmailDomains, &live.BlockDisposableEmailDomains)
+	diffField(&fields, "ignore_dots_for_gmail_addresses", desired.IgnoreDotsForGmailAddresses, &live.IgnoreDotsForGmailAddresses)
+	if len(fields) == 0 {
+		return nil, nil
+	}
+	params := &instancesettings.UpdateRestrictionsParams{
+		Allowlist:                   desired.Allowlist,
+		Blocklist:                   desired.Blocklist,
+		BlockEmailSubaddresses:      desired.BlockEmailSubaddresses,
+		BlockDisposableEmailDomains: desired.BlockDisposableEmailDomains,
+		IgnoreDotsForGmailAddresses: desired.IgnoreDotsForGmailAddresses,
+	}
+	return []*Change{{
+		Action:   ActionUpdate,
+		Resource: ResourceRestrictions,
+		Fields:   fields,
+		apply: func(ctx context.Context, m *Manager) error {
+			_, err := m.instanceSettings.UpdateRestrictions(ctx, params)
+			return err
+		},
+	}}, nil
+}
+
+func (m *Manager) planOrganizationSettings(ctx context.Context, spec *Spec) ([]*Change, error) {
+	desired := spec.OrganizationSettings
+	if desired == nil {
+		return nil, nil
+	}
+	live, err := m.instanceSettings.GetOrganizationSettings(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("get organization settings: %w", err)
+	}
+	var fields []FieldChange
+	diffField(&fields, "enabled", desired.Enabled, &live.Enabled)
+	diffField(&fields, "max_allowed_memberships", desired.MaxAllowedMemberships, &live.MaxAllowedMemberships)
+	diffField(&fields, "admin_delete_enabled", desired.AdminDeleteEnabled, &live.AdminDeleteEnabled)
+	diffField(&fields, "domains_enabled", desired.DomainsEnabled, &live.DomainsEnabled)
+	// The order of the enrollment modes doesn't matter.
+	if desired.DomainsEnrollmentModes != nil && !equalSets(*desired.DomainsEnrollmentModes, live.DomainsEnrollmentModes) {
+		fields = append(fields, FieldChange{
+			Field: "domains_enrollment_modes",
+			Old:   encodeValue(live.DomainsEnrollmentModes),
+			New:   encodeValue(*desired.DomainsEnrollmentModes),
+		})
+	}
+	if len(fields) == 0 {
+		return nil, nil
+	}
+	params := &instancesettings.UpdateOrganizationSettingsParams{
+		Enabled:                desired.Enabled,
+		MaxAllowedMemberships:  desired.MaxAllowedMemberships,
+		AdminDeleteEnabled:     desired.AdminDeleteEnabled,
+		DomainsEnabled:         desired.DomainsEnabled,
+		DomainsEnrollmentModes: desired.DomainsEnrollmentModes,
+	}
+	return []*Change{{
+		Action:   ActionUpdate,
+		Resource: ResourceOrganizationSettings,
+		Fields:   fields,
+		apply: func(ctx context.Context, m *Manager) error {
+			_, err := m.instanceSettings.UpdateOrganizationSettings(ctx, params)
+			return err
+		},
+	}}, nil
+}
+
+func (m *Manager) planDomains(ctx context.Context, spec *Spec) ([]*Change, error) {
+	if spec.Domains == nil {
+		return nil, nil
+	}
+	list, err := m.domains.List(ctx, &domain.ListParams{})
+	if err != nil {
+		return nil, fmt.Errorf("list domains: %w", err)
+	}
+	if err := checkComplete(ResourceDomain, len(list.Domains), list.TotalCount); err != nil {
+		return nil, err
+	}
+	live := map[string]*clerk.Domain{}
+	for _, d := range list.Domains {
+		live[strings.ToLower(d.Name)] = d
+	}
+
+	var changes []*Change
+	for _, desired := range spec.Domains {
+		desired := desired
+		existing, ok := live[strings.ToLower(desired.Name)]
+		if !ok {
+			fields := []FieldChange{{Field: "is_satellite", New: encodeValue(desired.IsSatellite)}}
+			if desired.ProxyURL != nil {
+				fields = append(fields, FieldChange{Field: "proxy_url", New: encodeValue(desired.ProxyURL)})
+			}
+			changes = append(changes, &Change{
+				Action:   ActionCreate,
+				Resource: ResourceDomain,
+				Name:     desired.Name,
+				Fields:   fields,
+				apply: func(ctx context.Context, m *Manager) error {
+					_, err := m.domains.Create(ctx, &domain.CreateParams{
+						Name:        clerk.String(desired.Name),
+						IsSatellite: clerk.Bool(desired.IsSatellite),
+						ProxyURL:    desired.ProxyURL,
+					})
+					return err
+				},
+			})
+			continue
+		}
+		delete(live, strings.ToLower(desired.Name))
+		if existing.IsSatellite != desired.IsSatellite {
+			return nil, fmt.Errorf("domain %q: is_satellite cannot be changed, delete the domain and create it again", desired.Name)
+		}
+		var fields []FieldChange
+		diffField(&fields, "proxy_url", desired.ProxyURL, existing.ProxyURL)
+		if len(fields) == 0 {
+			continue
+		}
+		id := existing.ID
+		changes = append(changes, &Change{
+			Action:   ActionUpdate,
+			Resource: ResourceDomain,
+			Name:     desired.Name,
+			Fields:   fields,
+			apply: func(ctx context.Context, m *Manager) error {
+				_, err := m.domains.Update(ctx, id, &domain.UpdateParams{ProxyURL: desired.ProxyURL})
+				return err
+			},
+		})
+	}
+
+	// The primary domain cannot be deleted.
+	var satellites []*clerk.Domain
+	for _, d := range live {
+		if d.IsSatellite {
+			satellites = append(satellites, d)
+		}
+	}
+	sort.Slice(satellites, func(i, j int) bool { return satellites[i].Name < satellites[j].Name })
+	for _, d := range satellites {
+		id := d.ID
+		changes = append(changes, &Change{
+			Action:   ActionDelete,
+			Resource: ResourceDomain,
+			Name:     d.Name,
+			apply: func(ctx context.Context, m *Manager) error {
+				_, err := m.domains.Delete(ctx, id)
+				return err
+			},
+		})
+	}
+	return changes, nil
+}
+
+func (m *Manager) planRedirectURLs(ctx context.Context, spec *Spec) ([]*Change, error) {
+	if spec.RedirectURLs == nil {
+		return nil, nil
+	}
+	list, err := m.redirectURLs.List(ctx, &redirecturl.ListParams{})
+	if err != nil {
+		return nil, fmt.Errorf("list redirect URLs: %w", err)
+	}
+	if err := checkComplete(ResourceRedirectURL, len(list.RedirectURLs), list.TotalCount); err != nil {
+		return nil, err
+	}
+	live := make([]namedResource, len(list.RedirectURLs))
+	for i, redirectURL := range list.RedirectURLs {
+		live[i] = namedResource{id: redirectURL.ID, name: redirectURL.URL}
+	}
+	return planSet(ResourceRedirectURL, spec.RedirectURLs, live, false,
+		func(ctx context.Context, m *Manager, url string) error {
+			_, err := m.redirectURLs.Create(ctx, &redirecturl.CreateParams{URL: clerk.String(url)})
+			return err
+		},
+		func(ctx context.Context, m *Manager, id string) error {
+			_, err := m.redirectURLs.Delete(ctx, id)
+			return err
+		},
+	), nil
+}
+
+func (m *Manager) planAllowlistIdentifiers(ctx context.Context, spec *Spec) ([]*Change, error) {
+	if spec.AllowlistIdentifiers == nil {
+		return nil, nil
+	}
+	list, err := m.allowlistIdentifiers.List(ctx, &allowlistidentifier.ListParams{})
+	if err != nil {
+		return nil, fmt.Errorf("list allowlist identifiers: %w", err)
+	}
+	if err := checkComplete(ResourceAllowlistIdentifier, len(list.AllowlistIdentifiers), list.TotalCount); err != nil {
+		return nil, err
+	}
+	live := make([]namedResource, len(list.AllowlistIdentifiers))
+	for i, identifier := range list.AllowlistIdentifiers {
+		live[i] = namedResource{id: identifier.ID, name: identifier.Identifier}
+	}
+	return planSet(ResourceAllowlistIdentifier, spec.AllowlistIdentifiers, live, true,
+		func(ctx context.Context, m *Manager, identifier string) error {
+			_, err := m.allowlistIdentifiers.Create(ctx, &allowlistidentifier.CreateParams{
+				Identifier: clerk.String(identifier),
+				Notify:     clerk.Bool(false),
+			})
+			return err
+		},
+		func(ctx context.Context, m *Manager, id string) error {
+			_, err := m.allowlistIdentifiers.Delete(ctx, id)
+			return err
+		},
+	), nil
+}
+
+func (m *Manager) planBlocklistIdentifiers(ctx context.Context, spec *Spec) ([]*Change, error) {
+	if spec.BlocklistIdentifiers == nil {
+		return nil, nil
+	}
+	list, err := m.blocklistIdentifiers.List(ctx, &blocklistidentifier.ListParams{})
+	if err != nil {
+		return nil, fmt.Errorf("list blocklist identifiers: %w", err)
+	}
+	if err := checkComplete(ResourceBlocklistIdentifier, len(list.BlocklistIdentifiers), list.TotalCount); err != nil {
+		return nil, err
+	}
+	live := make([]namedResource, len(list.BlocklistIdentifiers))
+	for i, identifier := range list.BlocklistIdentifiers {
+		live[i] = namedResource{id: identifier.ID, name: identifier.Identifier}
+	}
+	return planSet(ResourceBlocklistIdentifier, spec.BlocklistIdentifiers, live, true,
+		func(ctx context.Context, m *Manager, identifier string) error {
+			_, err := m.blocklistIdentifiers.Create(ctx, &blocklistidentifier.CreateParams{
+				Identifier: clerk.String(identifier),
+			})
+			return err
+		},
+		func(ctx context.Context, m *Manager, id string) error {
+			_, err := m.blocklistIdentifiers.Delete(ctx, id)
+			return err
+		},
+	), nil
+}
+
+func (m *Manager) planJWTTemplates(ctx context.Context, spec *Spec) ([]*Change, error) {
+	if spec.JWTTemplates == nil {
+		return nil, nil
+	}
+	list, err := m.jwtTemplates.List(ctx, &jwttemplate.ListParams{})
+	if err != nil {
+		return nil, fmt.Errorf("list JWT templates: %w", err)
+	}
+	if err := checkComplete(ResourceJWTTemplate, len(list.JWTTemplates), list.TotalCount); err != nil {
+		return nil, err
+	}
+	live := map[string]*clerk.JWTTemplate{}
+	for _, t := range list.JWTTemplates {
+		live[t.Name] = t
+	}
+
+	var changes []*Change
+	for _, desired := range spec.JWTTemplates {
+		desired := desired
+		existing, ok := live[desired.Name]
+		if !ok {
+			var fields []FieldChange
+			if len(desired.Claims) > 0 {
+				fields = append(fields, FieldChange{Field: "claims", New: encodeValue(desired.Claims)})
+			}
+			diffField(&fields, "lifetime", desired.Lifetime, nil)
+			diffField(&fields, "allowed_clock_skew", desired.AllowedClockSkew, nil)
+			diffField(&fields, "signing_algorithm", desired.SigningAlgorithm, nil)
+			changes = append(changes, &Change{
+				Action:   ActionCreate,
+				Resource: ResourceJWTTemplate,
+				Name:     desired.Name,
+				Fields:   fieldsWithoutOld(fields),
+				apply: func(ctx context.Context, m *Manager) error {
+					_, err := m.jwtTemplates.Create(ctx, &jwttemplate.CreateParams{
+						Name:             clerk.String(desired.Name),
+						Claims:           desired.Claims,
+						Lifetime:         desired.Lifetime,
+						AllowedClockSkew: desired.AllowedClockSkew,
+						SigningAlgorithm: desired.SigningAlgorithm,
+					})
+					return err
+				},
+			})
+			continue
+		}
+		delete(live, desired.Name)
+
+		var fields []FieldChange
+		if len(desired.Claims) > 0 && !equalJSON(desired.Claims, existing.Claims) {
+			fields = append(fields, FieldChange{
+				Field: "claims",
+				Old:   encodeValue(existing.Claims),
+				New:   encodeValue(desired.Claims),
+			})
+		}
+		diffField(&fields, "lifetime", desired.Lifetime, &existing.Lifetime)
+		diffField(&fields, "allowed_clock_skew", desired.AllowedClockSkew, &existing.AllowedClockSkew)
+		diffField(&fields, "signing_algorithm", desired.SigningAlgorithm, &existing.SigningAlgorithm)
+		if len(fields) == 0 {
+			continue
+		}
+		// Updates replace the template, so unmanaged fields keep their
+		// live values.
+		params := &jwttemplate.UpdateParams{
+			Name:             clerk.String(existing.Name),
+			Claims:           existing.Claims,
+			Lifetime:         clerk.Int64(existing.Lifetime),
+			AllowedClockSkew: clerk.Int64(existing.AllowedClockSkew),
+			SigningAlgorithm: stringOr(desired.SigningAlgorithm, existing.SigningAlgorithm),
+		}
+		if len(desired.Claims) > 0 {
+			params.Claims = desired.Claims
+		}
+		if desired.Lifetime != nil {
+			params.Lifetime = desired.Lifetime
+		}
+		if desired.AllowedClockSkew != nil {
+			params.AllowedClockSkew = desired.AllowedClockSkew
+		}
+		id := existing.ID
+		changes = append(changes, &Change{
+			Action:   ActionUpdate,
+			Resource: ResourceJWTTemplate,
+			Name:     desired.Name,
+			Fields:   fields,
+			apply: func(ctx context.Context, m *Manager) error {
+				_, err := m.jwtTemplates.Update(ctx, id, params)
+				return err
+			},
+		})
+	}
+
+	names := make([]string, 0, len(live))
+	for name := range live {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		id := live[name].ID
+		changes = append(changes, &Change{
+			Action:   ActionDelete,
+			Resource: ResourceJWTTemplate,
+			Name:     name,
+			apply: func(ctx context.Context, m *Manager) error {
+				_, err := m.jwtTemplates.Delete(ctx, id)
+				return err
+			},
+		})
+	}
+	return changes, nil
+}
+
+func (m *Manager) planEmailTemplates(ctx context.Context, spec *Spec) ([]*Change, error) {
+	return m.planTemplates(ctx, clerk.TemplateTypeEmail, ResourceEmailTemplate, spec.EmailTemplates)
+}
+
+func (m *Manager) planSMSTemplates(ctx context.Context, spec *Spec) ([]*Change, error) {
+	return m.planTemplates(ctx, clerk.TemplateTypeSMS, ResourceSMSTemplate, spec.SMSTemplates)
+}
+
+func (m *Manager) planTemplates(ctx context.Context, templateType clerk.TemplateType, resource string, specs []*TemplateSpec) ([]*Change, error) {
+	if specs == nil {
+		return nil, nil
+	}
+	list, err := m.templates.List(ctx, &template.ListParams{TemplateType: templateType})
+	if err != nil {
+		return nil, fmt.Errorf("list %s templates: %w", templateType, err)
+	}
+	if err := checkComplete(resource, len(list.Templates), list.TotalCount); err != nil {
+		return nil, err
+	}
+	live := map[string]*clerk.Template{}
+	for _, t := range list.Templates {
+		live[t.Slug] = t
+	}
+
+	var changes []*Change
+	for _, desired := range specs {
+		existing, ok := live[desired.Slug]
+		if !ok {
+			return nil, fmt.Errorf("unknown %s template %q", templateType, desired.Slug)
+		}
+		var fields []FieldChange
+		diffField(&fields, "name", desired.Name, &existing.Name)
+		diffField(&fields, "subject", desired.Subject, &existing.Subject)
+		diffField(&fields, "markup", desired.Markup, &existing.Markup)
+		diffField(&fields, "body", desired.Body, &existing.Body)
+		diffField(&fields, "from_email_name", desired.FromEmailName, existing.FromEmailName)
+		diffField(&fields, "reply_to_email_name", desired.ReplyToEmailName, existing.ReplyToEmailName)
+		diffField(&fields, "delivered_by_clerk", desired.DeliveredByClerk, &existing.DeliveredByClerk)
+		if len(fields) == 0 {
+			continue
+		}
+		// Templates are replaced by updates, so unmanaged fields keep
+		// their live values.
+		params := &template.UpdateParams{
+			TemplateType:     templateType,
+			Slug:             desired.Slug,
+			Name:             stringOr(desired.Name, existing.Name),
+			Subject:          stringOr(desired.Subject, existing.Subject),
+			Markup:           stringOr(desired.Markup, existing.Markup),
+			Body:             stringOr(desired.Body, existing.Body),
+			FromEmailName:    desired.FromEmailName,
+			ReplyToEmailName: desired.ReplyToEmailName,
+			DeliveredByClerk: desired.DeliveredByClerk,
+		}
+		if params.FromEmailName == nil {
+			params.FromEmailName = existing.FromEmailName
+		}
+		if params.ReplyToEmailName == nil {
+			params.ReplyToEmailName = existing.ReplyToEmailName
+		}
+		changes = append(changes, &Change{
+			Action:   ActionUpdate,
+			Resource: resource,
+			Name:     desired.Slug,
+			Fields:   fields,
+			apply: func(ctx context.Context, m *Manager) error {
+				_, err := m.templates.Update(ctx, params)
+				return err
+			},
+		})
+	}
+	return changes, nil
+}
+
+// A live resource that is identified by its name in the Spec.
+type namedResource struct {
+	id   string
+	name string
+}
+
+// Plans the changes for a set of resources that can only be created
+// or deleted.
+func planSet(
+	resource string,
+	desired []string,
+	live []namedResource,
+	fold bool,
+	create func(ctx context.Context, m *Manager, name string) error,
+	remove func(ctx context.Context, m *Manager, id string) error,
+) []*Change {
+	key := func(name string) string {
+		if fold {
+			return strings.ToLower(name)
+		}
+		return name
+	}
+	remaining := map[string]namedResource{}
+	for _, r := range live {
+		remaining[key(r.name)] = r
+	}
+
+	var changes []*Change
+	for _, name := range desired {
+		name := name
+		if _, ok := remaining[key(name)]; ok {
+			delete(remaining, key(name))
+			continue
+		}
+		changes = append(changes, &Change{
+			Action:   ActionCreate,
+			Resource: resource,
+			Name:     name,
+			apply: func(ctx context.Context, m *Manager) error {
+				return create(ctx, m, name)
+			},
+		})
+	}
+
+	deleted := make([]namedResource, 0, len(remaining))
+	for _, r := range remaining {
+		deleted = append(deleted, r)
+	}
+	sort.Slice(deleted, func(i, j int) bool { return deleted[i].name < deleted[j].name })
+	for _, r := range deleted {
+		id := r.id
+		changes = append(changes, &Change{
+			Action:   ActionDelete,
+			Resource: resource,
+			Name:     r.name,
+			apply: func(ctx context.Context, m *Manager) error {
+				return remove(ctx, m, id)
+			},
+		})
+	}
+	return changes
+}
+
+// Lists are not paginated, so planning fails instead of deleting or
+// duplicating resources that were not returned.
+func checkComplete(resource string, listed int, totalCount int64) error {
+	if int64(listed) < totalCount {
+		return fmt.Errorf("list %s: received %d of %d resources", resource, listed, totalCount)
+	}
+	return nil
+}
+
+// Adds a FieldChange if the desired value is set and differs from
+// the live value.
+func diffField[T comparable](fields *[]FieldChange, field string, desired, live *T) {
+	if desired == nil {
+		return
+	}
+	if live != nil && *live == *desired {
+		return
+	}
+	*fields = append(*fields, FieldChange{
+		Field: field,
+		Old:   encodeValue(live),
+		New:   encodeValue(desired),
+	})
+}
+
+func fieldsWithoutOld(fields []FieldChange) []FieldChange {
+	for i := range fields {
+		fields[i].Old = ""
+	}
+	return fields
+}
+
+// Returns the value, or the fallback if the value is nil. Empty
+// fallbacks result in nil.
+func stringOr(value *string, fallback string) *string {
+	if value != nil {
+		return value
+	}
+	if fallback == "" {
+		return nil
+	}
+	return clerk.String(fallback)
+}
+
+func equalSets(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	sortedA := append([]string{}, a...)
+	sortedB := append([]string{}, b...)
+	sort.Strings(sortedA)
+	sort.Strings(sortedB)
+	return reflect.DeepEqual(sortedA, sortedB)
+}
+
+func equalJSON(a, b json.RawMessage) bool {
+	var docA, docB any
+	if err := json.Unmarshal(a, &docA); err != nil {
+		return false
+	}
+	if err := json.Unmarshal(b, &docB); err != nil {
+		return false
+	}
+	return reflect.DeepEqual(docA, docB)
+}
@@ -0,0 +1,252 @@
+package instanceconfig
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/clerk/clerk-sdk-go/v2"
+	"github.com/stretchr/testify/require"
+)
+
+const testSpec = `
+instance:
+  hibp: false
+  support_email: support@example.com
+restrictions:
+  allowlist: true
+organization_settings:
+  enabled: true
+  domains_enrollment_modes: [automatic_invitation, manual_invitation]
+domains:
+  - name: example.com
+  - name: satellite.example.com
+    is_satellite: true
+    proxy_url: https://satellite.example.com/__clerk
+redirect_urls:
+  - https://example.com/callback
+  - https://example.com/new
+allowlist_identifiers:
+  - "*@example.com"
+blocklist_identifiers: []
+jwt_templates:
+  - name: downstream
+    lifetime: 120
+    claims:
+      aud: downstream
+  - name: analytics
+    claims:
+      email: "{{user.primary_email_address}}"
+email_templates:
+  - slug: verification_code
+    subject: Your verification code
+sms_templates:
+  - slug: verification_code
+    name: Verification code
+    body: "{{otp_code}} is your code"
+`
+
+// Returns a fake backend for an instance which has drifted from the
+// test spec.
+func newDriftedBackend() *FakeBackend {
+	backend := NewFakeBackend()
+	backend.Instance.HIBP = clerk.Bool(true)
+	backend.Instance.SupportEmail = clerk.String("support@example.com")
+	backend.OrganizationSettings.Enabled = true
+	backend.OrganizationSettings.DomainsEnrollmentModes = []string{"manual_invitation", "automatic_invitation"}
+	backend.Domains = []*clerk.Domain{
+		{ID: "dmn_primary", Name: "example.com"},
+		{ID: "dmn_old", Name: "old.example.com", IsSatellite: true},
+	}
+	backend.RedirectURLs = []*clerk.RedirectURL{
+		{ID: "redir_callback", URL: "https://example.com/callback"},
+		{ID: "redir_old", URL: "https://example.com/old"},
+	}
+	backend.AllowlistIdentifiers = []*clerk.AllowlistIdentifier{
+		{ID: "alid_1", Identifier: "*@EXAMPLE.com"},
+	}
+	backend.BlocklistIdentifiers = []*clerk.BlocklistIdentifier{
+		{ID: "blid_1", Identifier: "spam@example.com"},
+	}
+	backend.JWTTemplates = []*clerk.JWTTemplate{
+		{ID: "jtmp_downstream", Name: "downstream", Lifetime: 60, Claims: json.RawMessage(`{ "aud": "downstream" }`)},
+		{ID: "jtmp_legacy", Name: "legacy", Lifetime: 60, Claims: json.RawMessage(`{}`)},
+	}
+	backend.Templates = []*clerk.Template{
+		{Slug: "verification_code", TemplateType: clerk.TemplateTypeEmail, Name: "Verification code", Subject: "{{otp_code}} is your code", Markup: "<p>{{otp_code}}</p>"},
+		{Slug: "verification_code", TemplateType: clerk.TemplateTypeSMS, Body: "{{otp_code}}"},
+	}
+	return backend
+}
+
+func TestManagerPlan(t *testing.T) {
+	t.Parallel()
+	spec, err := Parse([]byte(testSpec))
+	require.NoError(t, err)
+	manager := NewManager(newDriftedBackend())
+	plan, err := manager.Plan(context.Background(), spec)
+	require.NoError(t, err)
+
+	var summary []string
+	for _, change := range plan.Changes {
+		summary = append(summary, change.String())
+	}
+	require.Equal(t, []string{
+		`~ instance`,
+		`~ restrictions`,
+		`+ domain "satellite.example.com"`,
+		`- domain "old.example.com"`,
+		`+ redirect_url "https://example.com/new"`,
+		`- redirect_url "https://example.com/old"`,
+		`- blocklist_identifier "spam@example.com"`,
+		`~ jwt_template "downstream"`,
+		`+ jwt_template "analytics"`,
+		`- jwt_template "legacy"`,
+		`~ email_template "verification_code"`,
+		`~ sms_template "verification_code"`,
+	}, summary)
+	require.Equal(t, []FieldChange{{Field: "hibp", Old: "true", New: "false"}}, plan.Changes[0].Fields)
+	require.Equal(t, []FieldChange{{Field: "lifetime", Old: "60", New: "120"}}, plan.Changes[7].Fields)
+
+	output := plan.String()
+	require.Contains(t, output, "~ instance\n    hibp: true -> false\n")
+	require.Contains(t, output, "+ domain \"satellite.example.com\"\n    is_satellite: true\n    proxy_url: \"https://satellite.example.com/__clerk\"\n")
+	require.Contains(t, output, "    subject: \"{{otp_code}} is your code\" -> \"Your verification code\"\n")
+	require.True(t, strings.HasSuffix(output, "Plan: 3 to create, 5 to update, 4 to delete.\n"))
+}
+
+func TestManagerApply(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	spec, err := Parse([]byte(testSpec))
+	require.NoError(t, err)
+	backend := newDriftedBackend()
+	manager := NewManager(backend)
+	plan, err := manager.Plan(ctx, spec)
+	require.NoError(t, err)
+	require.NoError(t, manager.Apply(ctx, plan))
+
+	require.False(t, *backend.Instance.HIBP)
+	require.True(t, backend.Restrictions.Allowlist)
+	require.Equal(t, 2, len(backend.Domains))
+	require.Equal(t, "satellite.example.com", backend.Domains[1].Name)
+	require.Equal(t, "https://satellite.example.com/__clerk", *backend.Domains[1].ProxyURL)
+	require.Equal(t, 2, len(backend.RedirectURLs))
+	require.Equal(t, "https://example.com/new", backend.RedirectURLs[1].URL)
+	require.Empty(t, backend.BlocklistIdentifiers)
+	require.Equal(t, 2, len(backend.JWTTemplates))
+	require.Equal(t, int64(120), backend.JWTTemplates[0].Lifetime)
+	require.JSONEq(t, `{"aud":"downstream"}`, string(backend.JWTTemplates[0].Claims))
+	require.Equal(t, "analytics", backend.JWTTemplates[1].Name)
+	require.Equal(t, "Your verification code", backend.Templates[0].Subject)
+	// Unmanaged template fields keep their values.
+	require.Equal(t, "<p>{{otp_code}}</p>", backend.Templates[0].Markup)
+	require.Equal(t, "Verification code", backend.Templates[0].Name)
+	require.Equal(t, "Verification code", backend.Templates[1].Name)
+	require.Equal(t, "{{otp_code}} is your code", backend.Templates[1].Body)
+	require.Equal(t, 2, len(backend.Templates))
+
+	// Applying the spec again doesn't change anything.
+	plan, err = manager.Plan(ctx, spec)
+	require.NoError(t, err)
+	require.True(t, plan.Empty())
+	require.Equal(t, "No changes.\n", plan.String())
+	requests := len(backend.Requests)
+	require.NoError(t, manager.Apply(ctx, plan))
+	require.Equal(t, requests, len(backend.Requests))
+}
+
+func TestManagerPlan_Unmanaged(t *testing.T) {
+	t.Parallel()
+	backend := newDriftedBackend()
+	manager := NewManager(backend)
+	plan, err := manager.Plan(context.Background(), &Spec{})
+	require.NoError(t, err)
+	require.True(t, plan.Empty())
+	require.Empty(t, backend.Requests)
+}
+
+func TestManagerPlan_Errors(t *testing.T) {
+	t.Parallel()
+	backend := newDriftedBackend()
+	manager := NewManager(backend)
+	_, err := manager.Plan(context.Background(), &Spec{
+		Domains: []*DomainSpec{{Name: "old.example.com"}},
+	})
+	require.ErrorContains(t, err, `domain "old.example.com": is_satellite cannot be changed`)
+
+	_, err = manager.Plan(context.Background(), &Spec{
+		RedirectURLs: []string{""},
+	})
+	require.ErrorContains(t, err, "missing identifier")
+
+	// Templates cannot be created.
+	_, err = manager.Plan(context.Background(), &Spec{
+		SMSTemplates: []*TemplateSpec{{Slug: "custom"}},
+	})
+	require.ErrorContains(t, err, `unknown sms template "custom"`)
+
+	// Incomplete lists cannot be compared.
+	_, err = NewManager(&truncatingBackend{backend}).Plan(context.Background(), &Spec{
+		RedirectURLs: []string{},
+	})
+	require.ErrorContains(t, err, "list redirect_url: received 1 of 2 resources")
+}
+
+func TestManagerApply_Error(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	backend := NewFakeBackend()
+	manager := NewManager(backend)
+	plan, err := manager.Plan(ctx, &Spec{
+		RedirectURLs: []string{"https://example.com/a", "https://example.com/b"},
+	})
+	require.NoError(t, err)
+
+	failing := NewManager(&failingBackend{backend: backend, path: "/redirect_urls", failAfter: 1})
+	err = failing.Apply(ctx, plan)
+	require.ErrorContains(t, err, `create redirect_url "https://example.com/b"`)
+	var apiErr *clerk.APIErrorResponse
+	require.True(t, errors.As(err, &apiErr))
+	require.Equal(t, 1, len(backend.RedirectURLs))
+
+	// Planning again returns the remaining changes.
+	plan, err = manager.Plan(ctx, &Spec{
+		RedirectURLs: []string{"https://example.com/a", "https://example.com/b"},
+	})
+	require.NoError(t, err)
+	require.Equal(t, 1, len(plan.Changes))
+	require.Equal(t, `+ redirect_url "https://example.com/b"`, plan.Changes[0].String())
+}
+
+// Drops all but the first item from list responses.
+type truncatingBackend struct {
+	*FakeBackend
+}
+
+func (b *truncatingBackend) Call(ctx context.Context, req *clerk.APIRequest, resource clerk.ResponseReader) error {
+	err := b.FakeBackend.Call(ctx, req, resource)
+	if list, ok := resource.(*clerk.RedirectURLList); ok && len(list.RedirectURLs) > 1 {
+		list.RedirectURLs = list.RedirectURLs[:1]
+	}
+	return err
+}
+
+// Fails POST requests to the path after a number of successful ones.
+type failingBackend struct {
+	backend   *FakeBackend
+	path      string
+	failAfter int
+}
+
+func (b *failingBackend) Call(ctx context.Context, req *clerk.APIRequest, resource clerk.ResponseReader) error {
+	if req.Method == "POST" && req.Path == b.path {
+		if b.failAfter == 0 {
+			return &clerk.APIErrorResponse{Errors: []clerk.Error{{Code: "internal_error"}}}
+		}
+		b.failAfter--
+	}
+	return b.backend.Call(ctx, req, resource)
+}
@@ -0,0 +1,154 @@
+package instanceconfig
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Action is the kind of operation of a Change.
+type Action string
+
+const (
+	ActionCreate Action = "create"
+	ActionUpdate Action = "update"
+	ActionDelete Action = "delete"
+)
+
+// Resource kinds that are reported in changes.
+const (
+	ResourceInstance             = "instance"
+	ResourceRestrictions         = "restrictions"
+	ResourceOrganizationSettings = "organization_settings"
+	ResourceDomain               = "domain"
+	ResourceRedirectURL          = "redirect_url"
+	ResourceAllowlistIdentifier  = "allowlist_identifier"
+	ResourceBlocklistIdentifier  = "blocklist_identifier"
+	ResourceJWTTemplate          = "jwt_template"
+	ResourceEmailTemplate        = "email_template"
+	ResourceSMSTemplate          = "sms_template"
+)
+
+// Plan holds the changes that are needed to bring a live instance in
+// line with a Spec. Plans are created with Manager.Plan.
+type Plan struct {
+	Changes []*Change
+}
+
+// Change is a single operation of a Plan.
+type Change struct {
+	Action   Action
+	Resource string
+	// Name identifies the resource, for example the URL of a redirect
+	// URL or the name of a JWT template. It's empty for the instance
+	// wide settings.
+	Name string
+	// Fields lists the fields that are changed by updates, or set by
+	// creates.
+	Fields []FieldChange
+
+	apply func(ctx context.Context, m *Manager) error
+}
+
+// FieldChange describes the change of a single field. Values are
+// encoded as JSON. Old is empty for fields of created resources.
+type FieldChange struct {
+	Field string
+	Old   string
+	New   string
+}
+
+// Empty reports whether the plan has no changes.
+func (p *Plan) Empty() bool {
+	return len(p.Changes) == 0
+}
+
+// String formats the plan for humans, one change per line followed
+// by the changed fields.
+//
+//	~ instance
+//	    hibp: true -> false
+//	+ redirect_url "https://example.com/callback"
+//	- jwt_template "legacy"
+func (p *Plan) String() string {
+	if p.Empty() {
+		return "No changes.\n"
+	}
+	var b strings.Builder
+	for _, change := range p.Changes {
+		b.WriteString(change.String())
+		b.WriteString("\n")
+		for _, field := range change.Fields {
+			if change.Action == ActionCreate {
+				fmt.Fprintf(&b, "    %s: %s\n", field.Field, formatValue(field.New))
+			} else {
+				fmt.Fprintf(&b, "    %s: %s -> %s\n", field.Field, formatValue(field.Old), formatValue(field.New))
+			}
+		}
+	}
+	var creates, updates, deletes int
+	for _, change := range p.Changes {
+		switch change.Action {
+		case ActionCreate:
+			creates++
+		case ActionUpdate:
+			updates++
+		case ActionDelete:
+			deletes++
+		}
+	}
+	fmt.Fprintf(&b, "Plan: %d to create, %d to update, %d to delete.\n", creates, updates, deletes)
+	return b.String()
+}
+
+// String returns a one-line description of the change.
+func (c *Change) String() string {
+	var symbol string
+	switch c.Action {
+	case ActionCreate:
+		symbol = "+"
+	case ActionUpdate:
+		symbol = "~"
+	case ActionDelete:
+		symbol = "-"
+	}
+	if c.Name == "" {
+		return fmt.Sprintf("%s %s", symbol, c.Resource)
+	}
+	return fmt.Sprintf("%s %s %q", symbol, c.Resource, c.Name)
+}
+
+// The maximum length of values in the plan output.
+const maxValueLength = 80
+
+// Shortens long values, like template markup, so that the plan
+// stays readable.
+func formatValue(value string) string {
+	if value == "" {
+		return "(unset)"
+	}
+	if len(value) <= maxValueLength {
+		return value
+	}
+	return fmt.Sprintf("%s... (%d bytes)", value[:maxValueLength], len(value))
+}
+
+// Encodes a value for a FieldChange.
+func encodeValue(v any) string {
+	if raw, ok := v.(json.RawMessage); ok {
+		var b bytes.Buffer
+		if err := json.Compact(&b, raw); err != nil {
+			return string(raw)
+		}
+		return b.String()
+	}
+	var b bytes.Buffer
+	enc := json.NewEncoder(&b)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(v); err != nil {
+		return fmt.Sprint(v)
+	}
+	return strings.TrimSpace(b.String())
+}
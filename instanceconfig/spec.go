@@ -0,0 +1,203 @@
+// Package instanceconfig manages the configuration of a Clerk
+// instance declaratively. A Spec describes the desired
+// configuration, a Manager compares it with the live instance and
+// produces a Plan with the changes, which can be printed or applied.
+//
+//	spec, err := instanceconfig.LoadFile("clerk.yaml")
+//	manager := instanceconfig.NewManager(clerk.NewBackend(&clerk.BackendConfig{}))
+//	plan, err := manager.Plan(ctx, spec)
+//	fmt.Print(plan)
+//	err = manager.Apply(ctx, plan)
+//
+// Only the parts of the configuration that are present in the Spec
+// are managed. Lists of resources, like redirect URLs or JWT
+// templates, describe the complete set of resources, so live
+// resources that are missing from a list are deleted. Use an empty
+// list to delete all resources of a kind, and omit the list to leave
+// them unmanaged.
+package instanceconfig
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Spec describes the desired configuration of a Clerk instance.
+type Spec struct {
+	Instance             *InstanceSpec             `json:"instance,omitempty"`
+	Restrictions         *RestrictionsSpec         `json:"restrictions,omitempty"`
+	OrganizationSettings *OrganizationSettingsSpec `json:"organization_settings,omitempty"`
+	Domains              []*DomainSpec             `json:"domains,omitempty"`
+	RedirectURLs         []string                  `json:"redirect_urls,omitempty"`
+	AllowlistIdentifiers []string                  `json:"allowlist_identifiers,omitempty"`
+	BlocklistIdentifiers []string                  `json:"blocklist_identifiers,omitempty"`
+	JWTTemplates         []*JWTTemplateSpec        `json:"jwt_templates,omitempty"`
+	EmailTemplates       []*TemplateSpec           `json:"email_templates,omitempty"`
+	SMSTemplates         []*TemplateSpec           `json:"sms_templates,omitempty"`
+}
+
+// InstanceSpec holds the instance settings. Nil fields are not
+// managed.
+type InstanceSpec struct {
+	TestMode                    *bool   `json:"test_mode,omitempty"`
+	HIBP                        *bool   `json:"hibp,omitempty"`
+	EnhancedEmailDeliverability *bool   `json:"enhanced_email_deliverability,omitempty"`
+	SupportEmail                *string `json:"support_email,omitempty"`
+	ClerkJSVersion              *string `json:"clerk_js_version,omitempty"`
+	URLBasedSessionSyncing      *bool   `json:"url_based_session_syncing,omitempty"`
+	DevelopmentOrigin           *string `json:"development_origin,omitempty"`
+}
+
+// RestrictionsSpec holds the instance restrictions. Nil fields are
+// not managed.
+type RestrictionsSpec struct {
+	Allowlist                   *bool `json:"allowlist,omitempty"`
+	Blocklist                   *bool `json:"blocklist,omitempty"`
+	BlockEmailSubaddresses      *bool `json:"block_email_subaddresses,omitempty"`
+	BlockDisposableEmailDomains *bool `json:"block_disposable_email_domains,omitempty"`
+	IgnoreDotsForGmailAddresses *bool `json:"ignore_dots_for_gmail_addresses,omitempty"`
+}
+
+// OrganizationSettingsSpec holds the organization settings. Nil
+// fields are not managed.
+type OrganizationSettingsSpec struct {
+	Enabled                *bool     `json:"enabled,omitempty"`
+	MaxAllowedMemberships  *int64    `json:"max_allowed_memberships,omitempty"`
+	AdminDeleteEnabled     *bool     `json:"admin_delete_enabled,omitempty"`
+	DomainsEnabled         *bool     `json:"domains_enabled,omitempty"`
+	DomainsEnrollmentModes *[]string `json:"domains_enrollment_modes,omitempty"`
+}
+
+// DomainSpec describes a domain of the instance. Domains are
+// identified by their name. The primary domain is never deleted.
+type DomainSpec struct {
+	Name        string  `json:"name"`
+	IsSatellite bool    `json:"is_satellite,omitempty"`
+	ProxyURL    *string `json:"proxy_url,omitempty"`
+}
+
+// JWTTemplateSpec describes a JWT template. Templates are identified
+// by their name. Nil fields are not managed.
+type JWTTemplateSpec struct {
+	Name             string          `json:"name"`
+	Claims           json.RawMessage `json:"claims,omitempty"`
+	Lifetime         *int64          `json:"lifetime,omitempty"`
+	AllowedClockSkew *int64          `json:"allowed_clock_skew,omitempty"`
+	SigningAlgorithm *string         `json:"signing_algorithm,omitempty"`
+}
+
+// TemplateSpec describes an email or SMS template. Templates are
+// identified by their slug. Templates that are missing from the Spec
+// are not deleted, since the built-in templates cannot be removed,
+// and templates that don't exist on the instance cannot be created.
+// Nil fields are not managed.
+type TemplateSpec struct {
+	Slug             string  `json:"slug"`
+	Name             *string `json:"name,omitempty"`
+	Subject          *string `json:"subject,omitempty"`
+	Markup           *string `json:"markup,omitempty"`
+	Body             *string `json:"body,omitempty"`
+	FromEmailName    *string `json:"from_email_name,omitempty"`
+	ReplyToEmailName *string `json:"reply_to_email_name,omitempty"`
+	DeliveredByClerk *bool   `json:"delivered_by_clerk,omitempty"`
+}
+
+// LoadFile reads a Spec from a YAML or JSON file.
+func LoadFile(name string) (*Spec, error) {
+	data, err := os.ReadFile(name)
+	if err != nil {
+		return nil, err
+	}
+	spec, err := Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+	return spec, nil
+}
+
+// Parse decodes a Spec from YAML or JSON data. Unknown fields are
+// rejected, so that typos don't go unnoticed.
+func Parse(data []byte) (*Spec, error) {
+	// YAML is a superset of JSON. The document is converted to JSON,
+	// so that the Spec types only need JSON tags and JWT template
+	// claims can be kept as raw JSON.
+	var doc any
+	err := yaml.Unmarshal(data, &doc)
+	if err != nil {
+		return nil, fmt.Errorf("parse spec: %w", err)
+	}
+	if doc == nil {
+		return &Spec{}, nil
+	}
+	jsonData, err := json.Marshal(doc)
+	if err != nil {
+		return nil, fmt.Errorf("parse spec: %w", err)
+	}
+	decoder := json.NewDecoder(bytes.NewReader(jsonData))
+	decoder.DisallowUnknownFields()
+	spec := &Spec{}
+	err = decoder.Decode(spec)
+	if err != nil {
+		return nil, fmt.Errorf("parse spec: %w", err)
+	}
+	err = spec.validate()
+	if err != nil {
+		return nil, err
+	}
+	return spec, nil
+}
+
+// Checks that resources have identifiers and that there are no
+// duplicates.
+func (spec *Spec) validate() error {
+	lists := []struct {
+		name string
+		keys []string
+		fold bool
+	}{
+		{name: "domains", keys: mapSlice(spec.Domains, func(d *DomainSpec) string { return d.Name })},
+		{name: "redirect_urls", keys: spec.RedirectURLs},
+		{name: "allowlist_identifiers", keys: spec.AllowlistIdentifiers, fold: true},
+		{name: "blocklist_identifiers", keys: spec.BlocklistIdentifiers, fold: true},
+		{name: "jwt_templates", keys: mapSlice(spec.JWTTemplates, func(t *JWTTemplateSpec) string { return t.Name })},
+		{name: "email_templates", keys: mapSlice(spec.EmailTemplates, func(t *TemplateSpec) string { return t.Slug })},
+		{name: "sms_templates", keys: mapSlice(spec.SMSTemplates, func(t *TemplateSpec) string { return t.Slug })},
+	}
+	for _, list := range lists {
+		seen := map[string]bool{}
+		for i, key := range list.keys {
+			if key == "" {
+				return fmt.Errorf("invalid spec: %s[%d]: missing identifier", list.name, i)
+			}
+			if list.fold {
+				key = strings.ToLower(key)
+			}
+			if seen[key] {
+				return fmt.Errorf("invalid spec: %s: duplicate entry %q", list.name, key)
+			}
+			seen[key] = true
+		}
+	}
+	for _, template := range spec.JWTTemplates {
+		if len(template.Claims) > 0 && !json.Valid(template.Claims) {
+			return fmt.Errorf("invalid spec: jwt_templates: %q: invalid claims", template.Name)
+		}
+	}
+	return nil
+}
+
+func mapSlice[T any](items []T, fn func(T) string) []string {
+	if items == nil {
+		return nil
+	}
+	values := make([]string, len(items))
+	for i, item := range items {
+		values[i] = fn(item)
+	}
+	return values
+}
@@ -0,0 +1,101 @@
+package instanceconfig
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse_YAML(t *testing.T) {
+	t.Parallel()
+	spec, err := Parse([]byte(`
+instance:
+  hibp: true
+  support_email: support@example.com
+restrictions:
+  allowlist: false
+organization_settings:
+  max_allowed_memberships: 10
+  domains_enrollment_modes: [manual_invitation, automatic_invitation]
+redirect_urls:
+  - https://example.com/callback
+allowlist_identifiers: []
+jwt_templates:
+  - name: downstream
+    lifetime: 60
+    claims:
+      aud: downstream
+      roles: ["{{org.role}}"]
+email_templates:
+  - slug: verification_code
+    subject: Your code
+`))
+	require.NoError(t, err)
+	require.True(t, *spec.Instance.HIBP)
+	require.Equal(t, "support@example.com", *spec.Instance.SupportEmail)
+	require.Nil(t, spec.Instance.TestMode)
+	require.False(t, *spec.Restrictions.Allowlist)
+	require.Equal(t, int64(10), *spec.OrganizationSettings.MaxAllowedMemberships)
+	require.Equal(t, []string{"manual_invitation", "automatic_invitation"}, *spec.OrganizationSettings.DomainsEnrollmentModes)
+	require.Equal(t, []string{"https://example.com/callback"}, spec.RedirectURLs)
+	// Empty lists are managed, missing lists are not.
+	require.NotNil(t, spec.AllowlistIdentifiers)
+	require.Empty(t, spec.AllowlistIdentifiers)
+	require.Nil(t, spec.BlocklistIdentifiers)
+	require.Equal(t, "downstream", spec.JWTTemplates[0].Name)
+	require.Equal(t, int64(60), *spec.JWTTemplates[0].Lifetime)
+	require.JSONEq(t, `{"aud":"downstream","roles":["{{org.role}}"]}`, string(spec.JWTTemplates[0].Claims))
+	require.Equal(t, "Your code", *spec.EmailTemplates[0].Subject)
+}
+
+func TestParse_JSON(t *testing.T) {
+	t.Parallel()
+	spec, err := Parse([]byte(`{"restrictions":{"blocklist":true},"blocklist_identifiers":["spam@example.com"]}`))
+	require.NoError(t, err)
+	require.True(t, *spec.Restrictions.Blocklist)
+	require.Equal(t, []string{"spam@example.com"}, spec.BlocklistIdentifiers)
+}
+
+func TestParse_Empty(t *testing.T) {
+	t.Parallel()
+	spec, err := Parse(nil)
+	require.NoError(t, err)
+	require.Equal(t, &Spec{}, spec)
+}
+
+func TestParse_Invalid(t *testing.T) {
+	t.Parallel()
+	for _, tc := range []struct {
+		name string
+		data string
+		err  string
+	}{
+		{name: "unknown field", data: "instance:\n  hibpp: true\n", err: `unknown field "hibpp"`},
+		{name: "invalid yaml", data: "instance: [", err: "parse spec"},
+		{name: "missing name", data: "jwt_templates:\n  - lifetime: 60\n", err: "jwt_templates[0]: missing identifier"},
+		{name: "duplicate", data: "allowlist_identifiers: [a@example.com, A@example.com]\n", err: `allowlist_identifiers: duplicate entry "a@example.com"`},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			_, err := Parse([]byte(tc.data))
+			require.Error(t, err)
+			require.Contains(t, err.Error(), tc.err)
+		})
+	}
+}
+
+func TestLoadFile(t *testing.T) {
+	t.Parallel()
+	name := filepath.Join(t.TempDir(), "clerk.yaml")
+	require.NoError(t, os.WriteFile(name, []byte("redirect_urls: [https://example.com]\n"), 0o600))
+	spec, err := LoadFile(name)
+	require.NoError(t, err)
+	require.Equal(t, []string{"https://example.com"}, spec.RedirectURLs)
+
+	require.NoError(t, os.WriteFile(name, []byte("unknown: true\n"), 0o600))
+	_, err = LoadFile(name)
+	require.ErrorContains(t, err, name)
+}
@@ -8,16 +8,34 @@ import (
 	"github.com/clerk/clerk-sdk-go/v2"
 )
 
-// Update updates the instance's settings.
-func Update(ctx context.Context, params *UpdateParams) error {
+// Get retrieves the instance and its settings.
+func Get(ctx context.Context) (*clerk.Instance, error) {
+	return getClient().Get(ctx)
+}
+
+// Update updates the instance's settings and returns the updated
+// instance.
+// The instance is fetched with Get if the Clerk API doesn't respond
+// with the updated resource.
+func Update(ctx context.Context, params *UpdateParams) (*clerk.Instance, error) {
 	return getClient().Update(ctx, params)
 }
 
+// GetRestrictions retrieves the restriction settings of the instance.
+func GetRestrictions(ctx context.Context) (*clerk.InstanceRestrictions, error) {
+	return getClient().GetRestrictions(ctx)
+}
+
 // UpdateRestrictions updates the restriction settings of the instance.
 func UpdateRestrictions(ctx context.Context, params *UpdateRestrictionsParams) (*clerk.InstanceRestrictions, error) {
 	return getClient().UpdateRestrictions(ctx, params)
 }
 
+// GetOrganizationSettings retrieves the organization settings of the instance.
+func GetOrganizationSettings(ctx context.Context) (*clerk.OrganizationSettings, error) {
+	return getClient().GetOrganizationSettings(ctx)
+}
+
 // UpdateOrganizationSettings updates the organization settings of the instance.
 func UpdateOrganizationSettings(ctx context.Context, params *UpdateOrganizationSettingsParams) (*clerk.OrganizationSettings, error) {
 	return getClient().UpdateOrganizationSettings(ctx, params)
@@ -23,6 +23,14 @@ func NewClient(config *clerk.ClientConfig) *Client {
 	}
 }
 
+// Get retrieves the instance and its settings.
+func (c *Client) Get(ctx context.Context) (*clerk.Instance, error) {
+	req := clerk.NewAPIRequest(http.MethodGet, path)
+	instance := &clerk.Instance{}
+	err := c.Backend.Call(ctx, req, instance)
+	return instance, err
+}
+
 type UpdateParams struct {
 	clerk.APIParams
 	// TestMode can be used to toggle test mode for this instance.
@@ -69,12 +77,34 @@ type UpdateParams struct {
 	DevelopmentOrigin *string `json:"development_origin,omitempty"`
 }
 
-// Update updates the instance's settings.
-func (c *Client) Update(ctx context.Context, params *UpdateParams) error {
+// Update updates the instance's settings and returns the updated
+// instance.
+// The instance is fetched with Get if the Clerk API doesn't respond
+// with the updated resource.
+func (c *Client) Update(ctx context.Context, params *UpdateParams) (*clerk.Instance, error) {
 	req := clerk.NewAPIRequest(http.MethodPatch, path)
 	req.SetParams(params)
-	err := c.Backend.Call(ctx, req, &clerk.APIResource{})
-	return err
+	instance := &clerk.Instance{}
+	err := c.Backend.Call(ctx, req, instance)
+	if err != nil {
+		return instance, err
+	}
+	if instance.Response == nil || len(instance.Response.RawJSON) == 0 {
+		return c.Get(ctx)
+	}
+	return instance, nil
+}
+
+// GetRestrictions retrieves the restriction settings of the instance.
+func (c *Client) GetRestrictions(ctx context.Context) (*clerk.InstanceRestrictions, error) {
+	path, err := clerk.JoinPath(path, "/restrictions")
+	if err != nil {
+		return nil, err
+	}
+	req := clerk.NewAPIRequest(http.MethodGet, path)
+	instanceRestrictions := &clerk.InstanceRestrictions{}
+	err = c.Backend.Call(ctx, req, instanceRestrictions)
+	return instanceRestrictions, err
 }
 
 type UpdateRestrictionsParams struct {
@@ -99,6 +129,18 @@ func (c *Client) UpdateRestrictions(ctx context.Context, params *UpdateRestricti
 	return instanceRestrictions, err
 }
 
+// GetOrganizationSettings retrieves the organization settings of the instance.
+func (c *Client) GetOrganizationSettings(ctx context.Context) (*clerk.OrganizationSettings, error) {
+	path, err := clerk.JoinPath(path, "/organization_settings")
+	if err != nil {
+		return nil, err
+	}
+	req := clerk.NewAPIRequest(http.MethodGet, path)
+	orgSettings := &clerk.OrganizationSettings{}
+	err = c.Backend.Call(ctx, req, orgSettings)
+	return orgSettings, err
+}
+
 type UpdateOrganizationSettingsParams struct {
 	clerk.APIParams
 	Enabled                *bool     `json:"enabled,omitempty"`
@@ -3,7 +3,9 @@ package instancesettings
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"net/http"
+	"net/http/httptest"
 	"testing"
 
 	"github.com/clerk/clerk-sdk-go/v2"
@@ -11,6 +13,29 @@ import (
 	"github.com/stretchr/testify/require"
 )
 
+func TestInstanceClientGet(t *testing.T) {
+	t.Parallel()
+	id := "ins_123"
+	config := &clerk.ClientConfig{}
+	config.HTTPClient = &http.Client{
+		Transport: &clerktest.RoundTripper{
+			T:      t,
+			Out:    json.RawMessage(fmt.Sprintf(`{"object":"instance","id":"%s","environment_type":"production","allowed_origins":["https://example.com"],"test_mode":false,"support_email":"support@example.com"}`, id)),
+			Method: http.MethodGet,
+			Path:   "/v1/instance",
+		},
+	}
+	client := NewClient(config)
+	instance, err := client.Get(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, id, instance.ID)
+	require.Equal(t, "production", instance.EnvironmentType)
+	require.Equal(t, []string{"https://example.com"}, instance.AllowedOrigins)
+	require.False(t, *instance.TestMode)
+	require.Equal(t, "support@example.com", *instance.SupportEmail)
+	require.Nil(t, instance.HIBP)
+}
+
 func TestInstanceClientUpdate(t *testing.T) {
 	t.Parallel()
 	config := &clerk.ClientConfig{}
@@ -18,16 +43,45 @@ func TestInstanceClientUpdate(t *testing.T) {
 		Transport: &clerktest.RoundTripper{
 			T:      t,
 			In:     json.RawMessage(`{"test_mode":true}`),
-			Out:    nil,
+			Out:    json.RawMessage(`{"object":"instance","id":"ins_123","test_mode":true}`),
 			Method: http.MethodPatch,
 			Path:   "/v1/instance",
 		},
 	}
 	client := NewClient(config)
-	err := client.Update(context.Background(), &UpdateParams{
+	instance, err := client.Update(context.Background(), &UpdateParams{
 		TestMode: clerk.Bool(true),
 	})
 	require.NoError(t, err)
+	require.Equal(t, "ins_123", instance.ID)
+	require.True(t, *instance.TestMode)
+}
+
+func TestInstanceClientUpdate_NoContent(t *testing.T) {
+	t.Parallel()
+	var methods []string
+	clerkAPI := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/instance", r.URL.Path)
+		methods = append(methods, r.Method)
+		if r.Method == http.MethodPatch {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		_, _ = w.Write([]byte(`{"object":"instance","id":"ins_123","hibp":false}`))
+	}))
+	defer clerkAPI.Close()
+
+	config := &clerk.ClientConfig{}
+	config.HTTPClient = clerkAPI.Client()
+	config.URL = &clerkAPI.URL
+	client := NewClient(config)
+	instance, err := client.Update(context.Background(), &UpdateParams{
+		HIBP: clerk.Bool(false),
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{http.MethodPatch, http.MethodGet}, methods)
+	require.Equal(t, "ins_123", instance.ID)
+	require.False(t, *instance.HIBP)
 }
 
 func TestInstanceClientUpdate_Error(t *testing.T) {
@@ -46,7 +100,7 @@ func TestInstanceClientUpdate_Error(t *testing.T) {
 		},
 	}
 	client := NewClient(config)
-	err := client.Update(context.Background(), &UpdateParams{})
+	_, err := client.Update(context.Background(), &UpdateParams{})
 	require.Error(t, err)
 	apiErr, ok := err.(*clerk.APIErrorResponse)
 	require.True(t, ok)
@@ -55,6 +109,24 @@ func TestInstanceClientUpdate_Error(t *testing.T) {
 	require.Equal(t, "update-error-code", apiErr.Errors[0].Code)
 }
 
+func TestInstanceClientGetRestrictions(t *testing.T) {
+	t.Parallel()
+	config := &clerk.ClientConfig{}
+	config.HTTPClient = &http.Client{
+		Transport: &clerktest.RoundTripper{
+			T:      t,
+			Out:    json.RawMessage(`{"object":"instance_restrictions","allowlist":true,"blocklist":false}`),
+			Method: http.MethodGet,
+			Path:   "/v1/instance/restrictions",
+		},
+	}
+	client := NewClient(config)
+	restrictions, err := client.GetRestrictions(context.Background())
+	require.NoError(t, err)
+	require.True(t, restrictions.Allowlist)
+	require.False(t, restrictions.Blocklist)
+}
+
 func TestInstanceClientUpdateRestrictions(t *testing.T) {
 	t.Parallel()
 	config := &clerk.ClientConfig{}
@@ -101,6 +173,47 @@ func TestInstanceClientUpdateRestrictions_Error(t *testing.T) {
 	require.Equal(t, "update-error-code", apiErr.Errors[0].Code)
 }
 
+func TestInstanceClientGetOrganizationSettings(t *testing.T) {
+	t.Parallel()
+	config := &clerk.ClientConfig{}
+	config.HTTPClient = &http.Client{
+		Transport: &clerktest.RoundTripper{
+			T:      t,
+			Out:    json.RawMessage(`{"object":"organization_settings","enabled":true,"max_allowed_memberships":5}`),
+			Method: http.MethodGet,
+			Path:   "/v1/instance/organization_settings",
+		},
+	}
+	client := NewClient(config)
+	settings, err := client.GetOrganizationSettings(context.Background())
+	require.NoError(t, err)
+	require.True(t, settings.Enabled)
+	require.Equal(t, int64(5), settings.MaxAllowedMemberships)
+}
+
+func TestInstanceClientGetOrganizationSettings_Error(t *testing.T) {
+	t.Parallel()
+	config := &clerk.ClientConfig{}
+	config.HTTPClient = &http.Client{
+		Transport: &clerktest.RoundTripper{
+			T:      t,
+			Status: http.StatusNotFound,
+			Out: json.RawMessage(`{
+  "errors":[{
+		"code":"get-error-code"
+	}],
+	"clerk_trace_id":"get-trace-id"
+}`),
+		},
+	}
+	client := NewClient(config)
+	_, err := client.GetOrganizationSettings(context.Background())
+	require.Error(t, err)
+	apiErr, ok := err.(*clerk.APIErrorResponse)
+	require.True(t, ok)
+	require.Equal(t, "get-trace-id", apiErr.TraceID)
+}
+
 func TestInstanceClientUpdateOrganizationSettings(t *testing.T) {
 	t.Parallel()
 	config := &clerk.ClientConfig{}
@@ -0,0 +1,32 @@
+// Package contextutil provides context helpers that are shared by
+// the SDK packages.
+package contextutil
+
+import (
+	"context"
+	"time"
+)
+
+// WithoutCancel returns a context which keeps the values of its
+// parent, but is never canceled and has no deadline.
+// Go 1.21 provides context.WithoutCancel, but the module supports
+// older Go versions.
+func WithoutCancel(ctx context.Context) context.Context {
+	return detachedContext{ctx}
+}
+
+type detachedContext struct {
+	context.Context
+}
+
+func (detachedContext) Deadline() (time.Time, bool) {
+	return time.Time{}, false
+}
+
+func (detachedContext) Done() <-chan struct{} {
+	return nil
+}
+
+func (detachedContext) Err() error {
+	return nil
+}
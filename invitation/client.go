@@ -70,6 +70,9 @@ type CreateParams struct {
 
 // Create adds a new identifier to the allowlist.
 func (c *Client) Create(ctx context.Context, params *CreateParams) (*clerk.Invitation, error) {
+	if err := clerk.ValidateMetadata(clerk.MetadataResourceInvitation, clerk.MetadataKindPublic, params.PublicMetadata, false); err != nil {
+		return nil, err
+	}
 	req := clerk.NewAPIRequest(http.MethodPost, path)
 	req.SetParams(params)
 	invitation := &clerk.Invitation{}
@@ -97,6 +100,12 @@ func (b *bulkCreateResponse) UnmarshalJSON(data []byte) error {
 
 // BulkCreate creates multiple invitations.
 func (c *Client) BulkCreate(ctx context.Context, params *BulkCreateParams) (*clerk.Invitations, error) {
+	for _, invitation := range params.Invitations {
+		err := clerk.ValidateMetadata(clerk.MetadataResourceInvitation, clerk.MetadataKindPublic, invitation.PublicMetadata, false)
+		if err != nil {
+			return nil, err
+		}
+	}
 	path, err := clerk.JoinPath(path, "bulk")
 	if err != nil {
 		return nil, err
@@ -3,6 +3,7 @@ package clerk
 import (
 	"context"
 	"encoding/json"
+	"strings"
 	"time"
 
 	"github.com/go-jose/go-jose/v3/jwt"
@@ -10,7 +11,10 @@ import (
 
 type key string
 
-const clerkActiveSessionClaims = key("clerkActiveSessionClaims")
+const (
+	clerkActiveSessionClaims = key("clerkActiveSessionClaims")
+	clerkDevBrowserToken     = key("clerkDevBrowserToken")
+)
 
 // ContextWithSessionClaims returns a new context which includes the
 // active session claims.
@@ -25,6 +29,20 @@ func SessionClaimsFromContext(ctx context.Context) (*SessionClaims, bool) {
 	return claims, ok
 }
 
+// ContextWithDevBrowserToken returns a new context which includes
+// the dev browser token of a development instance.
+func ContextWithDevBrowserToken(ctx context.Context, token string) context.Context {
+	return context.WithValue(ctx, clerkDevBrowserToken, token)
+}
+
+// DevBrowserTokenFromContext returns the dev browser token from the
+// context. The token is only available for requests to development
+// instances.
+func DevBrowserTokenFromContext(ctx context.Context) (string, bool) {
+	token, ok := ctx.Value(clerkDevBrowserToken).(string)
+	return token, ok
+}
+
 // SessionClaims represents Clerk specific JWT claims.
 type SessionClaims struct {
 	// Standard IANA JWT claims
@@ -69,6 +87,41 @@ func (s *SessionClaims) HasRole(role string) bool {
 	return s.ActiveOrganizationRole == role
 }
 
+// HasPlan checks if the session claims contain the provided plan.
+// The plan can be prefixed with "u:" or "o:" to check for a user or
+// organization plan respectively. Plans without a prefix are checked
+// against the user plans, and the organization plans if there's an
+// active organization.
+func (s *SessionClaims) HasPlan(plan string) bool {
+	return s.hasEntitlement(s.Plans, plan)
+}
+
+// HasFeature checks if the session claims contain the provided
+// feature.
+// The feature can be prefixed with "u:" or "o:" to check for a user
+// or organization feature respectively. Features without a prefix
+// are checked against the user features, and the organization
+// features if there's an active organization.
+func (s *SessionClaims) HasFeature(feature string) bool {
+	return s.hasEntitlement(s.Features, feature)
+}
+
+// Checks the entitlements for the value. Values without a scope
+// prefix match both user and active organization entitlements.
+func (s *SessionClaims) hasEntitlement(entitlements Entitlements, value string) bool {
+	scope, key, found := strings.Cut(value, ":")
+	if found {
+		switch EntitlementScope(scope) {
+		case EntitlementScopeUser, EntitlementScopeOrganization:
+			return entitlements.Has(EntitlementScope(scope), key)
+		}
+	}
+	if entitlements.Has(EntitlementScopeUser, value) {
+		return true
+	}
+	return s.ActiveOrganizationID != "" && entitlements.Has(EntitlementScopeOrganization, value)
+}
+
 // RegisteredClaims holds public claim values (as specified in RFC 7519).
 type RegisteredClaims struct {
 	Issuer    string   `json:"iss,omitempty"`
@@ -123,6 +176,90 @@ type Claims struct {
 	ActiveOrganizationRole        string          `json:"org_role"`
 	ActiveOrganizationPermissions []string        `json:"org_permissions"`
 	Actor                         json.RawMessage `json:"act,omitempty"`
+	Plans                         Entitlements    `json:"pla,omitempty"`
+	Features                      Entitlements    `json:"fea,omitempty"`
+}
+
+// EntitlementScope denotes whether an entitlement, like a plan or
+// a feature, comes from a user or an organization subscription.
+type EntitlementScope string
+
+const (
+	EntitlementScopeUser         EntitlementScope = "u"
+	EntitlementScopeOrganization EntitlementScope = "o"
+)
+
+// Entitlement is a plan or a feature that is granted by a
+// subscription.
+type Entitlement struct {
+	Scope EntitlementScope
+	Key   string
+}
+
+// String returns the entitlement in its scoped claim format,
+// e.g. "o:pro".
+func (e Entitlement) String() string {
+	return string(e.Scope) + ":" + e.Key
+}
+
+// Entitlements holds the values of the plan (pla) or feature (fea)
+// session token claims.
+// The claims are comma separated lists of scoped keys, for example
+// "u:free,o:pro", where the "u:" prefix denotes a user subscription
+// and the "o:" prefix an organization subscription.
+type Entitlements []Entitlement
+
+func (e *Entitlements) UnmarshalJSON(data []byte) error {
+	var values []string
+	var value string
+	err := json.Unmarshal(data, &value)
+	if err == nil {
+		values = strings.Split(value, ",")
+	} else if err = json.Unmarshal(data, &values); err != nil {
+		return err
+	}
+
+	*e = nil
+	for _, v := range values {
+		v = strings.TrimSpace(v)
+		if v == "" {
+			continue
+		}
+		*e = append(*e, parseEntitlement(v))
+	}
+	return nil
+}
+
+func (e Entitlements) MarshalJSON() ([]byte, error) {
+	values := make([]string, len(e))
+	for i, entitlement := range e {
+		values[i] = entitlement.String()
+	}
+	return json.Marshal(strings.Join(values, ","))
+}
+
+// Has returns true if the entitlements include the key in the
+// provided scope.
+func (e Entitlements) Has(scope EntitlementScope, key string) bool {
+	for _, entitlement := range e {
+		if entitlement.Scope == scope && entitlement.Key == key {
+			return true
+		}
+	}
+	return false
+}
+
+// Parses a scoped entitlement value, like "o:pro". Values without
+// a known scope prefix are considered user entitlements.
+func parseEntitlement(value string) Entitlement {
+	scope, key, found := strings.Cut(value, ":")
+	if found {
+		switch EntitlementScope(scope) {
+		case EntitlementScopeUser, EntitlementScopeOrganization:
+			return Entitlement{Scope: EntitlementScope(scope), Key: key}
+		}
+	}
+	return Entitlement{Scope: EntitlementScopeUser, Key: value}
 }
 
 // UnverifiedToken holds the result of a JWT decoding without any
@@ -1,6 +1,7 @@
 package clerk
 
 import (
+	"encoding/json"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -58,3 +59,38 @@ func TestSessionClaimsHasPermission(t *testing.T) {
 		require.Equal(t, claims.HasPermission(tc.permission), tc.want)
 	}
 }
+
+func TestSessionClaimsEntitlements(t *testing.T) {
+	t.Parallel()
+	claims := &SessionClaims{}
+	err := json.Unmarshal([]byte(`{"sub":"user_123","pla":"u:free,o:pro","fea":"u:profile, o:teams"}`), claims)
+	require.NoError(t, err)
+	require.Equal(t, Entitlements{
+		{Scope: EntitlementScopeUser, Key: "free"},
+		{Scope: EntitlementScopeOrganization, Key: "pro"},
+	}, claims.Plans)
+	require.Equal(t, 2, len(claims.Features))
+
+	// Without an active organization, unscoped checks are made against
+	// the user entitlements only.
+	require.True(t, claims.HasPlan("free"))
+	require.False(t, claims.HasPlan("pro"))
+	require.True(t, claims.HasPlan("o:pro"))
+	require.True(t, claims.HasFeature("profile"))
+	require.False(t, claims.HasFeature("teams"))
+
+	// With an active organization, unscoped checks are made against
+	// both the user and the organization entitlements.
+	claims.ActiveOrganizationID = "org_123"
+	require.True(t, claims.HasPlan("free"))
+	require.True(t, claims.HasPlan("u:free"))
+	require.False(t, claims.HasPlan("o:free"))
+	require.True(t, claims.HasPlan("pro"))
+	require.True(t, claims.HasFeature("profile"))
+	require.True(t, claims.HasFeature("teams"))
+	require.False(t, claims.HasFeature("sso"))
+
+	data, err := json.Marshal(claims.Plans)
+	require.NoError(t, err)
+	require.Equal(t, `"u:free,o:pro"`, string(data))
+}
@@ -0,0 +1,190 @@
+package clerk
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+)
+
+// DecodeMetadata decodes public, private or unsafe metadata into a
+// value of type T. Empty or null metadata result in the zero value
+// of T.
+//
+//	type Preferences struct {
+//		Theme string `json:"theme"`
+//	}
+//	prefs, err := clerk.DecodeMetadata[Preferences](user.PublicMetadata)
+func DecodeMetadata[T any](data json.RawMessage) (T, error) {
+	var v T
+	if isEmptyMetadata(data) {
+		return v, nil
+	}
+	err := json.Unmarshal(data, &v)
+	return v, err
+}
+
+// MarshalMetadata encodes v so that it can be used as a metadata
+// parameter for create and update operations.
+//
+//	params := &user.UpdateMetadataParams{}
+//	params.PublicMetadata, err = clerk.MarshalMetadata(prefs)
+func MarshalMetadata(v any) (*json.RawMessage, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	raw := json.RawMessage(data)
+	return &raw, nil
+}
+
+// MetadataPatch describes a partial metadata update. Values are
+// deep-merged with the existing metadata and keys with a nil value
+// are deleted, the same way metadata updates are applied by the
+// Clerk API.
+//
+//	patch := clerk.MetadataPatch{}.Set("theme", "dark").Delete("legacy_theme")
+//	params.PublicMetadata, err = clerk.MarshalMetadata(patch)
+type MetadataPatch map[string]any
+
+// Set sets the value for the key. Nested MetadataPatch values are
+// deep-merged with the existing metadata.
+func (p MetadataPatch) Set(key string, value any) MetadataPatch {
+	if p == nil {
+		p = MetadataPatch{}
+	}
+	p[key] = value
+	return p
+}
+
+// Delete marks the key for deletion.
+func (p MetadataPatch) Delete(key string) MetadataPatch {
+	return p.Set(key, nil)
+}
+
+// MergeMetadata applies the update to the current metadata and
+// returns the result. Objects are merged recursively, keys that are
+// set to null in the update are removed and any other value replaces
+// the current one. This matches the merge behavior of the metadata
+// update endpoints, so it can be used to compute the metadata that
+// will be stored after an update.
+func MergeMetadata(current json.RawMessage, update any) (json.RawMessage, error) {
+	var updateData []byte
+	switch u := update.(type) {
+	case json.RawMessage:
+		updateData = u
+	case *json.RawMessage:
+		if u != nil {
+			updateData = *u
+		}
+	default:
+		var err error
+		updateData, err = json.Marshal(update)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if isEmptyMetadata(updateData) {
+		if isEmptyMetadata(current) {
+			return json.RawMessage("{}"), nil
+		}
+		return current, nil
+	}
+
+	currentValues, err := decodeMetadataObject(current)
+	if err != nil {
+		return nil, fmt.Errorf("decode current metadata: %w", err)
+	}
+	updateValues, err := decodeMetadataObject(updateData)
+	if err != nil {
+		return nil, fmt.Errorf("decode metadata update: %w", err)
+	}
+	return json.Marshal(mergeMetadataValues(currentValues, updateValues))
+}
+
+func mergeMetadataValues(current, update map[string]any) map[string]any {
+	for key, value := range update {
+		if value == nil {
+			delete(current, key)
+			continue
+		}
+		updateObject, ok := value.(map[string]any)
+		if !ok {
+			current[key] = value
+			continue
+		}
+		currentObject, ok := current[key].(map[string]any)
+		if !ok {
+			currentObject = map[string]any{}
+		}
+		current[key] = mergeMetadataValues(currentObject, updateObject)
+	}
+	return current
+}
+
+func isEmptyMetadata(data []byte) bool {
+	data = bytes.TrimSpace(data)
+	return len(data) == 0 || bytes.Equal(data, []byte("null"))
+}
+
+// Decodes a metadata object. Numbers are decoded as json.Number, so
+// that integers which don't fit in a float64 are not rounded when
+// the object is encoded again. Empty metadata result in an empty map.
+func decodeMetadataObject(data []byte) (map[string]any, error) {
+	values := map[string]any{}
+	if isEmptyMetadata(data) {
+		return values, nil
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	err := decoder.Decode(&values)
+	return values, err
+}
+
+// MetadataDiff returns the update that turns the current metadata
+// into the updated metadata when it's merged with MergeMetadata, or
+// by the metadata update endpoints. Keys that were removed are set
+// to null. Returns nil if there are no changes.
+func MetadataDiff(current, updated json.RawMessage) (*json.RawMessage, error) {
+	currentValues, err := decodeMetadataObject(current)
+	if err != nil {
+		return nil, fmt.Errorf("decode current metadata: %w", err)
+	}
+	updatedValues, err := decodeMetadataObject(updated)
+	if err != nil {
+		return nil, fmt.Errorf("decode updated metadata: %w", err)
+	}
+	diff := diffMetadataValues(currentValues, updatedValues)
+	if len(diff) == 0 {
+		return nil, nil
+	}
+	return MarshalMetadata(diff)
+}
+
+func diffMetadataValues(current, updated map[string]any) map[string]any {
+	diff := map[string]any{}
+	for key := range current {
+		if _, ok := updated[key]; !ok {
+			diff[key] = nil
+		}
+	}
+	for key, value := range updated {
+		currentValue, ok := current[key]
+		if !ok {
+			diff[key] = value
+			continue
+		}
+		currentObject, currentIsObject := currentValue.(map[string]any)
+		updatedObject, updatedIsObject := value.(map[string]any)
+		if currentIsObject && updatedIsObject {
+			if nested := diffMetadataValues(currentObject, updatedObject); len(nested) > 0 {
+				diff[key] = nested
+			}
+			continue
+		}
+		if !reflect.DeepEqual(currentValue, value) {
+			diff[key] = value
+		}
+	}
+	return diff
+}
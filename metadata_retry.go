@@ -0,0 +1,170 @@
+package clerk
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math/rand"
+	"time"
+)
+
+// ErrMetadataConflict is returned when a resource was modified
+// while its metadata were being updated.
+// Conflicts are detected on a best-effort basis. The Clerk API
+// doesn't support conditional updates, so a modification that
+// happens right before an update is written cannot be detected.
+var ErrMetadataConflict = errors.New("clerk: resource was modified concurrently")
+
+const (
+	defaultMetadataUpdateMaxAttempts = 5
+	defaultMetadataUpdateMinBackoff  = 100 * time.Millisecond
+	defaultMetadataUpdateMaxBackoff  = 2 * time.Second
+)
+
+// MetadataRetryParams configures how read-modify-write metadata
+// updates are retried when a conflict is detected.
+type MetadataRetryParams struct {
+	// MaxAttempts is the maximum number of attempts before giving up
+	// with ErrMetadataConflict. Defaults to 5.
+	MaxAttempts int
+	// MinBackoff is the wait duration before the first retry. The
+	// duration doubles with every retry, with some random jitter.
+	// Defaults to 100ms.
+	MinBackoff time.Duration
+	// MaxBackoff is the maximum wait duration between retries.
+	// Defaults to 2s.
+	MaxBackoff time.Duration
+}
+
+// RetryOnMetadataConflict calls attempt until it succeeds, fails
+// with an error other than ErrMetadataConflict, or the maximum
+// number of attempts is reached. Attempts are spaced out with
+// exponential backoff and can be canceled with the context.
+func RetryOnMetadataConflict[T any](ctx context.Context, params *MetadataRetryParams, attempt func(context.Context) (T, error)) (T, error) {
+	if params == nil {
+		params = &MetadataRetryParams{}
+	}
+	maxAttempts := params.MaxAttempts
+	if maxAttempts <= 0 {
+		maxAttempts = defaultMetadataUpdateMaxAttempts
+	}
+	backoff := params.MinBackoff
+	if backoff <= 0 {
+		backoff = defaultMetadataUpdateMinBackoff
+	}
+	maxBackoff := params.MaxBackoff
+	if maxBackoff <= 0 {
+		maxBackoff = defaultMetadataUpdateMaxBackoff
+	}
+
+	var res T
+	var err error
+	for i := 0; i < maxAttempts; i++ {
+		if i > 0 {
+			// Wait between half and the full backoff duration.
+			wait := backoff/2 + time.Duration(rand.Int63n(int64(backoff/2)+1))
+			timer := time.NewTimer(wait)
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return res, ctx.Err()
+			case <-timer.C:
+			}
+			backoff *= 2
+			if backoff > maxBackoff {
+				backoff = maxBackoff
+			}
+		}
+		res, err = attempt(ctx)
+		if !errors.Is(err, ErrMetadataConflict) {
+			return res, err
+		}
+	}
+	return res, err
+}
+
+// ResourceMetadata holds the metadata of a resource and the time of
+// its last update.
+type ResourceMetadata struct {
+	PublicMetadata  json.RawMessage
+	PrivateMetadata json.RawMessage
+	UnsafeMetadata  json.RawMessage
+	UpdatedAt       int64
+}
+
+// MetadataChanges holds the changed keys of each metadata field, as
+// computed by MetadataDiff. Fields without changes are nil.
+type MetadataChanges struct {
+	PublicMetadata  *json.RawMessage
+	PrivateMetadata *json.RawMessage
+	UnsafeMetadata  *json.RawMessage
+}
+
+// MetadataModifier provides the operations of ModifyMetadata for a
+// resource type.
+type MetadataModifier[T any] struct {
+	// Get fetches the latest version of the resource.
+	Get func(ctx context.Context) (*T, error)
+	// Modify changes the metadata of the resource in place. It might
+	// be called more than once.
+	Modify func(resource *T) error
+	// Metadata returns the metadata of the resource.
+	Metadata func(resource *T) *ResourceMetadata
+	// Update writes the changed metadata of the resource.
+	Update func(ctx context.Context, changes *MetadataChanges) (*T, error)
+}
+
+// ModifyMetadata fetches a resource, applies the modifier.Modify
+// function to its metadata and writes back only the metadata keys
+// that changed. If the resource is updated by somebody else in the
+// meantime, as detected by its UpdatedAt timestamp, the operation is
+// retried with backoff. Fails with ErrMetadataConflict if all
+// attempts conflict.
+// Conflict detection is best-effort. The API doesn't support
+// conditional updates, so a change that happens right before the
+// metadata are written is not detected. Since only the changed keys
+// are written, such a change is lost only if it touches the same
+// keys.
+func ModifyMetadata[T any](ctx context.Context, params *MetadataRetryParams, modifier *MetadataModifier[T]) (*T, error) {
+	if modifier == nil || modifier.Modify == nil {
+		return nil, errors.New("missing Modify function")
+	}
+	return RetryOnMetadataConflict(ctx, params, func(ctx context.Context) (*T, error) {
+		current, err := modifier.Get(ctx)
+		if err != nil {
+			return nil, err
+		}
+		modified := *current
+		err = modifier.Modify(&modified)
+		if err != nil {
+			return nil, err
+		}
+
+		before, after := modifier.Metadata(current), modifier.Metadata(&modified)
+		changes := &MetadataChanges{}
+		changes.PublicMetadata, err = MetadataDiff(before.PublicMetadata, after.PublicMetadata)
+		if err != nil {
+			return nil, err
+		}
+		changes.PrivateMetadata, err = MetadataDiff(before.PrivateMetadata, after.PrivateMetadata)
+		if err != nil {
+			return nil, err
+		}
+		changes.UnsafeMetadata, err = MetadataDiff(before.UnsafeMetadata, after.UnsafeMetadata)
+		if err != nil {
+			return nil, err
+		}
+		if changes.PublicMetadata == nil && changes.PrivateMetadata == nil && changes.UnsafeMetadata == nil {
+			return current, nil
+		}
+
+		latest, err := modifier.Get(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if modifier.Metadata(latest).UpdatedAt != before.UpdatedAt {
+			return nil, ErrMetadataConflict
+		}
+		return modifier.Update(ctx, changes)
+	})
+}
@@ -0,0 +1,101 @@
+package clerk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRetryOnMetadataConflict(t *testing.T) {
+	t.Parallel()
+	params := &MetadataRetryParams{
+		MaxAttempts: 3,
+		MinBackoff:  time.Millisecond,
+		MaxBackoff:  time.Millisecond,
+	}
+
+	attempts := 0
+	res, err := RetryOnMetadataConflict(context.Background(), params, func(_ context.Context) (int, error) {
+		attempts++
+		if attempts < 3 {
+			return 0, ErrMetadataConflict
+		}
+		return attempts, nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, 3, res)
+
+	// Gives up after the maximum number of attempts.
+	attempts = 0
+	_, err = RetryOnMetadataConflict(context.Background(), params, func(_ context.Context) (int, error) {
+		attempts++
+		return 0, ErrMetadataConflict
+	})
+	require.ErrorIs(t, err, ErrMetadataConflict)
+	require.Equal(t, 3, attempts)
+
+	// Other errors are not retried.
+	attempts = 0
+	_, err = RetryOnMetadataConflict(context.Background(), params, func(_ context.Context) (int, error) {
+		attempts++
+		return 0, fmt.Errorf("oops")
+	})
+	require.EqualError(t, err, "oops")
+	require.Equal(t, 1, attempts)
+
+	// Retries stop when the context is canceled.
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = RetryOnMetadataConflict(ctx, params, func(_ context.Context) (int, error) {
+		return 0, ErrMetadataConflict
+	})
+	require.ErrorIs(t, err, context.Canceled)
+}
+
+func TestModifyMetadata(t *testing.T) {
+	t.Parallel()
+	params := &MetadataRetryParams{MinBackoff: time.Millisecond}
+	// The resource is updated concurrently during the first attempt.
+	current := &Organization{PublicMetadata: json.RawMessage(`{"a":1,"b":2}`), UpdatedAt: 1}
+	gets := 0
+	var updates []string
+	modifier := &MetadataModifier[Organization]{
+		Get: func(_ context.Context) (*Organization, error) {
+			gets++
+			if gets == 2 {
+				current = &Organization{PublicMetadata: json.RawMessage(`{"a":1,"b":3}`), UpdatedAt: 2}
+			}
+			return current, nil
+		},
+		Modify: func(organization *Organization) error {
+			organization.PublicMetadata = json.RawMessage(`{"a":10,"b":3}`)
+			return nil
+		},
+		Metadata: func(organization *Organization) *ResourceMetadata {
+			return &ResourceMetadata{PublicMetadata: organization.PublicMetadata, UpdatedAt: organization.UpdatedAt}
+		},
+		Update: func(_ context.Context, changes *MetadataChanges) (*Organization, error) {
+			require.Nil(t, changes.PrivateMetadata)
+			require.Nil(t, changes.UnsafeMetadata)
+			updates = append(updates, string(*changes.PublicMetadata))
+			return current, nil
+		},
+	}
+	_, err := ModifyMetadata(context.Background(), params, modifier)
+	require.NoError(t, err)
+	require.Equal(t, []string{`{"a":10}`}, updates)
+	require.Equal(t, 4, gets)
+
+	// Nothing is written if the metadata don't change.
+	modifier.Modify = func(*Organization) error { return nil }
+	_, err = ModifyMetadata(context.Background(), params, modifier)
+	require.NoError(t, err)
+	require.Equal(t, 1, len(updates))
+
+	_, err = ModifyMetadata(context.Background(), params, &MetadataModifier[Organization]{})
+	require.EqualError(t, err, "missing Modify function")
+}
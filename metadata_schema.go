@@ -0,0 +1,716 @@
+package clerk
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// MetadataSchema is a MetadataValidator which validates metadata
+// against a JSON Schema.
+//
+// A subset of JSON Schema draft 2020-12 is supported: the type,
+// enum, const, properties, required, additionalProperties,
+// minProperties, maxProperties, items, prefixItems, minItems,
+// maxItems, uniqueItems, minLength, maxLength, pattern, minimum,
+// maximum, exclusiveMinimum, exclusiveMaximum, multipleOf, allOf,
+// anyOf, oneOf and not keywords, and $ref to definitions in the same
+// document ($defs). Other keywords are ignored.
+//
+// Partial documents for metadata merge updates are validated without
+// the required keyword, and properties with null values are treated
+// as deletions.
+type MetadataSchema struct {
+	root *schemaNode
+}
+
+// NewMetadataSchema compiles the JSON Schema document.
+//
+//	schema, err := clerk.NewMetadataSchema([]byte(`{
+//		"type": "object",
+//		"properties": {"plan": {"enum": ["free", "pro"]}},
+//		"required": ["plan"]
+//	}`))
+//	clerk.RegisterMetadataValidator(clerk.MetadataResourceUser, clerk.MetadataKindPublic, schema)
+func NewMetadataSchema(schema []byte) (*MetadataSchema, error) {
+	var doc any
+	err := json.Unmarshal(schema, &doc)
+	if err != nil {
+		return nil, fmt.Errorf("decode schema: %w", err)
+	}
+	c := &schemaCompiler{
+		root:     doc,
+		compiled: map[string]*schemaNode{},
+	}
+	root, err := c.compile(doc, "#")
+	if err != nil {
+		return nil, err
+	}
+	err = c.checkRefCycles(root)
+	if err != nil {
+		return nil, err
+	}
+	return &MetadataSchema{root: root}, nil
+}
+
+// ValidateMetadata validates the metadata against the schema and
+// returns a *MetadataValidationError with an error for each invalid
+// field.
+func (s *MetadataSchema) ValidateMetadata(params *MetadataValidationParams) error {
+	var value any = map[string]any{}
+	if !isEmptyMetadata(params.Data) {
+		err := json.Unmarshal(params.Data, &value)
+		if err != nil {
+			return err
+		}
+	}
+	v := &schemaValidation{resolving: map[string]bool{}}
+	v.validate(s.root, value, "", params.Merge)
+	if len(v.errors) == 0 {
+		return nil
+	}
+	return &MetadataValidationError{
+		Resource: params.Resource,
+		Kind:     params.Kind,
+		Errors:   v.errors,
+	}
+}
+
+// A compiled schema. A nil node accepts any value.
+type schemaNode struct {
+	// Boolean schemas are represented with reject set for false.
+	reject bool
+
+	types    []string
+	enum     []any
+	constant *any
+
+	properties           map[string]*schemaNode
+	required             []string
+	additionalProperties *schemaNode
+	minProperties        *int
+	maxProperties        *int
+
+	items       *schemaNode
+	prefixItems []*schemaNode
+	minItems    *int
+	maxItems    *int
+	uniqueItems bool
+
+	minLength *int
+	maxLength *int
+	pattern   *regexp.Regexp
+
+	minimum          *float64
+	maximum          *float64
+	exclusiveMinimum *float64
+	exclusiveMaximum *float64
+	multipleOf       *float64
+
+	allOf []*schemaNode
+	anyOf []*schemaNode
+	oneOf []*schemaNode
+	not   *schemaNode
+
+	ref string
+	// Resolved lazily, so that recursive references are supported.
+	compiler *schemaCompiler
+}
+
+type schemaCompiler struct {
+	root     any
+	compiled map[string]*schemaNode
+}
+
+func (c *schemaCompiler) compile(doc any, location string) (*schemaNode, error) {
+	if b, ok := doc.(bool); ok {
+		return &schemaNode{reject: !b}, nil
+	}
+	obj, ok := doc.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("invalid schema at %s: must be an object or a boolean", location)
+	}
+	node := &schemaNode{}
+	var err error
+
+	if ref, ok := obj["$ref"]; ok {
+		refStr, ok := ref.(string)
+		if !ok || !strings.HasPrefix(refStr, "#") {
+			return nil, fmt.Errorf("invalid schema at %s: only local $ref values are supported", location)
+		}
+		node.ref = refStr
+		node.compiler = c
+	}
+
+	switch t := obj["type"].(type) {
+	case nil:
+	case string:
+		node.types = []string{t}
+	case []any:
+		for _, item := range t {
+			s, ok := item.(string)
+			if !ok {
+				return nil, fmt.Errorf("invalid schema at %s/type: must be a string or an array of strings", location)
+			}
+			node.types = append(node.types, s)
+		}
+	default:
+		return nil, fmt.Errorf("invalid schema at %s/type: must be a string or an array of strings", location)
+	}
+	if enum, ok := obj["enum"]; ok {
+		values, ok := enum.([]any)
+		if !ok {
+			return nil, fmt.Errorf("invalid schema at %s/enum: must be an array", location)
+		}
+		node.enum = values
+	}
+	if constant, ok := obj["const"]; ok {
+		node.constant = &constant
+	}
+
+	if props, ok := obj["properties"]; ok {
+		propsObj, ok := props.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("invalid schema at %s/properties: must be an object", location)
+		}
+		node.properties = map[string]*schemaNode{}
+		for name, prop := range propsObj {
+			node.properties[name], err = c.compile(prop, location+"/properties/"+escapeJSONPointer(name))
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+	if required, ok := obj["required"]; ok {
+		names, ok := required.([]any)
+		if !ok {
+			return nil, fmt.Errorf("invalid schema at %s/required: must be an array", location)
+		}
+		for _, name := range names {
+			s, ok := name.(string)
+			if !ok {
+				return nil, fmt.Errorf("invalid schema at %s/required: must be an array of strings", location)
+			}
+			node.required = append(node.required, s)
+		}
+	}
+	if additional, ok := obj["additionalProperties"]; ok {
+		node.additionalProperties, err = c.compile(additional, location+"/additionalProperties")
+		if err != nil {
+			return nil, err
+		}
+	}
+	if node.minProperties, err = schemaInt(obj, "minProperties", location); err != nil {
+		return nil, err
+	}
+	if node.maxProperties, err = schemaInt(obj, "maxProperties", location); err != nil {
+		return nil, err
+	}
+
+	if items, ok := obj["items"]; ok {
+		node.items, err = c.compile(items, location+"/items")
+		if err != nil {
+			return nil, err
+		}
+	}
+	if prefixItems, ok := obj["prefixItems"]; ok {
+		schemas, ok := prefixItems.([]any)
+		if !ok {
+			return nil, fmt.Errorf("invalid schema at %s/prefixItems: must be an array", location)
+		}
+		for i, item := range schemas {
+			compiled, err := c.compile(item, location+"/prefixItems/"+strconv.Itoa(i))
+			if err != nil {
+				return nil, err
+			}
+			node.prefixItems = append(node.prefixItems, compiled)
+		}
+	}
+	if node.minItems, err = schemaInt(obj, "minItems", location); err != nil {
+		return nil, err
+	}
+	if node.maxItems, err = schemaInt(obj, "maxItems", location); err != nil {
+		return nil, err
+	}
+	if unique, ok := obj["uniqueItems"].(bool); ok {
+		node.uniqueItems = unique
+	}
+
+	if node.minLength, err = schemaInt(obj, "minLength", location); err != nil {
+		return nil, err
+	}
+	if node.maxLength, err = schemaInt(obj, "maxLength", location); err != nil {
+		return nil, err
+	}
+	if pattern, ok := obj["pattern"]; ok {
+		s, ok := pattern.(string)
+		if !ok {
+			return nil, fmt.Errorf("invalid schema at %s/pattern: must be a string", location)
+		}
+		node.pattern, err = regexp.Compile(s)
+		if err != nil {
+			return nil, fmt.Errorf("invalid schema at %s/pattern: %w", location, err)
+		}
+	}
+
+	for keyword, target := range map[string]**float64{
+		"minimum":          &node.minimum,
+		"maximum":          &node.maximum,
+		"exclusiveMinimum": &node.exclusiveMinimum,
+		"exclusiveMaximum": &node.exclusiveMaximum,
+		"multipleOf":       &node.multipleOf,
+	} {
+		value, ok := obj[keyword]
+		if !ok {
+			continue
+		}
+		number, ok := value.(float64)
+		if !ok {
+			return nil, fmt.Errorf("invalid schema at %s/%s: must be a number", location, keyword)
+		}
+		*target = &number
+	}
+
+	for keyword, target := range map[string]*[]*schemaNode{
+		"allOf": &node.allOf,
+		"anyOf": &node.anyOf,
+		"oneOf": &node.oneOf,
+	} {
+		value, ok := obj[keyword]
+		if !ok {
+			continue
+		}
+		schemas, ok := value.([]any)
+		if !ok || len(schemas) == 0 {
+			return nil, fmt.Errorf("invalid schema at %s/%s: must be a non-empty array", location, keyword)
+		}
+		for i, schema := range schemas {
+			compiled, err := c.compile(schema, location+"/"+keyword+"/"+strconv.Itoa(i))
+			if err != nil {
+				return nil, err
+			}
+			*target = append(*target, compiled)
+		}
+	}
+	if not, ok := obj["not"]; ok {
+		node.not, err = c.compile(not, location+"/not")
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if node.ref != "" {
+		// Make sure that the reference can be resolved.
+		if _, err := c.resolve(node.ref); err != nil {
+			return nil, fmt.Errorf("invalid schema at %s/$ref: %w", location, err)
+		}
+	}
+	return node, nil
+}
+
+// Resolves a local reference, like "#/$defs/address".
+func (c *schemaCompiler) resolve(ref string) (*schemaNode, error) {
+	if node, ok := c.compiled[ref]; ok {
+		return node, nil
+	}
+	doc := c.root
+	pointer := strings.TrimPrefix(ref, "#")
+	if pointer != "" {
+		for _, token := range strings.Split(strings.TrimPrefix(pointer, "/"), "/") {
+			token = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+			obj, ok := doc.(map[string]any)
+			if !ok {
+				return nil, fmt.Errorf("cannot resolve %s", ref)
+			}
+			doc, ok = obj[token]
+			if !ok {
+				return nil, fmt.Errorf("cannot resolve %s", ref)
+			}
+		}
+	}
+	// Register a placeholder first, so that recursive references
+	// resolve to the same node.
+	node := &schemaNode{}
+	c.compiled[ref] = node
+	compiled, err := c.compile(doc, ref)
+	if err != nil {
+		delete(c.compiled, ref)
+		return nil, err
+	}
+	*node = *compiled
+	return node, nil
+}
+
+// Returns an error if a $ref leads back to a schema that applies to
+// the same value, like a definition that refers to itself directly
+// or through allOf, anyOf, oneOf or not. Such schemas would never
+// finish validating. References through properties or items are
+// allowed, since they descend into the value.
+func (c *schemaCompiler) checkRefCycles(root *schemaNode) error {
+	var nodes []*schemaNode
+	seen := map[*schemaNode]bool{}
+	var collect func(node *schemaNode)
+	collect = func(node *schemaNode) {
+		if node == nil || seen[node] {
+			return
+		}
+		seen[node] = true
+		nodes = append(nodes, node)
+		for _, sub := range node.subschemas() {
+			collect(sub)
+		}
+		if node.ref != "" {
+			collect(c.compiled[node.ref])
+		}
+	}
+	collect(root)
+
+	const (
+		visiting = 1
+		visited  = 2
+	)
+	state := map[*schemaNode]int{}
+	var visit func(node *schemaNode) error
+	visit = func(node *schemaNode) error {
+		if node == nil || state[node] == visited {
+			return nil
+		}
+		state[node] = visiting
+		if node.ref != "" {
+			target := c.compiled[node.ref]
+			if state[target] == visiting {
+				return fmt.Errorf("invalid schema: circular $ref %s", node.ref)
+			}
+			if err := visit(target); err != nil {
+				return err
+			}
+		}
+		for _, sub := range node.inPlaceSubschemas() {
+			if err := visit(sub); err != nil {
+				return err
+			}
+		}
+		state[node] = visited
+		return nil
+	}
+	for _, node := range nodes {
+		if err := visit(node); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Returns the subschemas that apply to the same value as the node.
+func (node *schemaNode) inPlaceSubschemas() []*schemaNode {
+	var subs []*schemaNode
+	subs = append(subs, node.allOf...)
+	subs = append(subs, node.anyOf...)
+	subs = append(subs, node.oneOf...)
+	if node.not != nil {
+		subs = append(subs, node.not)
+	}
+	return subs
+}
+
+// Returns all the subschemas of the node.
+func (node *schemaNode) subschemas() []*schemaNode {
+	subs := node.inPlaceSubschemas()
+	for _, prop := range node.properties {
+		subs = append(subs, prop)
+	}
+	if node.additionalProperties != nil {
+		subs = append(subs, node.additionalProperties)
+	}
+	if node.items != nil {
+		subs = append(subs, node.items)
+	}
+	subs = append(subs, node.prefixItems...)
+	return subs
+}
+
+func schemaInt(obj map[string]any, keyword, location string) (*int, error) {
+	value, ok := obj[keyword]
+	if !ok {
+		return nil, nil
+	}
+	number, ok := value.(float64)
+	if !ok || number < 0 || number != math.Trunc(number) {
+		return nil, fmt.Errorf("invalid schema at %s/%s: must be a non-negative integer", location, keyword)
+	}
+	n := int(number)
+	return &n, nil
+}
+
+// Collects the errors of a validation.
+type schemaValidation struct {
+	errors []MetadataFieldError
+	// The references that are being resolved for each value path.
+	// A reference that is resolved again for the same path without
+	// descending into the value is a cycle.
+	resolving map[string]bool
+}
+
+func (v *schemaValidation) addError(path, format string, args ...any) {
+	v.errors = append(v.errors, MetadataFieldError{
+		Path:    path,
+		Message: fmt.Sprintf(format, args...),
+	})
+}
+
+// Validates the value against the schema node. If partial is true,
+// the value is part of a merge update.
+func (v *schemaValidation) validate(node *schemaNode, value any, path string, partial bool) {
+	if node == nil {
+		return
+	}
+	if node.reject {
+		v.addError(path, "is not allowed")
+		return
+	}
+	if node.ref != "" {
+		key := node.ref + " " + path
+		if v.resolving[key] {
+			v.addError(path, "circular $ref %s", node.ref)
+			return
+		}
+		resolved, err := node.compiler.resolve(node.ref)
+		if err != nil {
+			v.addError(path, "%s", err)
+			return
+		}
+		v.resolving[key] = true
+		v.validate(resolved, value, path, partial)
+		delete(v.resolving, key)
+	}
+
+	if len(node.types) > 0 && !matchesSchemaType(node.types, value) {
+		v.addError(path, "must be of type %s", strings.Join(node.types, " or "))
+		// The rest of the keywords are not meaningful for the wrong
+		// type.
+		return
+	}
+	if node.enum != nil {
+		found := false
+		for _, allowed := range node.enum {
+			if schemaValuesEqual(allowed, value) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			v.addError(path, "must be one of %s", formatSchemaValues(node.enum))
+		}
+	}
+	if node.constant != nil && !schemaValuesEqual(*node.constant, value) {
+		v.addError(path, "must be %s", formatSchemaValues([]any{*node.constant}))
+	}
+
+	switch val := value.(type) {
+	case map[string]any:
+		v.validateObject(node, val, path, partial)
+	case []any:
+		v.validateArray(node, val, path)
+	case string:
+		length := utf8.RuneCountInString(val)
+		if node.minLength != nil && length < *node.minLength {
+			v.addError(path, "must be at least %d characters long", *node.minLength)
+		}
+		if node.maxLength != nil && length > *node.maxLength {
+			v.addError(path, "must be at most %d characters long", *node.maxLength)
+		}
+		if node.pattern != nil && !node.pattern.MatchString(val) {
+			v.addError(path, "must match the pattern %s", node.pattern)
+		}
+	case float64:
+		v.validateNumber(node, val, path)
+	}
+
+	for _, sub := range node.allOf {
+		v.validate(sub, value, path, partial)
+	}
+	if len(node.anyOf) > 0 {
+		matched := false
+		for _, sub := range node.anyOf {
+			if v.matches(sub, value, path, partial) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			v.addError(path, "must match at least one schema in anyOf")
+		}
+	}
+	if len(node.oneOf) > 0 {
+		matched := 0
+		for _, sub := range node.oneOf {
+			if v.matches(sub, value, path, partial) {
+				matched++
+			}
+		}
+		if matched != 1 {
+			v.addError(path, "must match exactly one schema in oneOf, but matches %d", matched)
+		}
+	}
+	if node.not != nil && v.matches(node.not, value, path, partial) {
+		v.addError(path, "must not match the schema in not")
+	}
+}
+
+// Reports whether the value is valid against the schema node,
+// without collecting any errors.
+func (v *schemaValidation) matches(node *schemaNode, value any, path string, partial bool) bool {
+	// The references that are being resolved are shared, so that
+	// cycles through anyOf, oneOf or not are detected.
+	sub := &schemaValidation{resolving: v.resolving}
+	sub.validate(node, value, path, partial)
+	return len(sub.errors) == 0
+}
+
+func (v *schemaValidation) validateObject(node *schemaNode, value map[string]any, path string, partial bool) {
+	if !partial {
+		for _, name := range node.required {
+			if _, ok := value[name]; !ok {
+				v.addError(path+"/"+escapeJSONPointer(name), "is required")
+			}
+		}
+		if node.minProperties != nil && len(value) < *node.minProperties {
+			v.addError(path, "must have at least %d properties", *node.minProperties)
+		}
+	}
+	if node.maxProperties != nil && len(value) > *node.maxProperties {
+		v.addError(path, "must have at most %d properties", *node.maxProperties)
+	}
+
+	// Validate properties in a stable order.
+	names := make([]string, 0, len(value))
+	for name := range value {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		propValue := value[name]
+		if partial && propValue == nil {
+			// Deleted keys.
+			continue
+		}
+		propPath := path + "/" + escapeJSONPointer(name)
+		if propNode, ok := node.properties[name]; ok {
+			v.validate(propNode, propValue, propPath, partial)
+		} else if node.additionalProperties != nil {
+			v.validate(node.additionalProperties, propValue, propPath, partial)
+		}
+	}
+}
+
+func (v *schemaValidation) validateArray(node *schemaNode, value []any, path string) {
+	if node.minItems != nil && len(value) < *node.minItems {
+		v.addError(path, "must have at least %d items", *node.minItems)
+	}
+	if node.maxItems != nil && len(value) > *node.maxItems {
+		v.addError(path, "must have at most %d items", *node.maxItems)
+	}
+	if node.uniqueItems {
+		for i := 0; i < len(value); i++ {
+			for j := i + 1; j < len(value); j++ {
+				if schemaValuesEqual(value[i], value[j]) {
+					v.addError(path, "must have unique items, but items %d and %d are equal", i, j)
+				}
+			}
+		}
+	}
+	// Arrays are replaced and not merged, so items are always
+	// validated as complete documents.
+	for i, item := range value {
+		itemPath := path + "/" + strconv.Itoa(i)
+		if i < len(node.prefixItems) {
+			v.validate(node.prefixItems[i], item, itemPath, false)
+		} else {
+			v.validate(node.items, item, itemPath, false)
+		}
+	}
+}
+
+func (v *schemaValidation) validateNumber(node *schemaNode, value float64, path string) {
+	if node.minimum != nil && value < *node.minimum {
+		v.addError(path, "must be greater than or equal to %v", *node.minimum)
+	}
+	if node.maximum != nil && value > *node.maximum {
+		v.addError(path, "must be less than or equal to %v", *node.maximum)
+	}
+	if node.exclusiveMinimum != nil && value <= *node.exclusiveMinimum {
+		v.addError(path, "must be greater than %v", *node.exclusiveMinimum)
+	}
+	if node.exclusiveMaximum != nil && value >= *node.exclusiveMaximum {
+		v.addError(path, "must be less than %v", *node.exclusiveMaximum)
+	}
+	if node.multipleOf != nil && *node.multipleOf != 0 {
+		quotient := value / *node.multipleOf
+		if quotient != math.Trunc(quotient) {
+			v.addError(path, "must be a multiple of %v", *node.multipleOf)
+		}
+	}
+}
+
+func matchesSchemaType(types []string, value any) bool {
+	for _, t := range types {
+		switch t {
+		case "null":
+			if value == nil {
+				return true
+			}
+		case "boolean":
+			if _, ok := value.(bool); ok {
+				return true
+			}
+		case "object":
+			if _, ok := value.(map[string]any); ok {
+				return true
+			}
+		case "array":
+			if _, ok := value.([]any); ok {
+				return true
+			}
+		case "string":
+			if _, ok := value.(string); ok {
+				return true
+			}
+		case "number":
+			if _, ok := value.(float64); ok {
+				return true
+			}
+		case "integer":
+			if n, ok := value.(float64); ok && n == math.Trunc(n) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func schemaValuesEqual(a, b any) bool {
+	return reflect.DeepEqual(a, b)
+}
+
+func formatSchemaValues(values []any) string {
+	formatted := make([]string, len(values))
+	for i, value := range values {
+		data, err := json.Marshal(value)
+		if err != nil {
+			formatted[i] = fmt.Sprint(value)
+		} else {
+			formatted[i] = string(data)
+		}
+	}
+	return strings.Join(formatted, ", ")
+}
+
+func escapeJSONPointer(token string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(token, "~", "~0"), "/", "~1")
+}
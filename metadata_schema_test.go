@@ -0,0 +1,157 @@
+package clerk
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const testMetadataSchema = `{
+	"$schema": "https://json-schema.org/draft/2020-12/schema",
+	"type": "object",
+	"properties": {
+		"plan": {"enum": ["free", "pro"]},
+		"seats": {"type": "integer", "minimum": 1, "maximum": 100},
+		"nickname": {"type": "string", "minLength": 2, "maxLength": 5, "pattern": "^[a-z]+$"},
+		"tags": {"type": "array", "items": {"type": "string"}, "uniqueItems": true, "maxItems": 3},
+		"address": {"$ref": "#/$defs/address"},
+		"contact": {"oneOf": [{"type": "string"}, {"type": "object", "required": ["email"]}]}
+	},
+	"required": ["plan"],
+	"additionalProperties": false,
+	"$defs": {
+		"address": {
+			"type": "object",
+			"properties": {"zip": {"type": "string"}, "country": {"const": "US"}},
+			"required": ["zip"]
+		}
+	}
+}`
+
+func TestMetadataSchema(t *testing.T) {
+	t.Parallel()
+	schema, err := NewMetadataSchema([]byte(testMetadataSchema))
+	require.NoError(t, err)
+
+	for _, tc := range []struct {
+		name  string
+		data  string
+		merge bool
+		want  []MetadataFieldError
+	}{
+		{
+			name: "valid",
+			data: `{"plan":"pro","seats":10,"nickname":"abc","tags":["a","b"],"address":{"zip":"12345","country":"US"},"contact":{"email":"a@b.c"}}`,
+		},
+		{
+			name: "invalid fields",
+			data: `{"seats":1.5,"nickname":"ABCDEF","tags":["a","a"],"address":{"country":"CA"},"contact":1,"extra":true}`,
+			want: []MetadataFieldError{
+				{Path: "/plan", Message: "is required"},
+				{Path: "/address/zip", Message: "is required"},
+				{Path: "/address/country", Message: `must be "US"`},
+				{Path: "/contact", Message: "must match exactly one schema in oneOf, but matches 0"},
+				{Path: "/extra", Message: "is not allowed"},
+				{Path: "/nickname", Message: "must be at most 5 characters long"},
+				{Path: "/nickname", Message: "must match the pattern ^[a-z]+$"},
+				{Path: "/seats", Message: "must be of type integer"},
+				{Path: "/tags", Message: "must have unique items, but items 0 and 1 are equal"},
+			},
+		},
+		{
+			name: "wrong type",
+			data: `[]`,
+			want: []MetadataFieldError{{Path: "", Message: "must be of type object"}},
+		},
+		{
+			name:  "partial update",
+			data:  `{"seats":5,"nickname":null,"address":{"country":"US"}}`,
+			merge: true,
+		},
+		{
+			name:  "partial update with invalid values",
+			data:  `{"seats":0,"tags":[1]}`,
+			merge: true,
+			want: []MetadataFieldError{
+				{Path: "/seats", Message: "must be greater than or equal to 1"},
+				{Path: "/tags/0", Message: "must be of type string"},
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			err := schema.ValidateMetadata(&MetadataValidationParams{
+				Resource: MetadataResourceUser,
+				Kind:     MetadataKindPublic,
+				Data:     json.RawMessage(tc.data),
+				Merge:    tc.merge,
+			})
+			if tc.want == nil {
+				require.NoError(t, err)
+				return
+			}
+			var validationErr *MetadataValidationError
+			require.ErrorAs(t, err, &validationErr)
+			require.ElementsMatch(t, tc.want, validationErr.Errors)
+		})
+	}
+}
+
+func TestMetadataSchema_RecursiveRef(t *testing.T) {
+	t.Parallel()
+	schema, err := NewMetadataSchema([]byte(`{
+		"$defs": {"node": {"type": "object", "properties": {"children": {"type": "array", "items": {"$ref": "#/$defs/node"}}}}},
+		"$ref": "#/$defs/node"
+	}`))
+	require.NoError(t, err)
+	err = schema.ValidateMetadata(&MetadataValidationParams{Data: json.RawMessage(`{"children":[{"children":[]}]}`)})
+	require.NoError(t, err)
+	err = schema.ValidateMetadata(&MetadataValidationParams{Data: json.RawMessage(`{"children":[{"children":[1]}]}`)})
+	require.Error(t, err)
+}
+
+func TestMetadataSchema_CircularRef(t *testing.T) {
+	t.Parallel()
+	for _, schema := range []string{
+		`{"$defs":{"a":{"allOf":[{"$ref":"#/$defs/a"}]}},"$ref":"#/$defs/a"}`,
+		`{"$defs":{"a":{"anyOf":[{"$ref":"#/$defs/a"}]}},"$ref":"#/$defs/a"}`,
+		`{"$defs":{"a":{"oneOf":[{"type":"string"},{"$ref":"#/$defs/b"}]},"b":{"not":{"$ref":"#/$defs/a"}}},"properties":{"x":{"$ref":"#/$defs/a"}}}`,
+	} {
+		_, err := NewMetadataSchema([]byte(schema))
+		require.ErrorContains(t, err, "circular $ref", schema)
+	}
+
+	// Recursive references that descend into the value are allowed.
+	schema, err := NewMetadataSchema([]byte(`{"type":"object","properties":{"name":{"type":"string"},"children":{"type":"array","items":{"$ref":"#"}}}}`))
+	require.NoError(t, err)
+	err = schema.ValidateMetadata(&MetadataValidationParams{Data: json.RawMessage(`{"children":[{"name":"a","children":[{"name":1}]}]}`)})
+	var validationErr *MetadataValidationError
+	require.ErrorAs(t, err, &validationErr)
+	require.Equal(t, "/children/0/children/0/name", validationErr.Errors[0].Path)
+
+	// Cycles are also detected while validating, including cycles
+	// through anyOf.
+	compiler := &schemaCompiler{compiled: map[string]*schemaNode{}}
+	node := &schemaNode{ref: "#/$defs/a", compiler: compiler}
+	compiler.compiled["#/$defs/a"] = &schemaNode{anyOf: []*schemaNode{node}}
+	err = (&MetadataSchema{root: node}).ValidateMetadata(&MetadataValidationParams{Data: json.RawMessage(`{"a":1}`)})
+	require.ErrorContains(t, err, "must match at least one schema in anyOf")
+}
+
+func TestNewMetadataSchema_Invalid(t *testing.T) {
+	t.Parallel()
+	for _, schema := range []string{
+		`"object"`,
+		`{"type":1}`,
+		`{"pattern":"("}`,
+		`{"minLength":-1}`,
+		`{"$ref":"#/$defs/missing"}`,
+		`{"$ref":"https://example.com/schema.json"}`,
+		`{"$ref":"#"}`,
+		`{"$defs":{"a":{"$ref":"#/$defs/a"}},"properties":{"x":{"$ref":"#/$defs/a"}}}`,
+		`{"$defs":{"a":{"$ref":"#/$defs/b"},"b":{"$ref":"#/$defs/a"}},"$ref":"#/$defs/a"}`,
+	} {
+		_, err := NewMetadataSchema([]byte(schema))
+		require.Error(t, err, schema)
+	}
+}
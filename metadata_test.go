@@ -0,0 +1,112 @@
+package clerk
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecodeMetadata(t *testing.T) {
+	t.Parallel()
+	type preferences struct {
+		Theme string `json:"theme"`
+	}
+	prefs, err := DecodeMetadata[preferences](json.RawMessage(`{"theme":"dark","other":1}`))
+	require.NoError(t, err)
+	require.Equal(t, "dark", prefs.Theme)
+
+	for _, data := range []json.RawMessage{nil, json.RawMessage("null"), json.RawMessage(" ")} {
+		prefs, err = DecodeMetadata[preferences](data)
+		require.NoError(t, err)
+		require.Equal(t, preferences{}, prefs)
+	}
+
+	_, err = DecodeMetadata[preferences](json.RawMessage(`{"theme":1}`))
+	require.Error(t, err)
+}
+
+func TestMergeMetadata(t *testing.T) {
+	t.Parallel()
+	for _, tc := range []struct {
+		name    string
+		current string
+		update  any
+		want    string
+	}{
+		{
+			name:    "deep merge",
+			current: `{"a":1,"nested":{"b":2,"c":3}}`,
+			update:  json.RawMessage(`{"nested":{"c":4,"d":5},"e":6}`),
+			want:    `{"a":1,"e":6,"nested":{"b":2,"c":4,"d":5}}`,
+		},
+		{
+			name:    "key deletion",
+			current: `{"a":1,"nested":{"b":2,"c":3}}`,
+			update:  MetadataPatch{}.Delete("a").Set("nested", MetadataPatch{}.Delete("b")),
+			want:    `{"nested":{"c":3}}`,
+		},
+		{
+			name:    "non-objects replace values",
+			current: `{"list":[1,2],"nested":{"b":2}}`,
+			update:  map[string]any{"list": []int{3}, "nested": "flat"},
+			want:    `{"list":[3],"nested":"flat"}`,
+		},
+		{
+			name:    "empty current metadata",
+			current: ``,
+			update:  struct{ Theme string }{Theme: "dark"},
+			want:    `{"Theme":"dark"}`,
+		},
+		{
+			name:    "empty update",
+			current: `{"a":1}`,
+			update:  (*json.RawMessage)(nil),
+			want:    `{"a":1}`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := MergeMetadata(json.RawMessage(tc.current), tc.update)
+			require.NoError(t, err)
+			require.JSONEq(t, tc.want, string(got))
+		})
+	}
+
+	_, err := MergeMetadata(json.RawMessage(`{}`), []int{1})
+	require.Error(t, err)
+}
+
+func TestMetadataDiff(t *testing.T) {
+	t.Parallel()
+	current := json.RawMessage(`{"a":1,"b":[1],"nested":{"c":2,"d":3},"e":"same"}`)
+	updated := json.RawMessage(`{"b":[1,2],"nested":{"c":2,"f":4},"e":"same","g":true}`)
+	diff, err := MetadataDiff(current, updated)
+	require.NoError(t, err)
+	require.JSONEq(t, `{"a":null,"b":[1,2],"nested":{"d":null,"f":4},"g":true}`, string(*diff))
+
+	// Applying the diff results in the updated metadata.
+	merged, err := MergeMetadata(current, diff)
+	require.NoError(t, err)
+	require.JSONEq(t, string(updated), string(merged))
+
+	diff, err = MetadataDiff(current, current)
+	require.NoError(t, err)
+	require.Nil(t, diff)
+
+	diff, err = MetadataDiff(nil, json.RawMessage(`{}`))
+	require.NoError(t, err)
+	require.Nil(t, diff)
+}
+
+func TestMetadata_LargeIntegers(t *testing.T) {
+	t.Parallel()
+	// 2^53 + 1 can't be represented as a float64.
+	current := json.RawMessage(`{"id":9007199254740993,"nested":{"id":9223372036854775807}}`)
+	merged, err := MergeMetadata(current, json.RawMessage(`{"theme":"dark"}`))
+	require.NoError(t, err)
+	require.Equal(t, `{"id":9007199254740993,"nested":{"id":9223372036854775807},"theme":"dark"}`, string(merged))
+
+	diff, err := MetadataDiff(current, json.RawMessage(`{"id":9007199254740993,"nested":{"id":9223372036854775806}}`))
+	require.NoError(t, err)
+	require.Equal(t, `{"nested":{"id":9223372036854775806}}`, string(*diff))
+}
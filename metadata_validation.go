@@ -0,0 +1,161 @@
+package clerk
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// MetadataResource is the type of resource that metadata belong to.
+type MetadataResource string
+
+const (
+	MetadataResourceUser                   MetadataResource = "user"
+	MetadataResourceOrganization           MetadataResource = "organization"
+	MetadataResourceOrganizationMembership MetadataResource = "organization_membership"
+	MetadataResourceInvitation             MetadataResource = "invitation"
+	MetadataResourceOrganizationInvitation MetadataResource = "organization_invitation"
+)
+
+// MetadataKind is the kind of metadata, based on their visibility.
+type MetadataKind string
+
+const (
+	MetadataKindPublic  MetadataKind = "public"
+	MetadataKindPrivate MetadataKind = "private"
+	MetadataKindUnsafe  MetadataKind = "unsafe"
+)
+
+// MetadataValidationParams holds the metadata that will be sent to
+// the Clerk API.
+type MetadataValidationParams struct {
+	Resource MetadataResource
+	Kind     MetadataKind
+	Data     json.RawMessage
+	// Merge is true if the metadata will be merged with the existing
+	// metadata, like when calling an UpdateMetadata method. In that
+	// case Data can hold a partial document, in which keys with null
+	// values will be deleted.
+	Merge bool
+}
+
+// MetadataValidator validates metadata before they are sent to the
+// Clerk API. Validators can return a *MetadataValidationError to
+// report errors for specific fields.
+type MetadataValidator interface {
+	ValidateMetadata(params *MetadataValidationParams) error
+}
+
+// MetadataValidatorFunc is an adapter to allow the use of ordinary
+// functions as metadata validators.
+type MetadataValidatorFunc func(params *MetadataValidationParams) error
+
+// ValidateMetadata calls f(params).
+func (f MetadataValidatorFunc) ValidateMetadata(params *MetadataValidationParams) error {
+	return f(params)
+}
+
+// MetadataFieldError describes an invalid metadata field.
+type MetadataFieldError struct {
+	// Path is the JSON pointer of the invalid field, for example
+	// "/address/zip". The path is empty for errors on the whole
+	// document.
+	Path    string `json:"path"`
+	Message string `json:"message"`
+}
+
+// MetadataValidationError is returned by create and update
+// operations when the metadata are rejected by a registered
+// validator. The request is not sent to the Clerk API.
+type MetadataValidationError struct {
+	Resource MetadataResource     `json:"resource"`
+	Kind     MetadataKind         `json:"kind"`
+	Errors   []MetadataFieldError `json:"errors"`
+}
+
+func (e *MetadataValidationError) Error() string {
+	fieldErrors := make([]string, len(e.Errors))
+	for i, fieldErr := range e.Errors {
+		if fieldErr.Path == "" {
+			fieldErrors[i] = fieldErr.Message
+		} else {
+			fieldErrors[i] = fmt.Sprintf("%s: %s", fieldErr.Path, fieldErr.Message)
+		}
+	}
+	return fmt.Sprintf("clerk: invalid %s %s metadata: %s", e.Resource, e.Kind, strings.Join(fieldErrors, "; "))
+}
+
+type metadataValidatorKey struct {
+	resource MetadataResource
+	kind     MetadataKind
+}
+
+var metadataValidators = struct {
+	mu         sync.RWMutex
+	validators map[metadataValidatorKey][]MetadataValidator
+}{
+	validators: map[metadataValidatorKey][]MetadataValidator{},
+}
+
+// RegisterMetadataValidator registers a validator for the resource
+// and kind of metadata. Validators run in the order they were
+// registered, whenever the metadata are sent to the Clerk API by
+// any client.
+func RegisterMetadataValidator(resource MetadataResource, kind MetadataKind, validator MetadataValidator) {
+	metadataValidators.mu.Lock()
+	defer metadataValidators.mu.Unlock()
+	key := metadataValidatorKey{resource: resource, kind: kind}
+	metadataValidators.validators[key] = append(metadataValidators.validators[key], validator)
+}
+
+// UnregisterMetadataValidators removes all validators for the
+// resource and kind of metadata.
+func UnregisterMetadataValidators(resource MetadataResource, kind MetadataKind) {
+	metadataValidators.mu.Lock()
+	defer metadataValidators.mu.Unlock()
+	delete(metadataValidators.validators, metadataValidatorKey{resource: resource, kind: kind})
+}
+
+// ValidateMetadata runs the registered validators for the resource
+// and kind of metadata. Nil data are not validated. Errors are
+// returned as a *MetadataValidationError.
+func ValidateMetadata(resource MetadataResource, kind MetadataKind, data *json.RawMessage, merge bool) error {
+	if data == nil {
+		return nil
+	}
+	metadataValidators.mu.RLock()
+	validators := metadataValidators.validators[metadataValidatorKey{resource: resource, kind: kind}]
+	metadataValidators.mu.RUnlock()
+	if len(validators) == 0 {
+		return nil
+	}
+
+	params := &MetadataValidationParams{
+		Resource: resource,
+		Kind:     kind,
+		Data:     *data,
+		Merge:    merge,
+	}
+	validationErr := &MetadataValidationError{
+		Resource: resource,
+		Kind:     kind,
+	}
+	for _, validator := range validators {
+		err := validator.ValidateMetadata(params)
+		if err == nil {
+			continue
+		}
+		var fieldsErr *MetadataValidationError
+		if errors.As(err, &fieldsErr) {
+			validationErr.Errors = append(validationErr.Errors, fieldsErr.Errors...)
+		} else {
+			validationErr.Errors = append(validationErr.Errors, MetadataFieldError{Message: err.Error()})
+		}
+	}
+	if len(validationErr.Errors) > 0 {
+		return validationErr
+	}
+	return nil
+}
@@ -0,0 +1,45 @@
+package clerk
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateMetadata(t *testing.T) {
+	t.Cleanup(func() {
+		UnregisterMetadataValidators(MetadataResourceOrganization, MetadataKindPrivate)
+	})
+	RegisterMetadataValidator(MetadataResourceOrganization, MetadataKindPrivate, MetadataValidatorFunc(func(params *MetadataValidationParams) error {
+		if !params.Merge {
+			return fmt.Errorf("only merge updates are allowed")
+		}
+		return nil
+	}))
+	RegisterMetadataValidator(MetadataResourceOrganization, MetadataKindPrivate, MetadataValidatorFunc(func(params *MetadataValidationParams) error {
+		return &MetadataValidationError{
+			Errors: []MetadataFieldError{{Path: "/a", Message: "is invalid"}},
+		}
+	}))
+
+	data := json.RawMessage(`{"a":1}`)
+	err := ValidateMetadata(MetadataResourceOrganization, MetadataKindPrivate, &data, false)
+	var validationErr *MetadataValidationError
+	require.ErrorAs(t, err, &validationErr)
+	require.Equal(t, MetadataResourceOrganization, validationErr.Resource)
+	require.Equal(t, MetadataKindPrivate, validationErr.Kind)
+	require.Equal(t, []MetadataFieldError{
+		{Message: "only merge updates are allowed"},
+		{Path: "/a", Message: "is invalid"},
+	}, validationErr.Errors)
+	require.EqualError(t, err, "clerk: invalid organization private metadata: only merge updates are allowed; /a: is invalid")
+
+	// Nil metadata and metadata without validators are not validated.
+	require.NoError(t, ValidateMetadata(MetadataResourceOrganization, MetadataKindPrivate, nil, false))
+	require.NoError(t, ValidateMetadata(MetadataResourceOrganization, MetadataKindPublic, &data, false))
+
+	UnregisterMetadataValidators(MetadataResourceOrganization, MetadataKindPrivate)
+	require.NoError(t, ValidateMetadata(MetadataResourceOrganization, MetadataKindPrivate, &data, false))
+}
@@ -36,6 +36,13 @@ func UpdateMetadata(ctx context.Context, id string, params *UpdateMetadataParams
 	return getClient().UpdateMetadata(ctx, id, params)
 }
 
+// ModifyMetadata updates the organization's metadata with the
+// params.Modify function. See clerk.ModifyMetadata for how
+// concurrent updates are detected and retried.
+func ModifyMetadata(ctx context.Context, id string, params *ModifyMetadataParams) (*clerk.Organization, error) {
+	return getClient().ModifyMetadata(ctx, id, params)
+}
+
 // Delete deletes an organization.
 func Delete(ctx context.Context, id string) (*clerk.DeletedResource, error) {
 	return getClient().Delete(ctx, id)
@@ -5,6 +5,7 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"io"
 	"mime/multipart"
 	"net/http"
@@ -41,6 +42,9 @@ type CreateParams struct {
 
 // Create creates a new organization.
 func (c *Client) Create(ctx context.Context, params *CreateParams) (*clerk.Organization, error) {
+	if err := validateMetadata(params.PublicMetadata, params.PrivateMetadata, false); err != nil {
+		return nil, err
+	}
 	req := clerk.NewAPIRequest(http.MethodPost, path)
 	req.SetParams(params)
 	organization := &clerk.Organization{}
@@ -93,6 +97,9 @@ type UpdateParams struct {
 
 // Update updates an organization.
 func (c *Client) Update(ctx context.Context, id string, params *UpdateParams) (*clerk.Organization, error) {
+	if err := validateMetadata(params.PublicMetadata, params.PrivateMetadata, false); err != nil {
+		return nil, err
+	}
 	path, err := clerk.JoinPath(path, id)
 	if err != nil {
 		return nil, err
@@ -113,6 +120,9 @@ type UpdateMetadataParams struct {
 // UpdateMetadata updates the organization's metadata by merging the
 // provided values with the existing ones.
 func (c *Client) UpdateMetadata(ctx context.Context, id string, params *UpdateMetadataParams) (*clerk.Organization, error) {
+	if err := validateMetadata(params.PublicMetadata, params.PrivateMetadata, true); err != nil {
+		return nil, err
+	}
 	path, err := clerk.JoinPath(path, id, "/metadata")
 	if err != nil {
 		return nil, err
@@ -124,6 +134,42 @@ func (c *Client) UpdateMetadata(ctx context.Context, id string, params *UpdateMe
 	return organization, err
 }
 
+type ModifyMetadataParams struct {
+	clerk.MetadataRetryParams
+	// Modify is called with the latest version of the organization
+	// and changes its PublicMetadata or PrivateMetadata in place. It
+	// might be called more than once.
+	Modify func(organization *clerk.Organization) error
+}
+
+// ModifyMetadata updates the organization's metadata with the
+// params.Modify function. See clerk.ModifyMetadata for how
+// concurrent updates are detected and retried.
+func (c *Client) ModifyMetadata(ctx context.Context, id string, params *ModifyMetadataParams) (*clerk.Organization, error) {
+	if params == nil {
+		return nil, errors.New("missing Modify function")
+	}
+	return clerk.ModifyMetadata(ctx, &params.MetadataRetryParams, &clerk.MetadataModifier[clerk.Organization]{
+		Get: func(ctx context.Context) (*clerk.Organization, error) {
+			return c.Get(ctx, id)
+		},
+		Modify: params.Modify,
+		Metadata: func(organization *clerk.Organization) *clerk.ResourceMetadata {
+			return &clerk.ResourceMetadata{
+				PublicMetadata:  organization.PublicMetadata,
+				PrivateMetadata: organization.PrivateMetadata,
+				UpdatedAt:       organization.UpdatedAt,
+			}
+		},
+		Update: func(ctx context.Context, changes *clerk.MetadataChanges) (*clerk.Organization, error) {
+			return c.UpdateMetadata(ctx, id, &UpdateMetadataParams{
+				PublicMetadata:  changes.PublicMetadata,
+				PrivateMetadata: changes.PrivateMetadata,
+			})
+		},
+	})
+}
+
 // Delete deletes an organization.
 func (c *Client) Delete(ctx context.Context, id string) (*clerk.DeletedResource, error) {
 	path, err := clerk.JoinPath(path, id)
@@ -248,3 +294,12 @@ func (c *Client) List(ctx context.Context, params *ListParams) (*clerk.Organizat
 	err := c.Backend.Call(ctx, req, list)
 	return list, err
 }
+
+// Runs the registered metadata validators for organizations.
+func validateMetadata(public, private *json.RawMessage, merge bool) error {
+	err := clerk.ValidateMetadata(clerk.MetadataResourceOrganization, clerk.MetadataKindPublic, public, merge)
+	if err != nil {
+		return err
+	}
+	return clerk.ValidateMetadata(clerk.MetadataResourceOrganization, clerk.MetadataKindPrivate, private, merge)
+}
@@ -0,0 +1,33 @@
+package organization
+
+import (
+	"context"
+
+	"github.com/clerk/clerk-sdk-go/v2"
+)
+
+// UpdatePublicMetadata encodes the metadata and merges it with the
+// organization's existing public metadata. Use a clerk.MetadataPatch
+// to delete keys.
+func UpdatePublicMetadata[T any](ctx context.Context, id string, metadata T) (*clerk.Organization, error) {
+	params := &UpdateMetadataParams{}
+	var err error
+	params.PublicMetadata, err = clerk.MarshalMetadata(metadata)
+	if err != nil {
+		return nil, err
+	}
+	return getClient().UpdateMetadata(ctx, id, params)
+}
+
+// UpdatePrivateMetadata encodes the metadata and merges it with the
+// organization's existing private metadata. Use a
+// clerk.MetadataPatch to delete keys.
+func UpdatePrivateMetadata[T any](ctx context.Context, id string, metadata T) (*clerk.Organization, error) {
+	params := &UpdateMetadataParams{}
+	var err error
+	params.PrivateMetadata, err = clerk.MarshalMetadata(metadata)
+	if err != nil {
+		return nil, err
+	}
+	return getClient().UpdateMetadata(ctx, id, params)
+}
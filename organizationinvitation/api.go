@@ -13,6 +13,29 @@ func Create(ctx context.Context, params *CreateParams) (*clerk.OrganizationInvit
 	return getClient().Create(ctx, params)
 }
 
+// BulkCreate creates and sends multiple invitations to join an
+// organization. The invitations are created atomically, so if any
+// invitation is invalid none of them is created.
+func BulkCreate(ctx context.Context, params *BulkCreateParams) (*clerk.OrganizationInvitationList, error) {
+	return getClient().BulkCreate(ctx, params)
+}
+
+// BulkCreateInChunks creates a large number of invitations in
+// chunks and reports the outcome for each invitation, in the same
+// order as params.Invitations.
+// Chunks are created with BulkCreate. If a chunk is rejected as
+// invalid, with a 400 or 422 response or by metadata validation, its
+// invitations are created one by one, so that a single invalid
+// invitation doesn't fail the rest of the chunk. Other errors, like
+// rate limiting or server errors, are reported for the whole chunk,
+// since some of its invitations might have been created already.
+// The returned error is only set if the context is done; the
+// remaining invitations are reported as failed with the context
+// error.
+func BulkCreateInChunks(ctx context.Context, params *BulkCreateInChunksParams) ([]*BulkCreateResult, error) {
+	return getClient().BulkCreateInChunks(ctx, params)
+}
+
 // List returns a list of organization invitations
 func List(ctx context.Context, params *ListParams) (*clerk.OrganizationInvitationList, error) {
 	return getClient().List(ctx, params)
@@ -4,8 +4,11 @@ package organizationinvitation
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
 	"net/url"
+	"strings"
 
 	"github.com/clerk/clerk-sdk-go/v2"
 )
@@ -39,6 +42,14 @@ type CreateParams struct {
 
 // Create creates and sends an invitation to join an organization.
 func (c *Client) Create(ctx context.Context, params *CreateParams) (*clerk.OrganizationInvitation, error) {
+	err := clerk.ValidateMetadata(clerk.MetadataResourceOrganizationInvitation, clerk.MetadataKindPublic, params.PublicMetadata, false)
+	if err != nil {
+		return nil, err
+	}
+	err = clerk.ValidateMetadata(clerk.MetadataResourceOrganizationInvitation, clerk.MetadataKindPrivate, params.PrivateMetadata, false)
+	if err != nil {
+		return nil, err
+	}
 	path, err := clerk.JoinPath(path, params.OrganizationID, "/invitations")
 	if err != nil {
 		return nil, err
@@ -50,6 +61,162 @@ func (c *Client) Create(ctx context.Context, params *CreateParams) (*clerk.Organ
 	return invitation, err
 }
 
+type BulkCreateParams struct {
+	clerk.APIParams
+	// Invitations holds the invitations to create. Their
+	// OrganizationID is ignored.
+	Invitations    []*CreateParams
+	OrganizationID string `json:"-"`
+}
+
+func (params BulkCreateParams) MarshalJSON() ([]byte, error) {
+	return json.Marshal(params.Invitations)
+}
+
+// BulkCreate creates and sends multiple invitations to join an
+// organization. The invitations are created atomically, so if any
+// invitation is invalid none of them is created.
+func (c *Client) BulkCreate(ctx context.Context, params *BulkCreateParams) (*clerk.OrganizationInvitationList, error) {
+	for _, invitation := range params.Invitations {
+		err := clerk.ValidateMetadata(clerk.MetadataResourceOrganizationInvitation, clerk.MetadataKindPublic, invitation.PublicMetadata, false)
+		if err != nil {
+			return nil, err
+		}
+		err = clerk.ValidateMetadata(clerk.MetadataResourceOrganizationInvitation, clerk.MetadataKindPrivate, invitation.PrivateMetadata, false)
+		if err != nil {
+			return nil, err
+		}
+	}
+	path, err := clerk.JoinPath(path, params.OrganizationID, "/invitations/bulk")
+	if err != nil {
+		return nil, err
+	}
+	req := clerk.NewAPIRequest(http.MethodPost, path)
+	req.SetParams(params)
+	list := &clerk.OrganizationInvitationList{}
+	err = c.Backend.Call(ctx, req, list)
+	return list, err
+}
+
+const defaultBulkCreateChunkSize = 50
+
+type BulkCreateInChunksParams struct {
+	BulkCreateParams
+	// ChunkSize is the maximum number of invitations that are created
+	// with a single request. Defaults to 50.
+	ChunkSize int
+}
+
+// BulkCreateResult holds the outcome of creating a single invitation
+// with BulkCreateInChunks. Either Invitation or Err is set.
+type BulkCreateResult struct {
+	EmailAddress string
+	Invitation   *clerk.OrganizationInvitation
+	Err          error
+}
+
+// BulkCreateInChunks creates a large number of invitations in
+// chunks and reports the outcome for each invitation, in the same
+// order as params.Invitations.
+// Chunks are created with BulkCreate. If a chunk is rejected as
+// invalid, with a 400 or 422 response or by metadata validation, its
+// invitations are created one by one, so that a single invalid
+// invitation doesn't fail the rest of the chunk. Other errors, like
+// rate limiting or server errors, are reported for the whole chunk,
+// since some of its invitations might have been created already.
+// The returned error is only set if the context is done; the
+// remaining invitations are reported as failed with the context
+// error.
+func (c *Client) BulkCreateInChunks(ctx context.Context, params *BulkCreateInChunksParams) ([]*BulkCreateResult, error) {
+	chunkSize := params.ChunkSize
+	if chunkSize <= 0 {
+		chunkSize = defaultBulkCreateChunkSize
+	}
+	results := make([]*BulkCreateResult, len(params.Invitations))
+	for i, invitation := range params.Invitations {
+		results[i] = &BulkCreateResult{}
+		if invitation.EmailAddress != nil {
+			results[i].EmailAddress = *invitation.EmailAddress
+		}
+	}
+
+	for start := 0; start < len(params.Invitations); start += chunkSize {
+		end := start + chunkSize
+		if end > len(params.Invitations) {
+			end = len(params.Invitations)
+		}
+		if err := ctx.Err(); err != nil {
+			for _, res := range results[start:] {
+				res.Err = err
+			}
+			return results, err
+		}
+		c.createChunk(ctx, params.OrganizationID, params.Invitations[start:end], results[start:end])
+	}
+	return results, ctx.Err()
+}
+
+// Creates a chunk of invitations and stores the outcome in results,
+// which has the same length as the chunk.
+func (c *Client) createChunk(ctx context.Context, organizationID string, chunk []*CreateParams, results []*BulkCreateResult) {
+	list, err := c.BulkCreate(ctx, &BulkCreateParams{
+		OrganizationID: organizationID,
+		Invitations:    chunk,
+	})
+	if err == nil {
+		// Invitations are matched to results by email address. The
+		// chunk is never retried after a successful request, as that
+		// would send the created invitations again.
+		byEmailAddress := map[string][]*clerk.OrganizationInvitation{}
+		for _, invitation := range list.OrganizationInvitations {
+			key := strings.ToLower(invitation.EmailAddress)
+			byEmailAddress[key] = append(byEmailAddress[key], invitation)
+		}
+		for _, res := range results {
+			key := strings.ToLower(res.EmailAddress)
+			if invitations := byEmailAddress[key]; len(invitations) > 0 {
+				res.Invitation = invitations[0]
+				byEmailAddress[key] = invitations[1:]
+			} else {
+				res.Err = fmt.Errorf("no invitation was returned for %s", res.EmailAddress)
+			}
+		}
+		return
+	}
+	if !isInvalidChunk(err) {
+		for _, res := range results {
+			res.Err = err
+		}
+		return
+	}
+
+	// Find out which invitations failed.
+	for i, invitation := range chunk {
+		if err := ctx.Err(); err != nil {
+			results[i].Err = err
+			continue
+		}
+		createParams := *invitation
+		createParams.OrganizationID = organizationID
+		results[i].Invitation, results[i].Err = c.Create(ctx, &createParams)
+		if results[i].Err != nil {
+			results[i].Invitation = nil
+		}
+	}
+}
+
+// Reports whether the chunk was rejected because of invalid
+// invitations, in which case none of them was created.
+func isInvalidChunk(err error) bool {
+	var validationErr *clerk.MetadataValidationError
+	if errors.As(err, &validationErr) {
+		return true
+	}
+	var apiErr *clerk.APIErrorResponse
+	return errors.As(err, &apiErr) &&
+		(apiErr.HTTPStatusCode == http.StatusBadRequest || apiErr.HTTPStatusCode == http.StatusUnprocessableEntity)
+}
+
 type ListParams struct {
 	clerk.APIParams
 	clerk.ListParams
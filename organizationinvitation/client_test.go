@@ -5,7 +5,10 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"net/http/httptest"
 	"net/url"
+	"strings"
+	"sync"
 	"testing"
 
 	"github.com/clerk/clerk-sdk-go/v2"
@@ -66,6 +69,204 @@ func TestOrganizationInvitationClientCreate_Error(t *testing.T) {
 	require.Equal(t, "create-error-code", apiErr.Errors[0].Code)
 }
 
+func TestOrganizationInvitationClientBulkCreate(t *testing.T) {
+	t.Parallel()
+	organizationID := "org_123"
+	emailAddresses := []string{"foo@bar.com", "baz@bar.com"}
+	config := &clerk.ClientConfig{}
+	config.HTTPClient = &http.Client{
+		Transport: &clerktest.RoundTripper{
+			T:      t,
+			In:     json.RawMessage(fmt.Sprintf(`[{"email_address":"%s","role":"org:member"},{"email_address":"%s","role":"org:admin"}]`, emailAddresses[0], emailAddresses[1])),
+			Out:    json.RawMessage(fmt.Sprintf(`{"data":[{"id":"orginv_1","email_address":"%s"},{"id":"orginv_2","email_address":"%s"}],"total_count":2}`, emailAddresses[0], emailAddresses[1])),
+			Method: http.MethodPost,
+			Path:   "/v1/organizations/" + organizationID + "/invitations/bulk",
+		},
+	}
+	client := NewClient(config)
+	list, err := client.BulkCreate(context.Background(), &BulkCreateParams{
+		OrganizationID: organizationID,
+		Invitations: []*CreateParams{
+			{EmailAddress: clerk.String(emailAddresses[0]), Role: clerk.String("org:member")},
+			{EmailAddress: clerk.String(emailAddresses[1]), Role: clerk.String("org:admin")},
+		},
+	})
+	require.NoError(t, err)
+	require.Equal(t, int64(2), list.TotalCount)
+	require.Equal(t, 2, len(list.OrganizationInvitations))
+	require.Equal(t, "orginv_1", list.OrganizationInvitations[0].ID)
+	require.Equal(t, emailAddresses[1], list.OrganizationInvitations[1].EmailAddress)
+}
+
+func TestOrganizationInvitationClientBulkCreateInChunks(t *testing.T) {
+	t.Parallel()
+	organizationID := "org_123"
+	invalidEmailAddress := "invalid@bar.com"
+	var mu sync.Mutex
+	bulkRequests := 0
+	singleRequests := 0
+	clerkAPI := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, http.MethodPost, r.Method)
+		errorResponse := `{"errors":[{"code":"form_param_format_invalid"}]}`
+		if r.URL.Path == "/organizations/"+organizationID+"/invitations/bulk" {
+			var invitations []*CreateParams
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&invitations))
+			mu.Lock()
+			bulkRequests++
+			mu.Unlock()
+			data := make([]string, len(invitations))
+			for i, invitation := range invitations {
+				if *invitation.EmailAddress == invalidEmailAddress {
+					w.WriteHeader(http.StatusUnprocessableEntity)
+					_, _ = w.Write([]byte(errorResponse))
+					return
+				}
+				data[i] = fmt.Sprintf(`{"id":"orginv_%d","email_address":"%s"}`, i, *invitation.EmailAddress)
+			}
+			_, _ = w.Write([]byte(fmt.Sprintf(`{"data":[%s],"total_count":%d}`, strings.Join(data, ","), len(data))))
+			return
+		}
+		require.Equal(t, "/organizations/"+organizationID+"/invitations", r.URL.Path)
+		var invitation CreateParams
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&invitation))
+		mu.Lock()
+		singleRequests++
+		mu.Unlock()
+		if *invitation.EmailAddress == invalidEmailAddress {
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			_, _ = w.Write([]byte(errorResponse))
+			return
+		}
+		_, _ = w.Write([]byte(fmt.Sprintf(`{"id":"orginv_single","email_address":"%s"}`, *invitation.EmailAddress)))
+	}))
+	defer clerkAPI.Close()
+
+	config := &clerk.ClientConfig{}
+	config.HTTPClient = clerkAPI.Client()
+	config.URL = &clerkAPI.URL
+	client := NewClient(config)
+
+	emailAddresses := []string{"one@bar.com", "two@bar.com", "three@bar.com", invalidEmailAddress, "five@bar.com"}
+	invitations := make([]*CreateParams, len(emailAddresses))
+	for i, emailAddress := range emailAddresses {
+		invitations[i] = &CreateParams{EmailAddress: clerk.String(emailAddress)}
+	}
+	results, err := client.BulkCreateInChunks(context.Background(), &BulkCreateInChunksParams{
+		BulkCreateParams: BulkCreateParams{
+			OrganizationID: organizationID,
+			Invitations:    invitations,
+		},
+		ChunkSize: 2,
+	})
+	require.NoError(t, err)
+	require.Equal(t, len(emailAddresses), len(results))
+	for i, res := range results {
+		require.Equal(t, emailAddresses[i], res.EmailAddress)
+		if res.EmailAddress == invalidEmailAddress {
+			require.Nil(t, res.Invitation)
+			apiErr, ok := res.Err.(*clerk.APIErrorResponse)
+			require.True(t, ok)
+			require.Equal(t, "form_param_format_invalid", apiErr.Errors[0].Code)
+			continue
+		}
+		require.NoError(t, res.Err)
+		require.Equal(t, emailAddresses[i], res.Invitation.EmailAddress)
+	}
+	// The second chunk is retried one invitation at a time.
+	require.Equal(t, 3, bulkRequests)
+	require.Equal(t, 2, singleRequests)
+	require.Equal(t, "orginv_single", results[2].Invitation.ID)
+	require.Equal(t, "orginv_0", results[4].Invitation.ID)
+}
+
+func TestOrganizationInvitationClientBulkCreateInChunks_NoRetry(t *testing.T) {
+	t.Parallel()
+	for _, tc := range []struct {
+		name     string
+		status   int
+		response string
+		err      string
+	}{
+		{
+			name:     "server error",
+			status:   http.StatusInternalServerError,
+			response: `{"errors":[{"code":"internal_clerk_error"}]}`,
+			err:      "internal_clerk_error",
+		},
+		{
+			name:     "rate limited",
+			status:   http.StatusTooManyRequests,
+			response: `{"errors":[{"code":"too_many_requests"}]}`,
+			err:      "too_many_requests",
+		},
+		{
+			name:     "partial response",
+			status:   http.StatusOK,
+			response: `{"data":[{"id":"orginv_1","email_address":"one@bar.com"}],"total_count":1}`,
+			err:      "no invitation was returned for two@bar.com",
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			var mu sync.Mutex
+			var requests []string
+			clerkAPI := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				mu.Lock()
+				requests = append(requests, r.URL.Path)
+				mu.Unlock()
+				w.WriteHeader(tc.status)
+				_, _ = w.Write([]byte(tc.response))
+			}))
+			defer clerkAPI.Close()
+			config := &clerk.ClientConfig{}
+			config.HTTPClient = clerkAPI.Client()
+			config.URL = &clerkAPI.URL
+			client := NewClient(config)
+
+			results, err := client.BulkCreateInChunks(context.Background(), &BulkCreateInChunksParams{
+				BulkCreateParams: BulkCreateParams{
+					OrganizationID: "org_123",
+					Invitations: []*CreateParams{
+						{EmailAddress: clerk.String("one@bar.com")},
+						{EmailAddress: clerk.String("two@bar.com")},
+					},
+				},
+			})
+			require.NoError(t, err)
+			// Invitations are never created one by one, since the bulk
+			// request might have created some of them.
+			require.Equal(t, []string{"/organizations/org_123/invitations/bulk"}, requests)
+			require.ErrorContains(t, results[1].Err, tc.err)
+			if tc.status == http.StatusOK {
+				require.NoError(t, results[0].Err)
+				require.Equal(t, "orginv_1", results[0].Invitation.ID)
+			} else {
+				require.ErrorContains(t, results[0].Err, tc.err)
+			}
+		})
+	}
+}
+
+func TestOrganizationInvitationClientBulkCreateInChunks_ContextCanceled(t *testing.T) {
+	t.Parallel()
+	client := NewClient(&clerk.ClientConfig{})
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	results, err := client.BulkCreateInChunks(ctx, &BulkCreateInChunksParams{
+		BulkCreateParams: BulkCreateParams{
+			OrganizationID: "org_123",
+			Invitations: []*CreateParams{
+				{EmailAddress: clerk.String("foo@bar.com")},
+			},
+		},
+	})
+	require.ErrorIs(t, err, context.Canceled)
+	require.Equal(t, 1, len(results))
+	require.Equal(t, "foo@bar.com", results[0].EmailAddress)
+	require.ErrorIs(t, results[0].Err, context.Canceled)
+}
+
 func TestOrganizationInvitationClientList(t *testing.T) {
 	t.Parallel()
 	organizationID := "org_123"
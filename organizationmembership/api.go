@@ -18,6 +18,12 @@ func Update(ctx context.Context, params *UpdateParams) (*clerk.OrganizationMembe
 	return getClient().Update(ctx, params)
 }
 
+// UpdateMetadata updates the organization membership's metadata by
+// merging the provided values with the existing ones.
+func UpdateMetadata(ctx context.Context, params *UpdateMetadataParams) (*clerk.OrganizationMembership, error) {
+	return getClient().UpdateMetadata(ctx, params)
+}
+
 // Delete removes a member from an organization.
 func Delete(ctx context.Context, params *DeleteParams) (*clerk.OrganizationMembership, error) {
 	return getClient().Delete(ctx, params)
@@ -28,6 +34,13 @@ func List(ctx context.Context, params *ListParams) (*clerk.OrganizationMembershi
 	return getClient().List(ctx, params)
 }
 
+// ModifyMetadata updates the organization membership's metadata
+// with the params.Modify function. See clerk.ModifyMetadata for how
+// concurrent updates are detected and retried.
+func ModifyMetadata(ctx context.Context, params *ModifyMetadataParams) (*clerk.OrganizationMembership, error) {
+	return getClient().ModifyMetadata(ctx, params)
+}
+
 func getClient() *Client {
 	return &Client{
 		Backend: clerk.GetBackend(),
@@ -3,6 +3,9 @@ package organizationmembership
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
 	"net/url"
 	"strconv"
@@ -65,6 +68,36 @@ func (c *Client) Update(ctx context.Context, params *UpdateParams) (*clerk.Organ
 	return membership, err
 }
 
+type UpdateMetadataParams struct {
+	clerk.APIParams
+	PublicMetadata  *json.RawMessage `json:"public_metadata,omitempty"`
+	PrivateMetadata *json.RawMessage `json:"private_metadata,omitempty"`
+	OrganizationID  string           `json:"-"`
+	UserID          string           `json:"-"`
+}
+
+// UpdateMetadata updates the organization membership's metadata by
+// merging the provided values with the existing ones.
+func (c *Client) UpdateMetadata(ctx context.Context, params *UpdateMetadataParams) (*clerk.OrganizationMembership, error) {
+	err := clerk.ValidateMetadata(clerk.MetadataResourceOrganizationMembership, clerk.MetadataKindPublic, params.PublicMetadata, true)
+	if err != nil {
+		return nil, err
+	}
+	err = clerk.ValidateMetadata(clerk.MetadataResourceOrganizationMembership, clerk.MetadataKindPrivate, params.PrivateMetadata, true)
+	if err != nil {
+		return nil, err
+	}
+	path, err := clerk.JoinPath(path, params.OrganizationID, "/memberships", params.UserID, "/metadata")
+	if err != nil {
+		return nil, err
+	}
+	req := clerk.NewAPIRequest(http.MethodPatch, path)
+	req.SetParams(params)
+	membership := &clerk.OrganizationMembership{}
+	err = c.Backend.Call(ctx, req, membership)
+	return membership, err
+}
+
 type DeleteParams struct {
 	clerk.APIParams
 	OrganizationID string `json:"-"`
@@ -172,3 +205,62 @@ func (c *Client) List(ctx context.Context, params *ListParams) (*clerk.Organizat
 	err = c.Backend.Call(ctx, req, list)
 	return list, err
 }
+
+type ModifyMetadataParams struct {
+	clerk.MetadataRetryParams
+	// Modify is called with the latest version of the organization
+	// membership and changes its PublicMetadata or PrivateMetadata in
+	// place. It might be called more than once.
+	Modify         func(membership *clerk.OrganizationMembership) error
+	OrganizationID string `json:"-"`
+	UserID         string `json:"-"`
+}
+
+// ModifyMetadata updates the organization membership's metadata
+// with the params.Modify function. See clerk.ModifyMetadata for how
+// concurrent updates are detected and retried.
+func (c *Client) ModifyMetadata(ctx context.Context, params *ModifyMetadataParams) (*clerk.OrganizationMembership, error) {
+	if params == nil {
+		return nil, errors.New("missing Modify function")
+	}
+	return clerk.ModifyMetadata(ctx, &params.MetadataRetryParams, &clerk.MetadataModifier[clerk.OrganizationMembership]{
+		Get: func(ctx context.Context) (*clerk.OrganizationMembership, error) {
+			return c.get(ctx, params.OrganizationID, params.UserID)
+		},
+		Modify: params.Modify,
+		Metadata: func(membership *clerk.OrganizationMembership) *clerk.ResourceMetadata {
+			return &clerk.ResourceMetadata{
+				PublicMetadata:  membership.PublicMetadata,
+				PrivateMetadata: membership.PrivateMetadata,
+				UpdatedAt:       membership.UpdatedAt,
+			}
+		},
+		Update: func(ctx context.Context, changes *clerk.MetadataChanges) (*clerk.OrganizationMembership, error) {
+			return c.UpdateMetadata(ctx, &UpdateMetadataParams{
+				PublicMetadata:  changes.PublicMetadata,
+				PrivateMetadata: changes.PrivateMetadata,
+				OrganizationID:  params.OrganizationID,
+				UserID:          params.UserID,
+			})
+		},
+	})
+}
+
+// Retrieves the membership of the user in the organization. There's
+// no endpoint for fetching a single membership, so the memberships
+// are listed and filtered by user ID.
+func (c *Client) get(ctx context.Context, organizationID, userID string) (*clerk.OrganizationMembership, error) {
+	params := &ListParams{
+		OrganizationID: organizationID,
+		UserIDs:        []string{userID},
+	}
+	params.Limit = clerk.Int64(1)
+	list, err := c.List(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+	if len(list.OrganizationMemberships) == 0 {
+		return nil, fmt.Errorf("user %s is not a member of organization %s", userID, organizationID)
+	}
+	return list.OrganizationMemberships[0], nil
+}
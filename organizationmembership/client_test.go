@@ -4,7 +4,9 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
+	"net/http/httptest"
 	"net/url"
 	"testing"
 
@@ -133,6 +135,36 @@ func TestOrganizationMembershipClientUpdate_Error(t *testing.T) {
 	require.Equal(t, "update-error-code", apiErr.Errors[0].Code)
 }
 
+func TestOrganizationMembershipClientUpdateMetadata(t *testing.T) {
+	t.Parallel()
+	id := "orgmem_123"
+	organizationID := "org_123"
+	userID := "user_123"
+	config := &clerk.ClientConfig{}
+	config.HTTPClient = &http.Client{
+		Transport: &clerktest.RoundTripper{
+			T:      t,
+			In:     json.RawMessage(`{"public_metadata":{"theme":"dark"},"private_metadata":{"seat":1}}`),
+			Out:    json.RawMessage(fmt.Sprintf(`{"id":"%s","public_metadata":{"theme":"dark"},"private_metadata":{"seat":1}}`, id)),
+			Method: http.MethodPatch,
+			Path:   "/v1/organizations/" + organizationID + "/memberships/" + userID + "/metadata",
+		},
+	}
+	client := NewClient(config)
+	publicMetadata := json.RawMessage(`{"theme":"dark"}`)
+	privateMetadata := json.RawMessage(`{"seat":1}`)
+	membership, err := client.UpdateMetadata(context.Background(), &UpdateMetadataParams{
+		OrganizationID:  organizationID,
+		UserID:          userID,
+		PublicMetadata:  &publicMetadata,
+		PrivateMetadata: &privateMetadata,
+	})
+	require.NoError(t, err)
+	require.Equal(t, id, membership.ID)
+	require.JSONEq(t, `{"theme":"dark"}`, string(membership.PublicMetadata))
+	require.JSONEq(t, `{"seat":1}`, string(membership.PrivateMetadata))
+}
+
 func TestOrganizationMembershipClientDelete(t *testing.T) {
 	t.Parallel()
 	id := "orgmem_123"
@@ -232,3 +264,48 @@ func TestOrganizationMembershipClientList(t *testing.T) {
 	require.Equal(t, "string", list.OrganizationMemberships[0].RoleName)
 	require.Equal(t, "string", list.OrganizationMemberships[0].Role)
 }
+
+func TestOrganizationMembershipClientModifyMetadata(t *testing.T) {
+	t.Parallel()
+	clerkAPI := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPatch {
+			require.Equal(t, "/organizations/org_123/memberships/user_123/metadata", r.URL.Path)
+			body, err := io.ReadAll(r.Body)
+			require.NoError(t, err)
+			require.JSONEq(t, `{"public_metadata":{"theme":"dark"}}`, string(body))
+			_, err = w.Write([]byte(`{"id":"orgmem_123","updated_at":2,"public_metadata":{"theme":"dark","seat":1}}`))
+			require.NoError(t, err)
+			return
+		}
+		require.Equal(t, "/organizations/org_123/memberships", r.URL.Path)
+		require.Equal(t, "user_123", r.URL.Query().Get("user_id"))
+		_, err := w.Write([]byte(`{"data":[{"id":"orgmem_123","updated_at":1,"public_metadata":{"seat":1}}],"total_count":1}`))
+		require.NoError(t, err)
+	}))
+	defer clerkAPI.Close()
+	config := &clerk.ClientConfig{}
+	config.HTTPClient = clerkAPI.Client()
+	config.URL = &clerkAPI.URL
+	client := NewClient(config)
+
+	membership, err := client.ModifyMetadata(context.Background(), &ModifyMetadataParams{
+		OrganizationID: "org_123",
+		UserID:         "user_123",
+		Modify: func(membership *clerk.OrganizationMembership) error {
+			membership.PublicMetadata = json.RawMessage(`{"seat":1,"theme":"dark"}`)
+			return nil
+		},
+	})
+	require.NoError(t, err)
+	require.Equal(t, "orgmem_123", membership.ID)
+	require.Equal(t, int64(2), membership.UpdatedAt)
+}
+
+func TestOrganizationMembershipClientModifyMetadata_MissingModify(t *testing.T) {
+	t.Parallel()
+	client := NewClient(&clerk.ClientConfig{})
+	_, err := client.ModifyMetadata(context.Background(), nil)
+	require.Error(t, err)
+	_, err = client.ModifyMetadata(context.Background(), &ModifyMetadataParams{OrganizationID: "org_123", UserID: "user_123"})
+	require.Error(t, err)
+}
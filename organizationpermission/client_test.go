@@ -0,0 +1,131 @@
+package organizationpermission
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/clerk/clerk-sdk-go/v2"
+	"github.com/clerk/clerk-sdk-go/v2/clerktest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOrganizationPermissionClientCreate(t *testing.T) {
+	t.Parallel()
+	id := "perm_123"
+	key := "org:invoices:read"
+	config := &clerk.ClientConfig{}
+	config.HTTPClient = &http.Client{
+		Transport: &clerktest.RoundTripper{
+			T:      t,
+			In:     json.RawMessage(fmt.Sprintf(`{"name":"Read invoices","key":"%s"}`, key)),
+			Out:    json.RawMessage(fmt.Sprintf(`{"id":"%s","object":"permission","key":"%s","type":"user"}`, id, key)),
+			Method: http.MethodPost,
+			Path:   "/v1/organization_permissions",
+		},
+	}
+	client := NewClient(config)
+	permission, err := client.Create(context.Background(), &CreateParams{
+		Name: clerk.String("Read invoices"),
+		Key:  clerk.String(key),
+	})
+	require.NoError(t, err)
+	require.Equal(t, id, permission.ID)
+	require.Equal(t, key, permission.Key)
+	require.Equal(t, "user", permission.Type)
+}
+
+func TestOrganizationPermissionClientGet(t *testing.T) {
+	t.Parallel()
+	id := "perm_123"
+	config := &clerk.ClientConfig{}
+	config.HTTPClient = &http.Client{
+		Transport: &clerktest.RoundTripper{
+			T:      t,
+			Out:    json.RawMessage(fmt.Sprintf(`{"id":"%s","object":"permission"}`, id)),
+			Method: http.MethodGet,
+			Path:   "/v1/organization_permissions/" + id,
+		},
+	}
+	client := NewClient(config)
+	permission, err := client.Get(context.Background(), id)
+	require.NoError(t, err)
+	require.Equal(t, id, permission.ID)
+}
+
+func TestOrganizationPermissionClientUpdate(t *testing.T) {
+	t.Parallel()
+	id := "perm_123"
+	name := "Read all invoices"
+	config := &clerk.ClientConfig{}
+	config.HTTPClient = &http.Client{
+		Transport: &clerktest.RoundTripper{
+			T:      t,
+			In:     json.RawMessage(fmt.Sprintf(`{"name":"%s"}`, name)),
+			Out:    json.RawMessage(fmt.Sprintf(`{"id":"%s","name":"%s"}`, id, name)),
+			Method: http.MethodPatch,
+			Path:   "/v1/organization_permissions/" + id,
+		},
+	}
+	client := NewClient(config)
+	permission, err := client.Update(context.Background(), id, &UpdateParams{
+		Name: clerk.String(name),
+	})
+	require.NoError(t, err)
+	require.Equal(t, id, permission.ID)
+	require.Equal(t, name, permission.Name)
+}
+
+func TestOrganizationPermissionClientDelete(t *testing.T) {
+	t.Parallel()
+	id := "perm_123"
+	config := &clerk.ClientConfig{}
+	config.HTTPClient = &http.Client{
+		Transport: &clerktest.RoundTripper{
+			T:      t,
+			Out:    json.RawMessage(fmt.Sprintf(`{"id":"%s","object":"permission","deleted":true}`, id)),
+			Method: http.MethodDelete,
+			Path:   "/v1/organization_permissions/" + id,
+		},
+	}
+	client := NewClient(config)
+	permission, err := client.Delete(context.Background(), id)
+	require.NoError(t, err)
+	require.Equal(t, id, permission.ID)
+	require.True(t, permission.Deleted)
+}
+
+func TestOrganizationPermissionClientList(t *testing.T) {
+	t.Parallel()
+	config := &clerk.ClientConfig{}
+	config.HTTPClient = &http.Client{
+		Transport: &clerktest.RoundTripper{
+			T: t,
+			Out: json.RawMessage(`{
+"data": [{"id":"perm_123","key":"org:invoices:read"}],
+"total_count": 1
+}`),
+			Method: http.MethodGet,
+			Path:   "/v1/organization_permissions",
+			Query: &url.Values{
+				"limit":  []string{"10"},
+				"offset": []string{"0"},
+				"query":  []string{"invoices"},
+			},
+		},
+	}
+	client := NewClient(config)
+	params := &ListParams{
+		Query: clerk.String("invoices"),
+	}
+	params.Limit = clerk.Int64(10)
+	params.Offset = clerk.Int64(0)
+	list, err := client.List(context.Background(), params)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), list.TotalCount)
+	require.Equal(t, 1, len(list.OrganizationPermissions))
+	require.Equal(t, "perm_123", list.OrganizationPermissions[0].ID)
+}
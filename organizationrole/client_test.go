@@ -0,0 +1,208 @@
+package organizationrole
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/clerk/clerk-sdk-go/v2"
+	"github.com/clerk/clerk-sdk-go/v2/clerktest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOrganizationRoleClientCreate(t *testing.T) {
+	t.Parallel()
+	id := "role_123"
+	key := "org:billing"
+	config := &clerk.ClientConfig{}
+	config.HTTPClient = &http.Client{
+		Transport: &clerktest.RoundTripper{
+			T:      t,
+			In:     json.RawMessage(fmt.Sprintf(`{"name":"Billing","key":"%s","permissions":["org:invoices:read"]}`, key)),
+			Out:    json.RawMessage(fmt.Sprintf(`{"id":"%s","object":"role","key":"%s","permissions":[{"id":"perm_123","key":"org:invoices:read"}]}`, id, key)),
+			Method: http.MethodPost,
+			Path:   "/v1/organization_roles",
+		},
+	}
+	client := NewClient(config)
+	role, err := client.Create(context.Background(), &CreateParams{
+		Name:        clerk.String("Billing"),
+		Key:         clerk.String(key),
+		Permissions: []string{"org:invoices:read"},
+	})
+	require.NoError(t, err)
+	require.Equal(t, id, role.ID)
+	require.Equal(t, key, role.Key)
+	require.Equal(t, 1, len(role.Permissions))
+	require.Equal(t, "org:invoices:read", role.Permissions[0].Key)
+}
+
+func TestOrganizationRoleClientCreate_Error(t *testing.T) {
+	t.Parallel()
+	config := &clerk.ClientConfig{}
+	config.HTTPClient = &http.Client{
+		Transport: &clerktest.RoundTripper{
+			T:      t,
+			Status: http.StatusUnprocessableEntity,
+			Out: json.RawMessage(`{
+  "errors":[{
+		"code":"duplicate_record",
+		"message":"a role with this key already exists"
+	}],
+	"clerk_trace_id":"trace-id"
+}`),
+		},
+	}
+	client := NewClient(config)
+	_, err := client.Create(context.Background(), &CreateParams{})
+	require.Error(t, err)
+	apiErr, ok := err.(*clerk.APIErrorResponse)
+	require.True(t, ok)
+	require.Equal(t, "trace-id", apiErr.TraceID)
+	require.Equal(t, 1, len(apiErr.Errors))
+	require.Equal(t, "duplicate_record", apiErr.Errors[0].Code)
+}
+
+func TestOrganizationRoleClientGet(t *testing.T) {
+	t.Parallel()
+	id := "role_123"
+	config := &clerk.ClientConfig{}
+	config.HTTPClient = &http.Client{
+		Transport: &clerktest.RoundTripper{
+			T:      t,
+			Out:    json.RawMessage(fmt.Sprintf(`{"id":"%s","object":"role","is_creator_eligible":true}`, id)),
+			Method: http.MethodGet,
+			Path:   "/v1/organization_roles/" + id,
+		},
+	}
+	client := NewClient(config)
+	role, err := client.Get(context.Background(), id)
+	require.NoError(t, err)
+	require.Equal(t, id, role.ID)
+	require.True(t, role.IsCreatorEligible)
+}
+
+func TestOrganizationRoleClientUpdate(t *testing.T) {
+	t.Parallel()
+	id := "role_123"
+	description := "Manages billing"
+	config := &clerk.ClientConfig{}
+	config.HTTPClient = &http.Client{
+		Transport: &clerktest.RoundTripper{
+			T:      t,
+			In:     json.RawMessage(fmt.Sprintf(`{"description":"%s"}`, description)),
+			Out:    json.RawMessage(fmt.Sprintf(`{"id":"%s","description":"%s"}`, id, description)),
+			Method: http.MethodPatch,
+			Path:   "/v1/organization_roles/" + id,
+		},
+	}
+	client := NewClient(config)
+	role, err := client.Update(context.Background(), id, &UpdateParams{
+		Description: clerk.String(description),
+	})
+	require.NoError(t, err)
+	require.Equal(t, id, role.ID)
+	require.Equal(t, description, role.Description)
+}
+
+func TestOrganizationRoleClientDelete(t *testing.T) {
+	t.Parallel()
+	id := "role_123"
+	config := &clerk.ClientConfig{}
+	config.HTTPClient = &http.Client{
+		Transport: &clerktest.RoundTripper{
+			T:      t,
+			Out:    json.RawMessage(fmt.Sprintf(`{"id":"%s","object":"role","deleted":true}`, id)),
+			Method: http.MethodDelete,
+			Path:   "/v1/organization_roles/" + id,
+		},
+	}
+	client := NewClient(config)
+	role, err := client.Delete(context.Background(), id)
+	require.NoError(t, err)
+	require.Equal(t, id, role.ID)
+	require.True(t, role.Deleted)
+}
+
+func TestOrganizationRoleClientList(t *testing.T) {
+	t.Parallel()
+	config := &clerk.ClientConfig{}
+	config.HTTPClient = &http.Client{
+		Transport: &clerktest.RoundTripper{
+			T: t,
+			Out: json.RawMessage(`{
+"data": [{"id":"role_123","key":"org:admin"}],
+"total_count": 3
+}`),
+			Method: http.MethodGet,
+			Path:   "/v1/organization_roles",
+			Query: &url.Values{
+				"limit":    []string{"1"},
+				"offset":   []string{"2"},
+				"query":    []string{"admin"},
+				"order_by": []string{"-created_at"},
+			},
+		},
+	}
+	client := NewClient(config)
+	params := &ListParams{
+		Query:   clerk.String("admin"),
+		OrderBy: clerk.String("-created_at"),
+	}
+	params.Limit = clerk.Int64(1)
+	params.Offset = clerk.Int64(2)
+	list, err := client.List(context.Background(), params)
+	require.NoError(t, err)
+	require.Equal(t, int64(3), list.TotalCount)
+	require.Equal(t, 1, len(list.OrganizationRoles))
+	require.Equal(t, "org:admin", list.OrganizationRoles[0].Key)
+}
+
+func TestOrganizationRoleClientAssignPermission(t *testing.T) {
+	t.Parallel()
+	id := "role_123"
+	permissionID := "perm_123"
+	config := &clerk.ClientConfig{}
+	config.HTTPClient = &http.Client{
+		Transport: &clerktest.RoundTripper{
+			T:      t,
+			Out:    json.RawMessage(fmt.Sprintf(`{"id":"%s","permissions":[{"id":"%s"}]}`, id, permissionID)),
+			Method: http.MethodPost,
+			Path:   "/v1/organization_roles/" + id + "/permissions/" + permissionID,
+		},
+	}
+	client := NewClient(config)
+	role, err := client.AssignPermission(context.Background(), &PermissionParams{
+		RoleID:       id,
+		PermissionID: permissionID,
+	})
+	require.NoError(t, err)
+	require.Equal(t, id, role.ID)
+	require.Equal(t, permissionID, role.Permissions[0].ID)
+}
+
+func TestOrganizationRoleClientRemovePermission(t *testing.T) {
+	t.Parallel()
+	id := "role_123"
+	permissionID := "perm_123"
+	config := &clerk.ClientConfig{}
+	config.HTTPClient = &http.Client{
+		Transport: &clerktest.RoundTripper{
+			T:      t,
+			Out:    json.RawMessage(fmt.Sprintf(`{"id":"%s","permissions":[]}`, id)),
+			Method: http.MethodDelete,
+			Path:   "/v1/organization_roles/" + id + "/permissions/" + permissionID,
+		},
+	}
+	client := NewClient(config)
+	role, err := client.RemovePermission(context.Background(), &PermissionParams{
+		RoleID:       id,
+		PermissionID: permissionID,
+	})
+	require.NoError(t, err)
+	require.Equal(t, id, role.ID)
+	require.Equal(t, 0, len(role.Permissions))
+}
@@ -36,3 +36,14 @@ type SessionList struct {
 	Sessions   []*Session `json:"data"`
 	TotalCount int64      `json:"total_count"`
 }
+
+// SessionToken is a JSON web token that was created for a session,
+// optionally from a JWT template.
+type SessionToken struct {
+	APIResource
+	Object string `json:"object"`
+	JWT    string `json:"jwt"`
+	// ExpiresAt is the Unix timestamp of the token's exp claim. It is
+	// not part of the API response.
+	ExpiresAt int64 `json:"-"`
+}
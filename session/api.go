@@ -23,6 +23,14 @@ func Revoke(ctx context.Context, params *RevokeParams) (*clerk.Session, error) {
 	return getClient().Revoke(ctx, params)
 }
 
+// CreateToken creates a token for the session from the JWT template
+// with the provided name. If templateName is empty, a session token
+// with the default claims is created.
+// The token's expiration is read from its exp claim.
+func CreateToken(ctx context.Context, sessionID, templateName string) (*clerk.SessionToken, error) {
+	return getClient().CreateToken(ctx, sessionID, templateName)
+}
+
 // Verify verifies the session.
 //
 // Deprecated: The operation is deprecated and will be removed in future versions.
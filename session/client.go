@@ -8,6 +8,7 @@ import (
 	"net/url"
 
 	"github.com/clerk/clerk-sdk-go/v2"
+	"github.com/clerk/clerk-sdk-go/v2/jwt"
 )
 
 //go:generate go run ../cmd/gen/main.go
@@ -85,6 +86,37 @@ func (c *Client) Revoke(ctx context.Context, params *RevokeParams) (*clerk.Sessi
 	return session, err
 }
 
+// CreateToken creates a token for the session from the JWT template
+// with the provided name. If templateName is empty, a session token
+// with the default claims is created.
+// The token's expiration is read from its exp claim.
+func (c *Client) CreateToken(ctx context.Context, sessionID, templateName string) (*clerk.SessionToken, error) {
+	path, err := clerk.JoinPath(path, sessionID, "/tokens")
+	if err != nil {
+		return nil, err
+	}
+	if templateName != "" {
+		path, err = clerk.JoinPath(path, templateName)
+		if err != nil {
+			return nil, err
+		}
+	}
+	req := clerk.NewAPIRequest(http.MethodPost, path)
+	token := &clerk.SessionToken{}
+	err = c.Backend.Call(ctx, req, token)
+	if err != nil {
+		return token, err
+	}
+	claims, err := jwt.Decode(ctx, &jwt.DecodeParams{Token: token.JWT})
+	if err != nil {
+		return nil, fmt.Errorf("decode session token: %w", err)
+	}
+	if claims.Expiry != nil {
+		token.ExpiresAt = *claims.Expiry
+	}
+	return token, nil
+}
+
 type VerifyParams struct {
 	ID    string  `json:"-"`
 	Token *string `json:"token,omitempty"`
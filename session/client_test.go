@@ -7,6 +7,7 @@ import (
 	"net/http"
 	"net/url"
 	"testing"
+	"time"
 
 	"github.com/clerk/clerk-sdk-go/v2"
 	"github.com/clerk/clerk-sdk-go/v2/clerktest"
@@ -109,3 +110,62 @@ func TestSessionClientVerify(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, id, session.ID)
 }
+
+func TestSessionClientCreateToken(t *testing.T) {
+	t.Parallel()
+	sessionID := "sess_123"
+	templateName := "downstream"
+	expiresAt := time.Now().Add(time.Minute).Unix()
+	token, _ := clerktest.GenerateJWT(t, map[string]any{"sub": "user_123", "exp": expiresAt}, "kid")
+	config := &clerk.ClientConfig{}
+	config.HTTPClient = &http.Client{
+		Transport: &clerktest.RoundTripper{
+			T:      t,
+			Out:    json.RawMessage(fmt.Sprintf(`{"object":"token","jwt":"%s"}`, token)),
+			Method: http.MethodPost,
+			Path:   "/v1/sessions/" + sessionID + "/tokens/" + templateName,
+		},
+	}
+	client := NewClient(config)
+	sessionToken, err := client.CreateToken(context.Background(), sessionID, templateName)
+	require.NoError(t, err)
+	require.Equal(t, token, sessionToken.JWT)
+	require.Equal(t, expiresAt, sessionToken.ExpiresAt)
+}
+
+func TestSessionClientCreateToken_DefaultTemplate(t *testing.T) {
+	t.Parallel()
+	sessionID := "sess_123"
+	token, _ := clerktest.GenerateJWT(t, map[string]any{"sub": "user_123"}, "kid")
+	config := &clerk.ClientConfig{}
+	config.HTTPClient = &http.Client{
+		Transport: &clerktest.RoundTripper{
+			T:      t,
+			Out:    json.RawMessage(fmt.Sprintf(`{"object":"token","jwt":"%s"}`, token)),
+			Method: http.MethodPost,
+			Path:   "/v1/sessions/" + sessionID + "/tokens",
+		},
+	}
+	client := NewClient(config)
+	sessionToken, err := client.CreateToken(context.Background(), sessionID, "")
+	require.NoError(t, err)
+	require.Equal(t, token, sessionToken.JWT)
+	require.Equal(t, int64(0), sessionToken.ExpiresAt)
+}
+
+func TestSessionClientCreateToken_Error(t *testing.T) {
+	t.Parallel()
+	config := &clerk.ClientConfig{}
+	config.HTTPClient = &http.Client{
+		Transport: &clerktest.RoundTripper{
+			T:      t,
+			Status: http.StatusNotFound,
+			Out:    json.RawMessage(`{"errors":[{"code":"resource_not_found"}],"clerk_trace_id":"trace-id"}`),
+		},
+	}
+	client := NewClient(config)
+	_, err := client.CreateToken(context.Background(), "sess_123", "missing")
+	apiErr, ok := err.(*clerk.APIErrorResponse)
+	require.True(t, ok)
+	require.Equal(t, "trace-id", apiErr.TraceID)
+}
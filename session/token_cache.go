@@ -0,0 +1,186 @@
+package session
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/clerk/clerk-sdk-go/v2"
+	"github.com/clerk/clerk-sdk-go/v2/internal/contextutil"
+)
+
+const (
+	// DefaultTokenRefreshBefore is the default duration before their
+	// expiration at which cached tokens are refreshed.
+	DefaultTokenRefreshBefore = 10 * time.Second
+	// DefaultTokenRefreshTimeout is the default maximum duration of
+	// a request that creates a token.
+	DefaultTokenRefreshTimeout = 30 * time.Second
+)
+
+type TokenCacheParams struct {
+	// RefreshBefore is the duration before a token expires at which a
+	// new token is created instead of returning the cached one.
+	// Defaults to DefaultTokenRefreshBefore.
+	RefreshBefore time.Duration
+	// RefreshTimeout is the maximum duration of a request that
+	// creates a token. Requests are shared by all callers for the
+	// same session and template, so they are not canceled when a
+	// caller's context is done. Defaults to DefaultTokenRefreshTimeout.
+	RefreshTimeout time.Duration
+	// Clock can be used to keep track of time and will replace usage
+	// of the [time] package.
+	Clock clerk.Clock
+}
+
+// TokenCache creates session tokens with a Client and reuses them
+// until they are about to expire.
+//
+//	tokens := session.NewTokenCache(session.NewClient(config), nil)
+//	token, err := tokens.CreateToken(ctx, sessionID, "downstream-service")
+//
+// Concurrent requests for the same session and template share a
+// single API request. Tokens without an expiration are not cached.
+type TokenCache struct {
+	client         *Client
+	refreshBefore  time.Duration
+	refreshTimeout time.Duration
+	clock          clerk.Clock
+
+	mu        sync.Mutex
+	tokens    map[tokenCacheKey]*clerk.SessionToken
+	calls     map[tokenCacheKey]*tokenCall
+	lastPurge time.Time
+}
+
+type tokenCacheKey struct {
+	sessionID    string
+	templateName string
+}
+
+// A CreateToken request that is in progress.
+type tokenCall struct {
+	done  chan struct{}
+	token *clerk.SessionToken
+	err   error
+	// Set if the session was invalidated while the request was in
+	// progress, so that the token is not cached. Guarded by the
+	// TokenCache lock.
+	invalidated bool
+}
+
+// NewTokenCache returns a TokenCache which creates tokens with the
+// provided client.
+func NewTokenCache(client *Client, params *TokenCacheParams) *TokenCache {
+	if params == nil {
+		params = &TokenCacheParams{}
+	}
+	cache := &TokenCache{
+		client:         client,
+		refreshBefore:  params.RefreshBefore,
+		refreshTimeout: params.RefreshTimeout,
+		clock:          params.Clock,
+		tokens:         map[tokenCacheKey]*clerk.SessionToken{},
+		calls:          map[tokenCacheKey]*tokenCall{},
+	}
+	if cache.refreshBefore == 0 {
+		cache.refreshBefore = DefaultTokenRefreshBefore
+	}
+	if cache.refreshTimeout <= 0 {
+		cache.refreshTimeout = DefaultTokenRefreshTimeout
+	}
+	if cache.clock == nil {
+		cache.clock = clerk.NewClock()
+	}
+	return cache
+}
+
+// CreateToken returns a cached token for the session and JWT
+// template, or creates a new one if there's no cached token or the
+// cached token expires within the RefreshBefore duration.
+func (c *TokenCache) CreateToken(ctx context.Context, sessionID, templateName string) (*clerk.SessionToken, error) {
+	key := tokenCacheKey{sessionID: sessionID, templateName: templateName}
+	c.mu.Lock()
+	now := c.clock.Now()
+	if token, ok := c.tokens[key]; ok && c.isFresh(token, now) {
+		c.mu.Unlock()
+		return token, nil
+	}
+	call, ok := c.calls[key]
+	if !ok {
+		call = &tokenCall{done: make(chan struct{})}
+		c.calls[key] = call
+		go c.create(ctx, key, call)
+	}
+	c.mu.Unlock()
+
+	select {
+	case <-call.done:
+		return call.token, call.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// Creates the token with the values of the caller's context, but
+// without its cancellation, so that the request is not canceled if
+// the caller that started it gives up while others are waiting for
+// the result. The request is limited by the refresh timeout instead.
+func (c *TokenCache) create(ctx context.Context, key tokenCacheKey, call *tokenCall) {
+	ctx, cancel := context.WithTimeout(contextutil.WithoutCancel(ctx), c.refreshTimeout)
+	defer cancel()
+	call.token, call.err = c.client.CreateToken(ctx, key.sessionID, key.templateName)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.calls[key] == call {
+		delete(c.calls, key)
+	}
+	now := c.clock.Now()
+	c.purge(now)
+	if call.err == nil && !call.invalidated && c.isFresh(call.token, now) {
+		c.tokens[key] = call.token
+	}
+	close(call.done)
+}
+
+// Invalidate removes all cached tokens for the session, for example
+// when the session is revoked. Tokens that are being created for the
+// session are not cached, and later calls to CreateToken start a new
+// request.
+func (c *TokenCache) Invalidate(sessionID string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for key := range c.tokens {
+		if key.sessionID == sessionID {
+			delete(c.tokens, key)
+		}
+	}
+	for key, call := range c.calls {
+		if key.sessionID == sessionID {
+			call.invalidated = true
+			delete(c.calls, key)
+		}
+	}
+}
+
+func (c *TokenCache) isFresh(token *clerk.SessionToken, now time.Time) bool {
+	if token.ExpiresAt == 0 {
+		return false
+	}
+	return time.Unix(token.ExpiresAt, 0).Sub(now) > c.refreshBefore
+}
+
+// Removes tokens that need to be refreshed, at most once per
+// minute. Must be called with the lock held.
+func (c *TokenCache) purge(now time.Time) {
+	if now.Sub(c.lastPurge) < time.Minute {
+		return
+	}
+	for key, token := range c.tokens {
+		if !c.isFresh(token, now) {
+			delete(c.tokens, key)
+		}
+	}
+	c.lastPurge = now
+}
@@ -0,0 +1,188 @@
+package session
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/clerk/clerk-sdk-go/v2"
+	"github.com/clerk/clerk-sdk-go/v2/clerktest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTokenCache(t *testing.T) {
+	t.Parallel()
+	clock := clerktest.NewClockAt(time.Now())
+	var requests int32
+	clerkAPI := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&requests, 1)
+		expiresAt := clock.Now().Add(time.Minute).Unix()
+		token, _ := clerktest.GenerateJWT(t, map[string]any{"exp": expiresAt, "jti": fmt.Sprint(n)}, "kid")
+		_, _ = w.Write([]byte(fmt.Sprintf(`{"object":"token","jwt":"%s"}`, token)))
+	}))
+	defer clerkAPI.Close()
+
+	config := &clerk.ClientConfig{}
+	config.HTTPClient = clerkAPI.Client()
+	config.URL = &clerkAPI.URL
+	cache := NewTokenCache(NewClient(config), &TokenCacheParams{
+		RefreshBefore: 10 * time.Second,
+		Clock:         clock,
+	})
+	ctx := context.Background()
+
+	token, err := cache.CreateToken(ctx, "sess_123", "template")
+	require.NoError(t, err)
+	cached, err := cache.CreateToken(ctx, "sess_123", "template")
+	require.NoError(t, err)
+	require.Equal(t, token.JWT, cached.JWT)
+	require.Equal(t, int32(1), atomic.LoadInt32(&requests))
+
+	// Tokens are cached per session and template.
+	_, err = cache.CreateToken(ctx, "sess_123", "other")
+	require.NoError(t, err)
+	_, err = cache.CreateToken(ctx, "sess_456", "template")
+	require.NoError(t, err)
+	require.Equal(t, int32(3), atomic.LoadInt32(&requests))
+
+	// The token is refreshed before it expires.
+	clock.Advance(45 * time.Second)
+	cached, err = cache.CreateToken(ctx, "sess_123", "template")
+	require.NoError(t, err)
+	require.Equal(t, token.JWT, cached.JWT)
+	clock.Advance(10 * time.Second)
+	refreshed, err := cache.CreateToken(ctx, "sess_123", "template")
+	require.NoError(t, err)
+	require.NotEqual(t, token.JWT, refreshed.JWT)
+	require.Equal(t, int32(4), atomic.LoadInt32(&requests))
+
+	// Invalidated tokens are created again.
+	cache.Invalidate("sess_123")
+	_, err = cache.CreateToken(ctx, "sess_123", "template")
+	require.NoError(t, err)
+	require.Equal(t, int32(5), atomic.LoadInt32(&requests))
+}
+
+func TestTokenCache_ConcurrentRequests(t *testing.T) {
+	t.Parallel()
+	var requests int32
+	release := make(chan struct{})
+	clerkAPI := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		<-release
+		token, _ := clerktest.GenerateJWT(t, map[string]any{"exp": time.Now().Add(time.Minute).Unix()}, "kid")
+		_, _ = w.Write([]byte(fmt.Sprintf(`{"object":"token","jwt":"%s"}`, token)))
+	}))
+	defer clerkAPI.Close()
+
+	config := &clerk.ClientConfig{}
+	config.HTTPClient = clerkAPI.Client()
+	config.URL = &clerkAPI.URL
+	cache := NewTokenCache(NewClient(config), nil)
+
+	var wg sync.WaitGroup
+	tokens := make([]string, 5)
+	for i := range tokens {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			token, err := cache.CreateToken(context.Background(), "sess_123", "template")
+			require.NoError(t, err)
+			tokens[i] = token.JWT
+		}(i)
+	}
+	require.Eventually(t, func() bool {
+		return atomic.LoadInt32(&requests) == 1
+	}, time.Second, time.Millisecond)
+	close(release)
+	wg.Wait()
+	require.Equal(t, int32(1), atomic.LoadInt32(&requests))
+	for _, token := range tokens {
+		require.Equal(t, tokens[0], token)
+	}
+}
+
+func TestTokenCache_Error(t *testing.T) {
+	t.Parallel()
+	var requests int32
+	clerkAPI := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"errors":[{"code":"resource_not_found"}]}`))
+	}))
+	defer clerkAPI.Close()
+
+	config := &clerk.ClientConfig{}
+	config.HTTPClient = clerkAPI.Client()
+	config.URL = &clerkAPI.URL
+	cache := NewTokenCache(NewClient(config), nil)
+
+	// Errors are not cached.
+	for i := 0; i < 2; i++ {
+		_, err := cache.CreateToken(context.Background(), "sess_123", "template")
+		_, ok := err.(*clerk.APIErrorResponse)
+		require.True(t, ok)
+	}
+	require.Equal(t, int32(2), atomic.LoadInt32(&requests))
+}
+
+func TestTokenCache_RefreshTimeout(t *testing.T) {
+	t.Parallel()
+	release := make(chan struct{})
+	clerkAPI := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer clerkAPI.Close()
+	defer close(release)
+
+	config := &clerk.ClientConfig{}
+	config.HTTPClient = clerkAPI.Client()
+	config.URL = &clerkAPI.URL
+	cache := NewTokenCache(NewClient(config), &TokenCacheParams{RefreshTimeout: 10 * time.Millisecond})
+
+	_, err := cache.CreateToken(context.Background(), "sess_123", "template")
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestTokenCache_InvalidateDuringRequest(t *testing.T) {
+	t.Parallel()
+	var requests int32
+	started := make(chan struct{}, 2)
+	release := make(chan struct{})
+	clerkAPI := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&requests, 1)
+		started <- struct{}{}
+		<-release
+		token, _ := clerktest.GenerateJWT(t, map[string]any{"exp": time.Now().Add(time.Minute).Unix(), "jti": fmt.Sprint(n)}, "kid")
+		_, _ = w.Write([]byte(fmt.Sprintf(`{"object":"token","jwt":"%s"}`, token)))
+	}))
+	defer clerkAPI.Close()
+
+	config := &clerk.ClientConfig{}
+	config.HTTPClient = clerkAPI.Client()
+	config.URL = &clerkAPI.URL
+	cache := NewTokenCache(NewClient(config), nil)
+
+	stale := make(chan *clerk.SessionToken)
+	go func() {
+		token, err := cache.CreateToken(context.Background(), "sess_123", "template")
+		require.NoError(t, err)
+		stale <- token
+	}()
+	<-started
+	cache.Invalidate("sess_123")
+	close(release)
+	staleToken := <-stale
+
+	// The token that was requested before the invalidation is not
+	// cached.
+	token, err := cache.CreateToken(context.Background(), "sess_123", "template")
+	require.NoError(t, err)
+	require.NotEqual(t, staleToken.JWT, token.JWT)
+	require.Equal(t, int32(2), atomic.LoadInt32(&requests))
+}
@@ -0,0 +1,39 @@
+package clerk
+
+import "encoding/json"
+
+type SignUp struct {
+	APIResource
+	Object           string                         `json:"object"`
+	ID               string                         `json:"id"`
+	Status           string                         `json:"status"`
+	RequiredFields   []string                       `json:"required_fields"`
+	OptionalFields   []string                       `json:"optional_fields"`
+	MissingFields    []string                       `json:"missing_fields"`
+	UnverifiedFields []string                       `json:"unverified_fields"`
+	Verifications    map[string]*SignUpVerification `json:"verifications"`
+	Username         *string                        `json:"username"`
+	EmailAddress     *string                        `json:"email_address"`
+	PhoneNumber      *string                        `json:"phone_number"`
+	Web3Wallet       *string                        `json:"web3_wallet"`
+	PasswordEnabled  bool                           `json:"password_enabled"`
+	FirstName        *string                        `json:"first_name"`
+	LastName         *string                        `json:"last_name"`
+	UnsafeMetadata   json.RawMessage                `json:"unsafe_metadata,omitempty"`
+	PublicMetadata   json.RawMessage                `json:"public_metadata,omitempty"`
+	CustomAction     bool                           `json:"custom_action"`
+	ExternalID       *string                        `json:"external_id"`
+	CreatedSessionID *string                        `json:"created_session_id"`
+	CreatedUserID    *string                        `json:"created_user_id"`
+	AbandonAt        int64                          `json:"abandon_at"`
+	LegalAcceptedAt  *int64                         `json:"legal_accepted_at"`
+}
+
+type SignUpVerification struct {
+	Status              string   `json:"status"`
+	Strategy            string   `json:"strategy"`
+	Attempts            *int64   `json:"attempts"`
+	ExpireAt            *int64   `json:"expire_at"`
+	NextAction          string   `json:"next_action,omitempty"`
+	SupportedStrategies []string `json:"supported_strategies,omitempty"`
+}
@@ -0,0 +1,58 @@
+// Package signup provides the Sign-Ups API.
+package signup
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/clerk/clerk-sdk-go/v2"
+)
+
+//go:generate go run ../cmd/gen/main.go
+
+const path = "/sign_ups"
+
+// Client is used to invoke the Sign-Ups API.
+type Client struct {
+	Backend clerk.Backend
+}
+
+func NewClient(config *clerk.ClientConfig) *Client {
+	return &Client{
+		Backend: clerk.NewBackend(&config.BackendConfig),
+	}
+}
+
+// Get retrieves details for a sign-up attempt.
+func (c *Client) Get(ctx context.Context, id string) (*clerk.SignUp, error) {
+	path, err := clerk.JoinPath(path, id)
+	if err != nil {
+		return nil, err
+	}
+	req := clerk.NewAPIRequest(http.MethodGet, path)
+	signUp := &clerk.SignUp{}
+	err = c.Backend.Call(ctx, req, signUp)
+	return signUp, err
+}
+
+type UpdateParams struct {
+	clerk.APIParams
+	ExternalID *string `json:"external_id,omitempty"`
+	// CustomAction marks the sign-up as requiring a custom action
+	// before it can be completed. Set it to false to let the sign-up
+	// complete.
+	CustomAction *bool `json:"custom_action,omitempty"`
+}
+
+// Update updates a sign-up attempt.
+func (c *Client) Update(ctx context.Context, id string, params *UpdateParams) (*clerk.SignUp, error) {
+	path, err := clerk.JoinPath(path, id)
+	if err != nil {
+		return nil, err
+	}
+	req := clerk.NewAPIRequest(http.MethodPatch, path)
+	req.SetParams(params)
+	signUp := &clerk.SignUp{}
+	err = c.Backend.Call(ctx, req, signUp)
+	return signUp, err
+}
@@ -0,0 +1,85 @@
+package signup
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/clerk/clerk-sdk-go/v2"
+	"github.com/clerk/clerk-sdk-go/v2/clerktest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSignUpClientGet(t *testing.T) {
+	t.Parallel()
+	id := "sua_123"
+	status := "missing_requirements"
+	config := &clerk.ClientConfig{}
+	config.HTTPClient = &http.Client{
+		Transport: &clerktest.RoundTripper{
+			T:      t,
+			Out:    json.RawMessage(fmt.Sprintf(`{"id":"%s","status":"%s","missing_fields":["password"],"verifications":{"email_address":{"status":"verified","strategy":"email_code"}}}`, id, status)),
+			Method: http.MethodGet,
+			Path:   "/v1/sign_ups/" + id,
+		},
+	}
+	client := NewClient(config)
+	signUp, err := client.Get(context.Background(), id)
+	require.NoError(t, err)
+	require.Equal(t, id, signUp.ID)
+	require.Equal(t, status, signUp.Status)
+	require.Equal(t, []string{"password"}, signUp.MissingFields)
+	require.Equal(t, "verified", signUp.Verifications["email_address"].Status)
+}
+
+func TestSignUpClientUpdate(t *testing.T) {
+	t.Parallel()
+	id := "sua_123"
+	externalID := "ext_123"
+	config := &clerk.ClientConfig{}
+	config.HTTPClient = &http.Client{
+		Transport: &clerktest.RoundTripper{
+			T:      t,
+			In:     json.RawMessage(fmt.Sprintf(`{"external_id":"%s","custom_action":false}`, externalID)),
+			Out:    json.RawMessage(fmt.Sprintf(`{"id":"%s","external_id":"%s","custom_action":false}`, id, externalID)),
+			Method: http.MethodPatch,
+			Path:   "/v1/sign_ups/" + id,
+		},
+	}
+	client := NewClient(config)
+	signUp, err := client.Update(context.Background(), id, &UpdateParams{
+		ExternalID:   clerk.String(externalID),
+		CustomAction: clerk.Bool(false),
+	})
+	require.NoError(t, err)
+	require.Equal(t, id, signUp.ID)
+	require.Equal(t, externalID, *signUp.ExternalID)
+	require.False(t, signUp.CustomAction)
+}
+
+func TestSignUpClientUpdate_Error(t *testing.T) {
+	t.Parallel()
+	config := &clerk.ClientConfig{}
+	config.HTTPClient = &http.Client{
+		Transport: &clerktest.RoundTripper{
+			T:      t,
+			Status: http.StatusBadRequest,
+			Out: json.RawMessage(`{
+  "errors":[{
+		"code":"update-error-code"
+	}],
+	"clerk_trace_id":"update-trace-id"
+}`),
+		},
+	}
+	client := NewClient(config)
+	_, err := client.Update(context.Background(), "sua_123", &UpdateParams{})
+	require.Error(t, err)
+	apiErr, ok := err.(*clerk.APIErrorResponse)
+	require.True(t, ok)
+	require.Equal(t, "update-trace-id", apiErr.TraceID)
+	require.Equal(t, 1, len(apiErr.Errors))
+	require.Equal(t, "update-error-code", apiErr.Errors[0].Code)
+}
@@ -0,0 +1,222 @@
+// Package templatesync keeps email and SMS templates as files, so
+// that changes can be code reviewed, and syncs them with a Clerk
+// instance.
+//
+// Templates are stored in a directory with one subdirectory per
+// template type:
+//
+//	templates/
+//	  email/
+//	    verification_code.html         # body
+//	    verification_code.markup.html  # markup, optional
+//	  sms/
+//	    verification_code.txt          # body
+//
+// Template files can start with YAML front matter, which holds the
+// other template fields. Fields that are omitted are not managed.
+//
+//	---
+//	subject: "{{otp_code}} is your verification code"
+//	from_email_name: support
+//	---
+//	<p>Your code is {{otp_code}}.</p>
+package templatesync
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/clerk/clerk-sdk-go/v2"
+	"gopkg.in/yaml.v3"
+)
+
+// Template is a template that is stored as files.
+type Template struct {
+	TemplateType clerk.TemplateType `yaml:"-"`
+	Slug         string             `yaml:"-"`
+	// Path is the path of the body file.
+	Path string `yaml:"-"`
+	Body string `yaml:"-"`
+	// Markup is nil if there's no markup file.
+	Markup           *string `yaml:"-"`
+	Name             *string `yaml:"name,omitempty"`
+	Subject          *string `yaml:"subject,omitempty"`
+	FromEmailName    *string `yaml:"from_email_name,omitempty"`
+	ReplyToEmailName *string `yaml:"reply_to_email_name,omitempty"`
+	DeliveredByClerk *bool   `yaml:"delivered_by_clerk,omitempty"`
+}
+
+const (
+	frontMatterDelimiter = "---\n"
+	markupSuffix         = ".markup"
+)
+
+// File extensions of the body files per template type.
+var extensions = map[clerk.TemplateType]string{
+	clerk.TemplateTypeEmail: ".html",
+	clerk.TemplateTypeSMS:   ".txt",
+}
+
+// Load reads all templates from the directory. Missing template type
+// subdirectories are skipped.
+func Load(dir string) ([]*Template, error) {
+	var templates []*Template
+	for _, templateType := range []clerk.TemplateType{clerk.TemplateTypeEmail, clerk.TemplateTypeSMS} {
+		typeDir := filepath.Join(dir, string(templateType))
+		entries, err := os.ReadDir(typeDir)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		ext := extensions[templateType]
+		for _, entry := range entries {
+			name := entry.Name()
+			if entry.IsDir() || filepath.Ext(name) != ext {
+				continue
+			}
+			slug := strings.TrimSuffix(name, ext)
+			if strings.HasSuffix(slug, markupSuffix) {
+				continue
+			}
+			template, err := loadTemplate(typeDir, templateType, slug)
+			if err != nil {
+				return nil, err
+			}
+			templates = append(templates, template)
+		}
+	}
+	return templates, nil
+}
+
+func loadTemplate(dir string, templateType clerk.TemplateType, slug string) (*Template, error) {
+	path := filepath.Join(dir, slug+extensions[templateType])
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	template := &Template{}
+	body, err := parseFrontMatter(data, template)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	template.TemplateType = templateType
+	template.Slug = slug
+	template.Path = path
+	template.Body = body
+
+	markup, err := os.ReadFile(filepath.Join(dir, slug+markupSuffix+extensions[templateType]))
+	if err == nil {
+		template.Markup = clerk.String(string(markup))
+	} else if !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+	return template, nil
+}
+
+// Decodes the front matter into the template and returns the rest of
+// the data.
+func parseFrontMatter(data []byte, template *Template) (string, error) {
+	data = bytes.ReplaceAll(data, []byte("\r\n"), []byte("\n"))
+	if !bytes.HasPrefix(data, []byte(frontMatterDelimiter)) {
+		return string(data), nil
+	}
+	rest := data[len(frontMatterDelimiter):]
+	if bytes.HasPrefix(rest, []byte(frontMatterDelimiter)) {
+		return string(rest[len(frontMatterDelimiter):]), nil
+	}
+	frontMatter, body, found := bytes.Cut(rest, []byte("\n"+frontMatterDelimiter))
+	if !found {
+		// The closing delimiter can be the last line of the file.
+		if !bytes.HasSuffix(rest, []byte("\n---")) {
+			return "", errors.New("front matter is not terminated")
+		}
+		frontMatter, body = rest[:len(rest)-len("\n---")], nil
+	}
+	decoder := yaml.NewDecoder(bytes.NewReader(frontMatter))
+	decoder.KnownFields(true)
+	err := decoder.Decode(template)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return "", fmt.Errorf("invalid front matter: %w", err)
+	}
+	return string(body), nil
+}
+
+// Write stores the templates in the directory, replacing any existing
+// files of the same templates.
+func Write(dir string, templates []*Template) error {
+	sorted := append([]*Template{}, templates...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Slug < sorted[j].Slug })
+	for _, template := range sorted {
+		ext, ok := extensions[template.TemplateType]
+		if !ok {
+			return fmt.Errorf("template %s: unsupported template type %q", template.Slug, template.TemplateType)
+		}
+		typeDir := filepath.Join(dir, string(template.TemplateType))
+		err := os.MkdirAll(typeDir, 0o755)
+		if err != nil {
+			return err
+		}
+
+		var data bytes.Buffer
+		frontMatter, err := yaml.Marshal(template)
+		if err != nil {
+			return err
+		}
+		if !bytes.Equal(bytes.TrimSpace(frontMatter), []byte("{}")) {
+			data.WriteString(frontMatterDelimiter)
+			data.Write(frontMatter)
+			data.WriteString(frontMatterDelimiter)
+		}
+		data.WriteString(template.Body)
+		err = os.WriteFile(filepath.Join(typeDir, template.Slug+ext), data.Bytes(), 0o644)
+		if err != nil {
+			return err
+		}
+
+		markupPath := filepath.Join(typeDir, template.Slug+markupSuffix+ext)
+		if template.Markup != nil {
+			err = os.WriteFile(markupPath, []byte(*template.Markup), 0o644)
+		} else {
+			err = os.Remove(markupPath)
+			if errors.Is(err, fs.ErrNotExist) {
+				err = nil
+			}
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// FromClerkTemplate converts a Clerk template to a Template that can
+// be stored as files. Empty fields are omitted.
+func FromClerkTemplate(t *clerk.Template) *Template {
+	template := &Template{
+		TemplateType:     t.TemplateType,
+		Slug:             t.Slug,
+		Body:             t.Body,
+		FromEmailName:    t.FromEmailName,
+		ReplyToEmailName: t.ReplyToEmailName,
+		DeliveredByClerk: clerk.Bool(t.DeliveredByClerk),
+	}
+	if t.Name != "" {
+		template.Name = clerk.String(t.Name)
+	}
+	if t.Subject != "" {
+		template.Subject = clerk.String(t.Subject)
+	}
+	if t.Markup != "" {
+		template.Markup = clerk.String(t.Markup)
+	}
+	return template
+}
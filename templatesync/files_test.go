@@ -0,0 +1,98 @@
+package templatesync
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/clerk/clerk-sdk-go/v2"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteLoad(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	templates := []*Template{
+		{
+			TemplateType:  clerk.TemplateTypeEmail,
+			Slug:          "verification_code",
+			Body:          "<p>{{otp_code}}</p>\n",
+			Markup:        clerk.String("<mjml></mjml>"),
+			Subject:       clerk.String("{{otp_code}} is your code"),
+			FromEmailName: clerk.String("support"),
+		},
+		{
+			TemplateType: clerk.TemplateTypeSMS,
+			Slug:         "verification_code",
+			Body:         "Your code is {{otp_code}}",
+		},
+	}
+	err := Write(dir, templates)
+	require.NoError(t, err)
+
+	data, err := os.ReadFile(filepath.Join(dir, "email", "verification_code.html"))
+	require.NoError(t, err)
+	require.Equal(t, "---\nsubject: '{{otp_code}} is your code'\nfrom_email_name: support\n---\n<p>{{otp_code}}</p>\n", string(data))
+	data, err = os.ReadFile(filepath.Join(dir, "sms", "verification_code.txt"))
+	require.NoError(t, err)
+	require.Equal(t, "Your code is {{otp_code}}", string(data))
+
+	loaded, err := Load(dir)
+	require.NoError(t, err)
+	require.Len(t, loaded, 2)
+	templates[0].Path = filepath.Join(dir, "email", "verification_code.html")
+	templates[1].Path = filepath.Join(dir, "sms", "verification_code.txt")
+	require.Equal(t, templates, loaded)
+
+	// Removing the markup removes the markup file.
+	templates[0].Markup = nil
+	err = Write(dir, templates)
+	require.NoError(t, err)
+	_, err = os.Stat(filepath.Join(dir, "email", "verification_code.markup.html"))
+	require.ErrorIs(t, err, os.ErrNotExist)
+}
+
+func TestLoad_FrontMatter(t *testing.T) {
+	t.Parallel()
+	for _, tc := range []struct {
+		name    string
+		data    string
+		body    string
+		subject *string
+		err     string
+	}{
+		{name: "none", data: "body", body: "body"},
+		{name: "empty", data: "---\n---\nbody", body: "body"},
+		{name: "fields", data: "---\r\nsubject: Hi\r\n---\r\nbody\r\n", body: "body\n", subject: clerk.String("Hi")},
+		{name: "no body", data: "---\nsubject: Hi\n---", body: "", subject: clerk.String("Hi")},
+		{name: "unterminated", data: "---\nsubject: Hi\nbody", err: "front matter is not terminated"},
+		{name: "unknown field", data: "---\nsubjet: Hi\n---\nbody", err: "invalid front matter"},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			dir := t.TempDir()
+			require.NoError(t, os.Mkdir(filepath.Join(dir, "email"), 0o755))
+			require.NoError(t, os.WriteFile(filepath.Join(dir, "email", "welcome.html"), []byte(tc.data), 0o644))
+
+			templates, err := Load(dir)
+			if tc.err != "" {
+				require.ErrorContains(t, err, tc.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Len(t, templates, 1)
+			require.Equal(t, "welcome", templates[0].Slug)
+			require.Equal(t, tc.body, templates[0].Body)
+			require.Equal(t, tc.subject, templates[0].Subject)
+			require.Nil(t, templates[0].Markup)
+		})
+	}
+}
+
+func TestLoad_MissingDirectory(t *testing.T) {
+	t.Parallel()
+	templates, err := Load(t.TempDir())
+	require.NoError(t, err)
+	require.Empty(t, templates)
+}
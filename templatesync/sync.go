@@ -0,0 +1,247 @@
+package templatesync
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/clerk/clerk-sdk-go/v2"
+	"github.com/clerk/clerk-sdk-go/v2/template"
+)
+
+// Syncer compares templates that are stored as files with the
+// templates of a Clerk instance and pushes the changes.
+//
+//	templates, err := templatesync.Load("templates")
+//	syncer := templatesync.NewSyncer(clerk.NewBackend(&clerk.BackendConfig{}))
+//	changes, err := syncer.Diff(ctx, templates)
+//	err = syncer.Preview(ctx, changes)
+//	err = syncer.Push(ctx, changes)
+type Syncer struct {
+	templates *template.Client
+}
+
+// NewSyncer returns a Syncer which calls the Clerk API with the
+// provided backend.
+func NewSyncer(backend clerk.Backend) *Syncer {
+	return &Syncer{
+		templates: &template.Client{Backend: backend},
+	}
+}
+
+// Change describes a template that differs from the live template.
+type Change struct {
+	Template *Template
+	Live     *clerk.Template
+	// Fields lists the names of the changed fields.
+	Fields []string
+	// Preview holds the rendered template. It's set by
+	// Syncer.Preview.
+	Preview *clerk.TemplatePreview
+
+	params *template.UpdateParams
+}
+
+// ValidationError is returned by Syncer.Diff for templates that
+// don't match a live template or reference invalid variables.
+type ValidationError struct {
+	Problems []Problem
+}
+
+// Problem is a validation error of a single template.
+type Problem struct {
+	// Path is the path of the template file.
+	Path    string
+	Message string
+}
+
+func (e *ValidationError) Error() string {
+	problems := make([]string, len(e.Problems))
+	for i, problem := range e.Problems {
+		problems[i] = fmt.Sprintf("%s: %s", problem.Path, problem.Message)
+	}
+	return fmt.Sprintf("invalid templates:\n%s", strings.Join(problems, "\n"))
+}
+
+// Pull fetches the email and SMS templates of the instance, so that
+// they can be stored with Write.
+func (s *Syncer) Pull(ctx context.Context) ([]*Template, error) {
+	var templates []*Template
+	for _, templateType := range []clerk.TemplateType{clerk.TemplateTypeEmail, clerk.TemplateTypeSMS} {
+		live, err := s.list(ctx, templateType)
+		if err != nil {
+			return nil, err
+		}
+		for _, t := range live {
+			templates = append(templates, FromClerkTemplate(t))
+		}
+	}
+	return templates, nil
+}
+
+// Diff compares the templates with the live templates and returns
+// the templates that changed.
+// The templates are validated against the variables of the live
+// templates first: all required variables must be used and only
+// available variables can be referenced. Validation errors are
+// returned as a *ValidationError.
+func (s *Syncer) Diff(ctx context.Context, templates []*Template) ([]*Change, error) {
+	live := map[clerk.TemplateType]map[string]*clerk.Template{}
+	for _, t := range templates {
+		if _, ok := live[t.TemplateType]; ok {
+			continue
+		}
+		list, err := s.list(ctx, t.TemplateType)
+		if err != nil {
+			return nil, err
+		}
+		live[t.TemplateType] = map[string]*clerk.Template{}
+		for _, liveTemplate := range list {
+			live[t.TemplateType][liveTemplate.Slug] = liveTemplate
+		}
+	}
+
+	validationErr := &ValidationError{}
+	var changes []*Change
+	for _, t := range templates {
+		liveTemplate, ok := live[t.TemplateType][t.Slug]
+		if !ok {
+			validationErr.Problems = append(validationErr.Problems, Problem{
+				Path:    t.Path,
+				Message: fmt.Sprintf("unknown %s template %q", t.TemplateType, t.Slug),
+			})
+			continue
+		}
+		change := diff(t, liveTemplate)
+		texts := []string{stringValue(change.params.Subject), stringValue(change.params.Body), stringValue(change.params.Markup)}
+		for _, message := range validateVariables(texts, liveTemplate.AvailableVariables, liveTemplate.RequiredVariables) {
+			validationErr.Problems = append(validationErr.Problems, Problem{Path: t.Path, Message: message})
+		}
+		if len(change.Fields) > 0 {
+			changes = append(changes, change)
+		}
+	}
+	if len(validationErr.Problems) > 0 {
+		return nil, validationErr
+	}
+	return changes, nil
+}
+
+// Preview renders the changed templates with the Clerk API and sets
+// the Preview of each change.
+func (s *Syncer) Preview(ctx context.Context, changes []*Change) error {
+	for _, change := range changes {
+		preview, err := s.templates.Preview(ctx, &template.PreviewParams{
+			TemplateType:     change.params.TemplateType,
+			Slug:             change.params.Slug,
+			Subject:          change.params.Subject,
+			Body:             change.params.Body,
+			FromEmailName:    change.params.FromEmailName,
+			ReplyToEmailName: change.params.ReplyToEmailName,
+		})
+		if err != nil {
+			return fmt.Errorf("preview %s: %w", change.Template.Path, err)
+		}
+		change.Preview = preview
+	}
+	return nil
+}
+
+// Push updates the changed templates. It stops at the first template
+// that fails to update.
+func (s *Syncer) Push(ctx context.Context, changes []*Change) error {
+	for _, change := range changes {
+		_, err := s.templates.Update(ctx, change.params)
+		if err != nil {
+			return fmt.Errorf("update %s: %w", change.Template.Path, err)
+		}
+	}
+	return nil
+}
+
+// Lists all templates of the type.
+func (s *Syncer) list(ctx context.Context, templateType clerk.TemplateType) ([]*clerk.Template, error) {
+	list, err := s.templates.List(ctx, &template.ListParams{TemplateType: templateType})
+	if err != nil {
+		return nil, fmt.Errorf("list %s templates: %w", templateType, err)
+	}
+	if int64(len(list.Templates)) < list.TotalCount {
+		return nil, fmt.Errorf("list %s templates: received %d of %d templates", templateType, len(list.Templates), list.TotalCount)
+	}
+	return list.Templates, nil
+}
+
+// Compares the template with the live template. The update params of
+// the change hold the local value of managed fields and the live
+// value of the others.
+func diff(t *Template, live *clerk.Template) *Change {
+	change := &Change{
+		Template: t,
+		Live:     live,
+		params: &template.UpdateParams{
+			TemplateType:     t.TemplateType,
+			Slug:             t.Slug,
+			Name:             nonEmpty(live.Name),
+			Subject:          nonEmpty(live.Subject),
+			Markup:           nonEmpty(live.Markup),
+			Body:             clerk.String(t.Body),
+			FromEmailName:    live.FromEmailName,
+			ReplyToEmailName: live.ReplyToEmailName,
+			DeliveredByClerk: clerk.Bool(live.DeliveredByClerk),
+		},
+	}
+	if t.Body != live.Body {
+		change.Fields = append(change.Fields, "body")
+	}
+	if t.Markup != nil {
+		change.params.Markup = t.Markup
+		if *t.Markup != live.Markup {
+			change.Fields = append(change.Fields, "markup")
+		}
+	}
+	if t.Name != nil {
+		change.params.Name = t.Name
+		if *t.Name != live.Name {
+			change.Fields = append(change.Fields, "name")
+		}
+	}
+	if t.Subject != nil {
+		change.params.Subject = t.Subject
+		if *t.Subject != live.Subject {
+			change.Fields = append(change.Fields, "subject")
+		}
+	}
+	if t.FromEmailName != nil {
+		change.params.FromEmailName = t.FromEmailName
+		if *t.FromEmailName != stringValue(live.FromEmailName) {
+			change.Fields = append(change.Fields, "from_email_name")
+		}
+	}
+	if t.ReplyToEmailName != nil {
+		change.params.ReplyToEmailName = t.ReplyToEmailName
+		if *t.ReplyToEmailName != stringValue(live.ReplyToEmailName) {
+			change.Fields = append(change.Fields, "reply_to_email_name")
+		}
+	}
+	if t.DeliveredByClerk != nil {
+		change.params.DeliveredByClerk = t.DeliveredByClerk
+		if *t.DeliveredByClerk != live.DeliveredByClerk {
+			change.Fields = append(change.Fields, "delivered_by_clerk")
+		}
+	}
+	return change
+}
+
+func stringValue(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+func nonEmpty(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return clerk.String(s)
+}
@@ -0,0 +1,213 @@
+package templatesync
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/clerk/clerk-sdk-go/v2"
+	"github.com/stretchr/testify/require"
+)
+
+// Serves the live templates, and records the update and preview
+// requests.
+type templateAPI struct {
+	mu        sync.Mutex
+	templates []*clerk.Template
+	updates   map[string]map[string]any
+	previews  []string
+}
+
+func (api *templateAPI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	api.mu.Lock()
+	defer api.mu.Unlock()
+	segments := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	w.Header().Set("Content-Type", "application/json")
+	switch {
+	case r.Method == http.MethodGet && len(segments) == 2:
+		var templates []*clerk.Template
+		for _, t := range api.templates {
+			if string(t.TemplateType) == segments[1] {
+				templates = append(templates, t)
+			}
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{"data": templates, "total_count": len(templates)})
+	case r.Method == http.MethodPut && len(segments) == 3:
+		params := map[string]any{}
+		_ = json.NewDecoder(r.Body).Decode(&params)
+		if api.updates == nil {
+			api.updates = map[string]map[string]any{}
+		}
+		api.updates[segments[1]+"/"+segments[2]] = params
+		_ = json.NewEncoder(w).Encode(map[string]any{"slug": segments[2], "template_type": segments[1]})
+	case r.Method == http.MethodPost && len(segments) == 4 && segments[3] == "preview":
+		params := map[string]string{}
+		_ = json.NewDecoder(r.Body).Decode(&params)
+		api.previews = append(api.previews, segments[1]+"/"+segments[2])
+		_ = json.NewEncoder(w).Encode(map[string]any{"subject": params["subject"], "body": "rendered " + params["body"]})
+	default:
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"errors":[{"code":"resource_not_found"}]}`))
+	}
+}
+
+func newTestSyncer(t *testing.T, api *templateAPI) *Syncer {
+	t.Helper()
+	server := httptest.NewServer(api)
+	t.Cleanup(server.Close)
+	return NewSyncer(clerk.NewBackend(&clerk.BackendConfig{
+		HTTPClient: server.Client(),
+		URL:        &server.URL,
+	}))
+}
+
+func liveTemplates() []*clerk.Template {
+	return []*clerk.Template{
+		{
+			Object:             "template",
+			TemplateType:       clerk.TemplateTypeEmail,
+			Slug:               "verification_code",
+			Name:               "Verification code",
+			Subject:            "{{otp_code}} is your code",
+			Body:               "<p>{{otp_code}}</p>",
+			FromEmailName:      clerk.String("support"),
+			DeliveredByClerk:   true,
+			AvailableVariables: []string{"app", "otp_code"},
+			RequiredVariables:  []string{"otp_code"},
+		},
+		{
+			Object:             "template",
+			TemplateType:       clerk.TemplateTypeSMS,
+			Slug:               "verification_code",
+			Name:               "Verification code",
+			Body:               "{{otp_code}} is your code",
+			DeliveredByClerk:   true,
+			AvailableVariables: []string{"app", "otp_code"},
+			RequiredVariables:  []string{"otp_code"},
+		},
+	}
+}
+
+func TestSyncer_Pull(t *testing.T) {
+	t.Parallel()
+	syncer := newTestSyncer(t, &templateAPI{templates: liveTemplates()})
+	templates, err := syncer.Pull(context.Background())
+	require.NoError(t, err)
+	require.Len(t, templates, 2)
+	require.Equal(t, clerk.TemplateTypeEmail, templates[0].TemplateType)
+	require.Equal(t, "verification_code", templates[0].Slug)
+	require.Equal(t, "{{otp_code}} is your code", *templates[0].Subject)
+	require.Equal(t, "support", *templates[0].FromEmailName)
+	require.Nil(t, templates[0].Markup)
+	require.Equal(t, clerk.TemplateTypeSMS, templates[1].TemplateType)
+	require.Nil(t, templates[1].Subject)
+}
+
+func TestSyncer_DiffPreviewPush(t *testing.T) {
+	t.Parallel()
+	api := &templateAPI{templates: liveTemplates()}
+	syncer := newTestSyncer(t, api)
+	ctx := context.Background()
+
+	templates := []*Template{
+		{
+			TemplateType: clerk.TemplateTypeEmail,
+			Slug:         "verification_code",
+			Path:         "email/verification_code.html",
+			Body:         "<p>{{app.name}}: {{otp_code}}</p>",
+		},
+		{
+			TemplateType: clerk.TemplateTypeSMS,
+			Slug:         "verification_code",
+			Path:         "sms/verification_code.txt",
+			Body:         "{{otp_code}} is your code",
+		},
+	}
+	changes, err := syncer.Diff(ctx, templates)
+	require.NoError(t, err)
+	require.Len(t, changes, 1)
+	require.Equal(t, templates[0], changes[0].Template)
+	require.Equal(t, []string{"body"}, changes[0].Fields)
+
+	err = syncer.Preview(ctx, changes)
+	require.NoError(t, err)
+	require.Equal(t, []string{"email/verification_code"}, api.previews)
+	require.Equal(t, "{{otp_code}} is your code", changes[0].Preview.Subject)
+	require.Equal(t, "rendered <p>{{app.name}}: {{otp_code}}</p>", changes[0].Preview.Body)
+
+	err = syncer.Push(ctx, changes)
+	require.NoError(t, err)
+	require.Len(t, api.updates, 1)
+	update := api.updates["email/verification_code"]
+	require.Equal(t, "<p>{{app.name}}: {{otp_code}}</p>", update["body"])
+	// Unmanaged fields keep their live values.
+	require.Equal(t, "{{otp_code}} is your code", update["subject"])
+	require.Equal(t, "Verification code", update["name"])
+	require.Equal(t, "support", update["from_email_name"])
+	require.Equal(t, true, update["delivered_by_clerk"])
+	require.NotContains(t, update, "markup")
+}
+
+func TestSyncer_Diff_ManagedFields(t *testing.T) {
+	t.Parallel()
+	syncer := newTestSyncer(t, &templateAPI{templates: liveTemplates()})
+	changes, err := syncer.Diff(context.Background(), []*Template{{
+		TemplateType:     clerk.TemplateTypeEmail,
+		Slug:             "verification_code",
+		Body:             "<p>{{otp_code}}</p>",
+		Subject:          clerk.String("Your code"),
+		FromEmailName:    clerk.String("support"),
+		DeliveredByClerk: clerk.Bool(false),
+	}})
+	require.NoError(t, err)
+	require.Len(t, changes, 1)
+	require.Equal(t, []string{"subject", "delivered_by_clerk"}, changes[0].Fields)
+}
+
+func TestSyncer_Diff_ValidationError(t *testing.T) {
+	t.Parallel()
+	api := &templateAPI{templates: liveTemplates()}
+	syncer := newTestSyncer(t, api)
+	_, err := syncer.Diff(context.Background(), []*Template{
+		{
+			TemplateType: clerk.TemplateTypeEmail,
+			Slug:         "verification_code",
+			Path:         "email/verification_code.html",
+			Body:         "<p>Hi {{user.first_name}}</p>",
+			Subject:      clerk.String("Your code"),
+		},
+		{
+			TemplateType: clerk.TemplateTypeSMS,
+			Slug:         "magic_link",
+			Path:         "sms/magic_link.txt",
+			Body:         "{{magic_link}}",
+		},
+	})
+	var validationErr *ValidationError
+	require.True(t, errors.As(err, &validationErr))
+	require.Equal(t, []Problem{
+		{Path: "email/verification_code.html", Message: `required variable "otp_code" is not used`},
+		{Path: "email/verification_code.html", Message: `unknown variable "user.first_name"`},
+		{Path: "sms/magic_link.txt", Message: `unknown sms template "magic_link"`},
+	}, validationErr.Problems)
+	require.Empty(t, api.updates)
+}
+
+func TestSyncer_Diff_TruncatedList(t *testing.T) {
+	t.Parallel()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"data":[],"total_count":3}`))
+	}))
+	t.Cleanup(server.Close)
+	syncer := NewSyncer(clerk.NewBackend(&clerk.BackendConfig{
+		HTTPClient: server.Client(),
+		URL:        &server.URL,
+	}))
+	_, err := syncer.Diff(context.Background(), []*Template{{TemplateType: clerk.TemplateTypeEmail, Slug: "welcome"}})
+	require.ErrorContains(t, err, "received 0 of 3 templates")
+}
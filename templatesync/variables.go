@@ -0,0 +1,169 @@
+package templatesync
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Matches Handlebars expressions, like {{name}}, {{{name}}},
+// {{#if name}} or {{/if}}.
+var expressionPattern = regexp.MustCompile(`\{\{\{?~?\s*([^{}]*?)\s*~?\}?\}\}`)
+
+// Block helpers and keywords that are not variables.
+var keywords = map[string]bool{
+	"if":     true,
+	"unless": true,
+	"each":   true,
+	"with":   true,
+	"else":   true,
+	"this":   true,
+	"true":   true,
+	"false":  true,
+	"null":   true,
+}
+
+// Variables returns the variables that are referenced by the
+// Handlebars expressions in the text, sorted and without duplicates.
+// Helper names, keywords, literals, partials and data variables like
+// @index are ignored. Variables inside each and with blocks, and
+// inside sections like {{#user}}, are resolved against the block
+// argument, so {{#each items}}{{name}}{{/each}} references items.name.
+func Variables(text string) []string {
+	seen := map[string]bool{}
+	// The context of each open block, as a path prefix.
+	scopes := []string{""}
+	resolve := func(word string) string {
+		word = strings.TrimPrefix(word, "this.")
+		if scope := scopes[len(scopes)-1]; scope != "" {
+			return scope + "." + word
+		}
+		return word
+	}
+	for _, match := range expressionPattern.FindAllStringSubmatch(text, -1) {
+		expression := match[1]
+		if strings.HasPrefix(expression, "/") {
+			if len(scopes) > 1 {
+				scopes = scopes[:len(scopes)-1]
+			}
+			continue
+		}
+		if expression == "" || strings.HasPrefix(expression, "!") || strings.HasPrefix(expression, ">") {
+			// Comments and partials.
+			continue
+		}
+		block := strings.HasPrefix(expression, "#") || strings.HasPrefix(expression, "^")
+		words := strings.Fields(strings.TrimLeft(expression, "#^&"))
+		if len(words) == 0 {
+			continue
+		}
+		helper := words[0]
+		if len(words) > 1 {
+			// The first word is a helper, the rest are its arguments.
+			words = words[1:]
+		}
+		if i := indexOf(words, "as"); i >= 0 {
+			// Block parameters, like {{#each items as |item|}}.
+			words = words[:i]
+		}
+		for _, word := range words {
+			if isVariable(word) {
+				seen[resolve(word)] = true
+			}
+		}
+		if !block {
+			continue
+		}
+		// Blocks that change the context push the argument as the new
+		// scope, other blocks keep the current one.
+		scope := scopes[len(scopes)-1]
+		switch {
+		case strings.HasPrefix(expression, "^"):
+		case (helper == "each" || helper == "with") && len(words) == 1 && isVariable(words[0]):
+			scope = resolve(words[0])
+		case len(words) == 1 && words[0] == helper && isVariable(helper):
+			scope = resolve(helper)
+		}
+		scopes = append(scopes, scope)
+	}
+	variables := make([]string, 0, len(seen))
+	for variable := range seen {
+		variables = append(variables, variable)
+	}
+	sort.Strings(variables)
+	return variables
+}
+
+func indexOf(words []string, word string) int {
+	for i, w := range words {
+		if w == word {
+			return i
+		}
+	}
+	return -1
+}
+
+func isVariable(word string) bool {
+	if word == "" || keywords[word] || strings.HasPrefix(word, "@") || strings.HasPrefix(word, "../") {
+		return false
+	}
+	// String and number literals, and hash arguments.
+	if strings.ContainsAny(word[:1], `"'0123456789-`) || strings.Contains(word, "=") {
+		return false
+	}
+	return true
+}
+
+// Checks that the texts of a template use all required variables and
+// only reference available variables. Variables can reference nested
+// fields of available variables, like user.public_metadata.plan.
+func validateVariables(texts []string, available, required []string) []string {
+	used := map[string]bool{}
+	for _, text := range texts {
+		for _, variable := range Variables(text) {
+			used[variable] = true
+		}
+	}
+
+	var problems []string
+	for _, variable := range required {
+		if !isUsed(variable, used) {
+			problems = append(problems, fmt.Sprintf("required variable %q is not used", variable))
+		}
+	}
+	usedVariables := make([]string, 0, len(used))
+	for variable := range used {
+		usedVariables = append(usedVariables, variable)
+	}
+	sort.Strings(usedVariables)
+	for _, variable := range usedVariables {
+		if !isAvailable(variable, available) {
+			problems = append(problems, fmt.Sprintf("unknown variable %q", variable))
+		}
+	}
+	return problems
+}
+
+// A required variable is used when it is referenced directly or
+// through one of its nested fields, like user.first_name for user.
+func isUsed(variable string, used map[string]bool) bool {
+	if used[variable] {
+		return true
+	}
+	for name := range used {
+		if strings.HasPrefix(name, variable+".") {
+			return true
+		}
+	}
+	return false
+}
+
+func isAvailable(variable string, available []string) bool {
+	for _, name := range available {
+		if variable == name || strings.HasPrefix(variable, name+".") {
+			return true
+		}
+	}
+	return false
+}
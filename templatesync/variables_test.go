@@ -0,0 +1,53 @@
+package templatesync
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestVariables(t *testing.T) {
+	t.Parallel()
+	for _, tc := range []struct {
+		text string
+		want []string
+	}{
+		{text: "no variables", want: []string{}},
+		{text: "{{otp_code}} and {{ otp_code }}", want: []string{"otp_code"}},
+		{text: "{{{user.name}}}", want: []string{"user.name"}},
+		{text: "{{#if app.logo_url}}<img src={{app.logo_url}}>{{else}}{{app.name}}{{/if}}", want: []string{"app.logo_url", "app.name"}},
+		{text: "{{#each items}}{{@index}} {{this.name}}{{/each}}", want: []string{"items", "items.name"}},
+		{text: "{{#each items}}{{name}}{{/each}} {{name}}", want: []string{"items", "items.name", "name"}},
+		{text: "{{#with user}}{{#each orgs as |org|}}{{name}}{{/each}}{{/with}}", want: []string{"user", "user.orgs", "user.orgs.name"}},
+		{text: "{{#if user}}{{user.name}}{{/if}}{{#user}}{{name}}{{/user}}{{^items}}{{empty}}{{/items}}", want: []string{"empty", "items", "user", "user.name"}},
+		{text: `{{! comment }}{{formatDate created_at "short" tz=utc}}`, want: []string{"created_at"}},
+		{text: "{{~action_url~}}", want: []string{"action_url"}},
+		{text: "{{> footer}}{{>header app}}", want: []string{}},
+	} {
+		require.Equal(t, tc.want, Variables(tc.text), tc.text)
+	}
+}
+
+func TestValidateVariables(t *testing.T) {
+	t.Parallel()
+	available := []string{"app", "otp_code", "requested_at"}
+	required := []string{"otp_code"}
+
+	problems := validateVariables([]string{"{{otp_code}}", "{{app.name}} {{app.logo_url}}"}, available, required)
+	require.Empty(t, problems)
+
+	problems = validateVariables([]string{"Your code", "{{user.name}} {{application}}"}, available, required)
+	require.Equal(t, []string{
+		`required variable "otp_code" is not used`,
+		`unknown variable "application"`,
+		`unknown variable "user.name"`,
+	}, problems)
+
+	// Required variables can be used through their nested fields, and
+	// variables inside blocks are resolved against the block argument.
+	problems = validateVariables([]string{
+		"Hi {{user.first_name}}",
+		"{{#each user.organizations}}{{name}}{{/each}}",
+	}, []string{"user"}, []string{"user"})
+	require.Empty(t, problems)
+}
@@ -39,21 +39,62 @@ func UpdateMetadata(ctx context.Context, id string, params *UpdateMetadataParams
 	return getClient().UpdateMetadata(ctx, id, params)
 }
 
+// ModifyMetadata updates the user's metadata with the
+// params.Modify function. See clerk.ModifyMetadata for how
+// concurrent updates are detected and retried.
+func ModifyMetadata(ctx context.Context, id string, params *ModifyMetadataParams) (*clerk.User, error) {
+	return getClient().ModifyMetadata(ctx, id, params)
+}
+
 // Delete deletes a user.
 func Delete(ctx context.Context, id string) (*clerk.DeletedResource, error) {
 	return getClient().Delete(ctx, id)
 }
 
 // List returns a list of users.
+// Unless params.SkipTotalCount is set, the total count of users is
+// retrieved with an additional request.
 func List(ctx context.Context, params *ListParams) (*clerk.UserList, error) {
 	return getClient().List(ctx, params)
 }
 
+// ListEach iterates over all users that match the params, page by
+// page, and calls fn for each user. Iteration stops at the first
+// error returned by fn. The total count of users is never requested.
+// Pagination starts at params.Offset and pages contain params.Limit
+// users, or 100 if no limit is set.
+func ListEach(ctx context.Context, params *ListParams, fn func(*clerk.User) error) error {
+	return getClient().ListEach(ctx, params, fn)
+}
+
 // Count returns the total count of users satisfying the parameters.
 func Count(ctx context.Context, params *ListParams) (*TotalCount, error) {
 	return getClient().Count(ctx, params)
 }
 
+// GetMany retrieves the users with the provided IDs.
+// The IDs are split in chunks which are requested concurrently.
+// The total count of users is not requested.
+func GetMany(ctx context.Context, ids []string, params *GetManyParams) (*GetManyResult, error) {
+	return getClient().GetMany(ctx, ids, params)
+}
+
+// GetManyByEmailAddress retrieves the users with the provided email
+// addresses. Email addresses are matched case-insensitively.
+// The email addresses are split in chunks which are requested
+// concurrently. The total count of users is not requested.
+func GetManyByEmailAddress(ctx context.Context, emailAddresses []string, params *GetManyParams) (*GetManyResult, error) {
+	return getClient().GetManyByEmailAddress(ctx, emailAddresses, params)
+}
+
+// GetManyByExternalID retrieves the users with the provided external
+// IDs.
+// The external IDs are split in chunks which are requested
+// concurrently. The total count of users is not requested.
+func GetManyByExternalID(ctx context.Context, externalIDs []string, params *GetManyParams) (*GetManyResult, error) {
+	return getClient().GetManyByExternalID(ctx, externalIDs, params)
+}
+
 // ListOAuthAccessTokens retrieves a list of the user's access
 // tokens for a specific OAuth provider.
 func ListOAuthAccessTokens(ctx context.Context, params *ListOAuthAccessTokensParams) (*clerk.OAuthAccessTokenList, error) {
@@ -122,6 +163,20 @@ func DeleteBackupCode(ctx context.Context, userID string) (*MultifactorAuthentic
 	return getClient().DeleteBackupCode(ctx, userID)
 }
 
+// VerifyPassword checks that the password matches the user's
+// password. Failed verifications return ErrIncorrectPassword or
+// ErrUserLocked, which can be checked with errors.Is.
+func VerifyPassword(ctx context.Context, params *VerifyPasswordParams) (*PasswordVerification, error) {
+	return getClient().VerifyPassword(ctx, params)
+}
+
+// VerifyTOTP checks that the code is a valid TOTP or backup code
+// for the user. Failed verifications return ErrIncorrectCode or
+// ErrUserLocked, which can be checked with errors.Is.
+func VerifyTOTP(ctx context.Context, params *VerifyTOTPParams) (*TOTPVerification, error) {
+	return getClient().VerifyTOTP(ctx, params)
+}
+
 // DeleteExternalAccount deletes an external account by its ID.
 func DeleteExternalAccount(ctx context.Context, params *DeleteExternalAccountParams) (*clerk.DeletedResource, error) {
 	return getClient().DeleteExternalAccount(ctx, params)
@@ -5,12 +5,15 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"mime/multipart"
 	"net/http"
 	"net/url"
 	"strconv"
+	"strings"
+	"sync"
 
 	"github.com/clerk/clerk-sdk-go/v2"
 )
@@ -61,6 +64,9 @@ type CreateParams struct {
 
 // Create creates a new user.
 func (c *Client) Create(ctx context.Context, params *CreateParams) (*clerk.User, error) {
+	if err := validateMetadata(params.PublicMetadata, params.PrivateMetadata, params.UnsafeMetadata, false); err != nil {
+		return nil, err
+	}
 	req := clerk.NewAPIRequest(http.MethodPost, path)
 	req.SetParams(params)
 	resource := &clerk.User{}
@@ -114,6 +120,9 @@ type UpdateParams struct {
 
 // Update updates a user.
 func (c *Client) Update(ctx context.Context, id string, params *UpdateParams) (*clerk.User, error) {
+	if err := validateMetadata(params.PublicMetadata, params.PrivateMetadata, params.UnsafeMetadata, false); err != nil {
+		return nil, err
+	}
 	path, err := clerk.JoinPath(path, id)
 	if err != nil {
 		return nil, err
@@ -186,6 +195,9 @@ type UpdateMetadataParams struct {
 // UpdateMetadata updates the user's metadata by merging the
 // provided values with the existing ones.
 func (c *Client) UpdateMetadata(ctx context.Context, id string, params *UpdateMetadataParams) (*clerk.User, error) {
+	if err := validateMetadata(params.PublicMetadata, params.PrivateMetadata, params.UnsafeMetadata, true); err != nil {
+		return nil, err
+	}
 	path, err := clerk.JoinPath(path, id, "/metadata")
 	if err != nil {
 		return nil, err
@@ -197,6 +209,44 @@ func (c *Client) UpdateMetadata(ctx context.Context, id string, params *UpdateMe
 	return resource, err
 }
 
+type ModifyMetadataParams struct {
+	clerk.MetadataRetryParams
+	// Modify is called with the latest version of the user and
+	// changes its PublicMetadata, PrivateMetadata or UnsafeMetadata
+	// in place. It might be called more than once.
+	Modify func(user *clerk.User) error
+}
+
+// ModifyMetadata updates the user's metadata with the
+// params.Modify function. See clerk.ModifyMetadata for how
+// concurrent updates are detected and retried.
+func (c *Client) ModifyMetadata(ctx context.Context, id string, params *ModifyMetadataParams) (*clerk.User, error) {
+	if params == nil {
+		return nil, errors.New("missing Modify function")
+	}
+	return clerk.ModifyMetadata(ctx, &params.MetadataRetryParams, &clerk.MetadataModifier[clerk.User]{
+		Get: func(ctx context.Context) (*clerk.User, error) {
+			return c.Get(ctx, id)
+		},
+		Modify: params.Modify,
+		Metadata: func(user *clerk.User) *clerk.ResourceMetadata {
+			return &clerk.ResourceMetadata{
+				PublicMetadata:  user.PublicMetadata,
+				PrivateMetadata: user.PrivateMetadata,
+				UnsafeMetadata:  user.UnsafeMetadata,
+				UpdatedAt:       user.UpdatedAt,
+			}
+		},
+		Update: func(ctx context.Context, changes *clerk.MetadataChanges) (*clerk.User, error) {
+			return c.UpdateMetadata(ctx, id, &UpdateMetadataParams{
+				PublicMetadata:  changes.PublicMetadata,
+				PrivateMetadata: changes.PrivateMetadata,
+				UnsafeMetadata:  changes.UnsafeMetadata,
+			})
+		},
+	})
+}
+
 // Delete deletes a user.
 func (c *Client) Delete(ctx context.Context, id string) (*clerk.DeletedResource, error) {
 	path, err := clerk.JoinPath(path, id)
@@ -236,6 +286,12 @@ type ListParams struct {
 	CreatedAtAfter     *int64 `json:"created_at_after,omitempty"`
 	LastActiveAtBefore *int64 `json:"last_active_at_before,omitempty"`
 	LastActiveAtAfter  *int64 `json:"last_active_at_after,omitempty"`
+	// SkipTotalCount skips the request for the total count of users.
+	// The TotalCount of the returned list will be zero.
+	SkipTotalCount bool `json:"-"`
+	// ConcurrentTotalCount requests the users and their total count
+	// concurrently, instead of one after the other.
+	ConcurrentTotalCount bool `json:"-"`
 }
 
 // ToQuery returns url.Values from the params.
@@ -303,6 +359,8 @@ func (params *ListParams) ToQuery() url.Values {
 }
 
 // List returns a list of users.
+// Unless params.SkipTotalCount is set, the total count of users is
+// retrieved with an additional request.
 func (c *Client) List(ctx context.Context, params *ListParams) (*clerk.UserList, error) {
 	// The Clerk API returns the results of GET /v1/users as an
 	// array. In order to build the final response that includes
@@ -310,12 +368,38 @@ func (c *Client) List(ctx context.Context, params *ListParams) (*clerk.UserList,
 	// GET /v1/users retrieves the actual results
 	// GET /v1/users/count retrieves the total count
 	// The response is then synthesized from the individual responses.
+	if params.SkipTotalCount {
+		users, err := c.listPage(ctx, params)
+		if err != nil {
+			return nil, err
+		}
+		return &clerk.UserList{Users: users}, nil
+	}
+
+	if params.ConcurrentTotalCount {
+		var totalCount *TotalCount
+		var countErr error
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			totalCount, countErr = c.Count(ctx, params)
+		}()
+		users, err := c.listPage(ctx, params)
+		<-done
+		if err != nil {
+			return nil, err
+		}
+		if countErr != nil {
+			return nil, countErr
+		}
+		return &clerk.UserList{
+			Users:      users,
+			TotalCount: totalCount.TotalCount,
+		}, nil
+	}
 
 	// GET /v1/users
-	req := clerk.NewAPIRequest(http.MethodGet, path)
-	req.SetParams(params)
-	data := &userList{}
-	err := c.Backend.Call(ctx, req, data)
+	users, err := c.listPage(ctx, params)
 	if err != nil {
 		return nil, err
 	}
@@ -326,13 +410,48 @@ func (c *Client) List(ctx context.Context, params *ListParams) (*clerk.UserList,
 		return nil, err
 	}
 
-	users := []*clerk.User(*data)
 	return &clerk.UserList{
 		Users:      users,
 		TotalCount: totalCount.TotalCount,
 	}, nil
 }
 
+const defaultListEachLimit = 100
+
+// ListEach iterates over all users that match the params, page by
+// page, and calls fn for each user. Iteration stops at the first
+// error returned by fn. The total count of users is never requested.
+// Pagination starts at params.Offset and pages contain params.Limit
+// users, or 100 if no limit is set.
+func (c *Client) ListEach(ctx context.Context, params *ListParams, fn func(*clerk.User) error) error {
+	pageParams := *params
+	limit := int64(defaultListEachLimit)
+	if params.Limit != nil && *params.Limit > 0 {
+		limit = *params.Limit
+	}
+	offset := int64(0)
+	if params.Offset != nil {
+		offset = *params.Offset
+	}
+	for {
+		pageParams.Limit = clerk.Int64(limit)
+		pageParams.Offset = clerk.Int64(offset)
+		users, err := c.listPage(ctx, &pageParams)
+		if err != nil {
+			return err
+		}
+		for _, user := range users {
+			if err := fn(user); err != nil {
+				return err
+			}
+		}
+		if int64(len(users)) < limit {
+			return nil
+		}
+		offset += limit
+	}
+}
+
 // Count returns the total count of users satisfying the parameters.
 func (c *Client) Count(ctx context.Context, params *ListParams) (*TotalCount, error) {
 	path, err := clerk.JoinPath(path, "/count")
@@ -346,6 +465,195 @@ func (c *Client) Count(ctx context.Context, params *ListParams) (*TotalCount, er
 	return resource, err
 }
 
+// Retrieves a single page of users, without the total count.
+func (c *Client) listPage(ctx context.Context, params *ListParams) ([]*clerk.User, error) {
+	req := clerk.NewAPIRequest(http.MethodGet, path)
+	req.SetParams(params)
+	data := &userList{}
+	err := c.Backend.Call(ctx, req, data)
+	if err != nil {
+		return nil, err
+	}
+	return []*clerk.User(*data), nil
+}
+
+const (
+	defaultGetManyChunkSize   = 100
+	defaultGetManyConcurrency = 4
+)
+
+type GetManyParams struct {
+	// ChunkSize is the maximum number of identifiers that are sent
+	// in a single request. Defaults to 100.
+	ChunkSize int
+	// Concurrency is the maximum number of requests that are made
+	// concurrently. Defaults to 4.
+	Concurrency int
+}
+
+// GetManyResult holds the users that were found for a batch lookup.
+type GetManyResult struct {
+	// Users maps each identifier that was found to its user.
+	Users map[string]*clerk.User
+	// Missing holds the identifiers for which no user was found, in
+	// the order they were provided.
+	Missing []string
+}
+
+// GetMany retrieves the users with the provided IDs.
+// The IDs are split in chunks which are requested concurrently.
+// The total count of users is not requested.
+func (c *Client) GetMany(ctx context.Context, ids []string, params *GetManyParams) (*GetManyResult, error) {
+	return c.getMany(ctx, ids, params, false, func(listParams *ListParams, chunk []string) {
+		listParams.UserIDs = chunk
+	}, func(user *clerk.User) []string {
+		return []string{user.ID}
+	})
+}
+
+// GetManyByEmailAddress retrieves the users with the provided email
+// addresses. Email addresses are matched case-insensitively.
+// The email addresses are split in chunks which are requested
+// concurrently. The total count of users is not requested.
+func (c *Client) GetManyByEmailAddress(ctx context.Context, emailAddresses []string, params *GetManyParams) (*GetManyResult, error) {
+	return c.getMany(ctx, emailAddresses, params, true, func(listParams *ListParams, chunk []string) {
+		listParams.EmailAddresses = chunk
+	}, func(user *clerk.User) []string {
+		identifiers := make([]string, 0, len(user.EmailAddresses))
+		for _, emailAddress := range user.EmailAddresses {
+			identifiers = append(identifiers, emailAddress.EmailAddress)
+		}
+		return identifiers
+	})
+}
+
+// GetManyByExternalID retrieves the users with the provided external
+// IDs.
+// The external IDs are split in chunks which are requested
+// concurrently. The total count of users is not requested.
+func (c *Client) GetManyByExternalID(ctx context.Context, externalIDs []string, params *GetManyParams) (*GetManyResult, error) {
+	return c.getMany(ctx, externalIDs, params, false, func(listParams *ListParams, chunk []string) {
+		listParams.ExternalIDs = chunk
+	}, func(user *clerk.User) []string {
+		if user.ExternalID == nil {
+			return nil
+		}
+		return []string{*user.ExternalID}
+	})
+}
+
+// Looks up users by identifiers in concurrent chunks. The filter
+// function sets the chunk of identifiers on the list params and the
+// identifiers function returns the identifiers of a user that can
+// match the input. If foldCase is true, identifiers are matched
+// case-insensitively.
+func (c *Client) getMany(
+	ctx context.Context,
+	identifiers []string,
+	params *GetManyParams,
+	foldCase bool,
+	filter func(*ListParams, []string),
+	userIdentifiers func(*clerk.User) []string,
+) (*GetManyResult, error) {
+	if params == nil {
+		params = &GetManyParams{}
+	}
+	chunkSize := params.ChunkSize
+	if chunkSize <= 0 {
+		chunkSize = defaultGetManyChunkSize
+	}
+	concurrency := params.Concurrency
+	if concurrency <= 0 {
+		concurrency = defaultGetManyConcurrency
+	}
+
+	normalize := func(identifier string) string {
+		if foldCase {
+			return strings.ToLower(identifier)
+		}
+		return identifier
+	}
+	unique := make([]string, 0, len(identifiers))
+	seen := map[string]bool{}
+	for _, identifier := range identifiers {
+		normalized := normalize(identifier)
+		if seen[normalized] {
+			continue
+		}
+		seen[normalized] = true
+		unique = append(unique, identifier)
+	}
+	var chunks [][]string
+	for start := 0; start < len(unique); start += chunkSize {
+		end := start + chunkSize
+		if end > len(unique) {
+			end = len(unique)
+		}
+		chunks = append(chunks, unique[start:end])
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	results := make([][]*clerk.User, len(chunks))
+	errs := make([]error, len(chunks))
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for i, chunk := range chunks {
+		wg.Add(1)
+		go func(i int, chunk []string) {
+			defer wg.Done()
+			select {
+			case sem <- struct{}{}:
+			case <-ctx.Done():
+				errs[i] = ctx.Err()
+				return
+			}
+			defer func() { <-sem }()
+			listParams := &ListParams{}
+			listParams.Limit = clerk.Int64(int64(len(chunk)))
+			filter(listParams, chunk)
+			results[i], errs[i] = c.listPage(ctx, listParams)
+			if errs[i] != nil {
+				// Stop the remaining requests.
+				cancel()
+			}
+		}(i, chunk)
+	}
+	wg.Wait()
+	for _, err := range errs {
+		if err != nil && !errors.Is(err, context.Canceled) {
+			return nil, err
+		}
+	}
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	found := map[string]*clerk.User{}
+	for _, users := range results {
+		for _, user := range users {
+			for _, identifier := range userIdentifiers(user) {
+				found[normalize(identifier)] = user
+			}
+		}
+	}
+	res := &GetManyResult{
+		Users: map[string]*clerk.User{},
+	}
+	missing := map[string]bool{}
+	for _, identifier := range identifiers {
+		if user, ok := found[normalize(identifier)]; ok {
+			res.Users[identifier] = user
+		} else if !missing[identifier] {
+			missing[identifier] = true
+			res.Missing = append(res.Missing, identifier)
+		}
+	}
+	return res, nil
+}
+
 // Custom type needed in order to store the GET /v1/users results
 // array.
 type userList []*clerk.User
@@ -566,6 +874,130 @@ func (c *Client) DeleteBackupCode(ctx context.Context, userID string) (*Multifac
 	return resource, err
 }
 
+var (
+	// ErrIncorrectPassword is returned by VerifyPassword when the
+	// password doesn't match the user's password.
+	ErrIncorrectPassword = errors.New("clerk: incorrect password")
+	// ErrIncorrectCode is returned by VerifyTOTP when the code is
+	// neither a valid TOTP nor a valid backup code.
+	ErrIncorrectCode = errors.New("clerk: incorrect verification code")
+	// ErrUserLocked is returned by VerifyPassword and VerifyTOTP when
+	// the user is locked because of too many failed attempts.
+	ErrUserLocked = errors.New("clerk: user is locked")
+)
+
+// Maps Clerk API error codes to the verification errors.
+var verificationErrors = map[string]error{
+	"form_password_incorrect": ErrIncorrectPassword,
+	"incorrect_password":      ErrIncorrectPassword,
+	"form_code_incorrect":     ErrIncorrectCode,
+	"verification_failed":     ErrIncorrectCode,
+	"user_locked":             ErrUserLocked,
+}
+
+// verificationError wraps the Clerk API error response of a failed
+// verification. It matches one of the verification errors with
+// errors.Is, and the *clerk.APIErrorResponse with errors.As.
+type verificationError struct {
+	reason error
+	apiErr *clerk.APIErrorResponse
+}
+
+func (e *verificationError) Error() string {
+	return e.apiErr.Error()
+}
+
+func (e *verificationError) Is(target error) bool {
+	return e.reason == target
+}
+
+func (e *verificationError) Unwrap() error {
+	return e.apiErr
+}
+
+// Translates known verification failures into errors that can be
+// matched with the verification error values.
+func toVerificationError(err error) error {
+	var apiErr *clerk.APIErrorResponse
+	if !errors.As(err, &apiErr) {
+		return err
+	}
+	for _, e := range apiErr.Errors {
+		if reason, ok := verificationErrors[e.Code]; ok {
+			return &verificationError{reason: reason, apiErr: apiErr}
+		}
+	}
+	return err
+}
+
+type VerifyPasswordParams struct {
+	clerk.APIParams
+	UserID   string  `json:"-"`
+	Password *string `json:"password,omitempty"`
+}
+
+type PasswordVerification struct {
+	clerk.APIResource
+	Verified bool `json:"verified"`
+}
+
+// VerifyPassword checks that the password matches the user's
+// password. Failed verifications return ErrIncorrectPassword or
+// ErrUserLocked, which can be checked with errors.Is.
+func (c *Client) VerifyPassword(ctx context.Context, params *VerifyPasswordParams) (*PasswordVerification, error) {
+	path, err := clerk.JoinPath(path, params.UserID, "/verify_password")
+	if err != nil {
+		return nil, err
+	}
+	req := clerk.NewAPIRequest(http.MethodPost, path)
+	req.SetParams(params)
+	resource := &PasswordVerification{}
+	err = c.Backend.Call(ctx, req, resource)
+	if err != nil {
+		return nil, toVerificationError(err)
+	}
+	return resource, nil
+}
+
+// TOTPCodeType is the type of code that was used for a TOTP
+// verification.
+type TOTPCodeType string
+
+const (
+	TOTPCodeTypeTOTP       TOTPCodeType = "totp"
+	TOTPCodeTypeBackupCode TOTPCodeType = "backup_code"
+)
+
+type VerifyTOTPParams struct {
+	clerk.APIParams
+	UserID string  `json:"-"`
+	Code   *string `json:"code,omitempty"`
+}
+
+type TOTPVerification struct {
+	clerk.APIResource
+	Verified bool         `json:"verified"`
+	CodeType TOTPCodeType `json:"code_type"`
+}
+
+// VerifyTOTP checks that the code is a valid TOTP or backup code
+// for the user. Failed verifications return ErrIncorrectCode or
+// ErrUserLocked, which can be checked with errors.Is.
+func (c *Client) VerifyTOTP(ctx context.Context, params *VerifyTOTPParams) (*TOTPVerification, error) {
+	path, err := clerk.JoinPath(path, params.UserID, "/verify_totp")
+	if err != nil {
+		return nil, err
+	}
+	req := clerk.NewAPIRequest(http.MethodPost, path)
+	req.SetParams(params)
+	resource := &TOTPVerification{}
+	err = c.Backend.Call(ctx, req, resource)
+	if err != nil {
+		return nil, toVerificationError(err)
+	}
+	return resource, nil
+}
+
 type DeleteExternalAccountParams struct {
 	clerk.APIParams
 	UserID string `json:"-"`
@@ -583,3 +1015,16 @@ func (c *Client) DeleteExternalAccount(ctx context.Context, params *DeleteExtern
 	err = c.Backend.Call(ctx, req, resource)
 	return resource, err
 }
+
+// Runs the registered metadata validators for users.
+func validateMetadata(public, private, unsafe *json.RawMessage, merge bool) error {
+	err := clerk.ValidateMetadata(clerk.MetadataResourceUser, clerk.MetadataKindPublic, public, merge)
+	if err != nil {
+		return err
+	}
+	err = clerk.ValidateMetadata(clerk.MetadataResourceUser, clerk.MetadataKindPrivate, private, merge)
+	if err != nil {
+		return err
+	}
+	return clerk.ValidateMetadata(clerk.MetadataResourceUser, clerk.MetadataKindUnsafe, unsafe, merge)
+}
@@ -4,11 +4,14 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
+	"strconv"
 	"strings"
+	"sync"
 	"testing"
 
 	"github.com/clerk/clerk-sdk-go/v2"
@@ -107,6 +110,89 @@ func TestUserClientList_Response(t *testing.T) {
 	require.Equal(t, "user_123", list.Users[0].ID)
 }
 
+func TestUserClientList_TotalCountModes(t *testing.T) {
+	t.Parallel()
+	var mu sync.Mutex
+	requests := map[string]int{}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requests[r.URL.Path]++
+		mu.Unlock()
+		if strings.Contains(r.URL.Path, "count") {
+			_, err := w.Write([]byte(`{"object":"total_count","total_count":5}`))
+			require.NoError(t, err)
+			return
+		}
+		_, err := w.Write([]byte(`[{"object":"user","id":"user_123"}]`))
+		require.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	config := &clerk.ClientConfig{}
+	config.URL = clerk.String(ts.URL)
+	config.HTTPClient = ts.Client()
+	client := NewClient(config)
+
+	list, err := client.List(context.Background(), &ListParams{SkipTotalCount: true})
+	require.NoError(t, err)
+	require.Equal(t, int64(0), list.TotalCount)
+	require.Equal(t, 1, len(list.Users))
+	require.Equal(t, map[string]int{"/users": 1}, requests)
+
+	list, err = client.List(context.Background(), &ListParams{ConcurrentTotalCount: true})
+	require.NoError(t, err)
+	require.Equal(t, int64(5), list.TotalCount)
+	require.Equal(t, 1, len(list.Users))
+	require.Equal(t, map[string]int{"/users": 2, "/users/count": 1}, requests)
+}
+
+func TestUserClientListEach(t *testing.T) {
+	t.Parallel()
+	var offsets []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// The total count is never requested.
+		require.Equal(t, "/users", r.URL.Path)
+		require.Equal(t, "2", r.URL.Query().Get("limit"))
+		require.Equal(t, "foo@bar.com", r.URL.Query().Get("email_address"))
+		offset := r.URL.Query().Get("offset")
+		offsets = append(offsets, offset)
+		users := map[string]string{
+			"0": `[{"id":"user_1"},{"id":"user_2"}]`,
+			"2": `[{"id":"user_3"},{"id":"user_4"}]`,
+			"4": `[{"id":"user_5"}]`,
+		}[offset]
+		_, err := w.Write([]byte(users))
+		require.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	config := &clerk.ClientConfig{}
+	config.URL = clerk.String(ts.URL)
+	config.HTTPClient = ts.Client()
+	client := NewClient(config)
+
+	params := &ListParams{EmailAddresses: []string{"foo@bar.com"}}
+	params.Limit = clerk.Int64(2)
+	var ids []string
+	err := client.ListEach(context.Background(), params, func(user *clerk.User) error {
+		ids = append(ids, user.ID)
+		return nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{"user_1", "user_2", "user_3", "user_4", "user_5"}, ids)
+	require.Equal(t, []string{"0", "2", "4"}, offsets)
+	// The params are not modified.
+	require.Nil(t, params.Offset)
+
+	// Iteration stops when the callback fails.
+	offsets = nil
+	err = client.ListEach(context.Background(), params, func(user *clerk.User) error {
+		return fmt.Errorf("stop")
+	})
+	require.EqualError(t, err, "stop")
+	require.Equal(t, []string{"0"}, offsets)
+}
+
 func TestUserClientCount(t *testing.T) {
 	t.Parallel()
 	config := &clerk.ClientConfig{}
@@ -592,3 +678,226 @@ func TestUserClientDeleteExternalAccount(t *testing.T) {
 	require.Equal(t, externalAccountID, externalAccount.ID)
 	require.Equal(t, "external_account", externalAccount.Object)
 }
+
+func TestUserClientGetMany(t *testing.T) {
+	t.Parallel()
+	var mu sync.Mutex
+	var requestedIDs [][]string
+	clerkAPI := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// The total count is never requested.
+		require.Equal(t, "/users", r.URL.Path)
+		ids := r.URL.Query()["user_id"]
+		require.Equal(t, strconv.Itoa(len(ids)), r.URL.Query().Get("limit"))
+		mu.Lock()
+		requestedIDs = append(requestedIDs, ids)
+		mu.Unlock()
+		users := []map[string]string{}
+		for _, id := range ids {
+			if id != "user_missing" {
+				users = append(users, map[string]string{"id": id})
+			}
+		}
+		require.NoError(t, json.NewEncoder(w).Encode(users))
+	}))
+	defer clerkAPI.Close()
+	config := &clerk.ClientConfig{}
+	config.HTTPClient = clerkAPI.Client()
+	config.URL = &clerkAPI.URL
+	client := NewClient(config)
+
+	res, err := client.GetMany(context.Background(), []string{"user_1", "user_2", "user_missing", "user_3", "user_1", "user_4"}, &GetManyParams{
+		ChunkSize:   2,
+		Concurrency: 2,
+	})
+	require.NoError(t, err)
+	require.Len(t, res.Users, 4)
+	for _, id := range []string{"user_1", "user_2", "user_3", "user_4"} {
+		require.Equal(t, id, res.Users[id].ID)
+	}
+	require.Equal(t, []string{"user_missing"}, res.Missing)
+	// Duplicate identifiers are requested once.
+	require.ElementsMatch(t, [][]string{{"user_1", "user_2"}, {"user_missing", "user_3"}, {"user_4"}}, requestedIDs)
+}
+
+func TestUserClientGetManyByEmailAddress(t *testing.T) {
+	t.Parallel()
+	config := &clerk.ClientConfig{}
+	config.HTTPClient = &http.Client{
+		Transport: &clerktest.RoundTripper{
+			T: t,
+			Out: json.RawMessage(`[
+{"id":"user_1","email_addresses":[{"email_address":"foo@bar.com"},{"email_address":"foo@baz.com"}]},
+{"id":"user_2","email_addresses":[{"email_address":"Qux@bar.com"}]}
+]`),
+			Method: http.MethodGet,
+			Path:   "/v1/users",
+			Query: &url.Values{
+				"email_address": []string{"foo@bar.com", "foo@baz.com", "qux@bar.com", "missing@bar.com"},
+				"limit":         []string{"4"},
+			},
+		},
+	}
+	client := NewClient(config)
+	res, err := client.GetManyByEmailAddress(context.Background(), []string{"foo@bar.com", "foo@baz.com", "qux@bar.com", "missing@bar.com"}, nil)
+	require.NoError(t, err)
+	require.Equal(t, "user_1", res.Users["foo@bar.com"].ID)
+	require.Equal(t, "user_1", res.Users["foo@baz.com"].ID)
+	require.Equal(t, "user_2", res.Users["qux@bar.com"].ID)
+	require.Equal(t, []string{"missing@bar.com"}, res.Missing)
+}
+
+func TestUserClientGetManyByExternalID(t *testing.T) {
+	t.Parallel()
+	config := &clerk.ClientConfig{}
+	config.HTTPClient = &http.Client{
+		Transport: &clerktest.RoundTripper{
+			T:      t,
+			Out:    json.RawMessage(`[{"id":"user_1","external_id":"ABC"},{"id":"user_2","external_id":"abc"}]`),
+			Method: http.MethodGet,
+			Path:   "/v1/users",
+			Query: &url.Values{
+				"external_id": []string{"ABC", "abc", "Abc"},
+				"limit":       []string{"3"},
+			},
+		},
+	}
+	client := NewClient(config)
+	// External IDs are case-sensitive.
+	res, err := client.GetManyByExternalID(context.Background(), []string{"ABC", "abc", "Abc"}, nil)
+	require.NoError(t, err)
+	require.Len(t, res.Users, 2)
+	require.Equal(t, "user_1", res.Users["ABC"].ID)
+	require.Equal(t, "user_2", res.Users["abc"].ID)
+	require.Equal(t, []string{"Abc"}, res.Missing)
+}
+
+func TestUserClientGetManyByExternalID_Error(t *testing.T) {
+	t.Parallel()
+	config := &clerk.ClientConfig{}
+	config.HTTPClient = &http.Client{
+		Transport: &clerktest.RoundTripper{
+			T:      t,
+			Status: http.StatusBadRequest,
+			Out:    json.RawMessage(`{"errors":[{"code":"invalid_request"}]}`),
+		},
+	}
+	client := NewClient(config)
+	_, err := client.GetManyByExternalID(context.Background(), []string{"ext_1", "ext_2", "ext_3"}, &GetManyParams{ChunkSize: 1})
+	apiErr, ok := err.(*clerk.APIErrorResponse)
+	require.True(t, ok)
+	require.Equal(t, http.StatusBadRequest, apiErr.HTTPStatusCode)
+}
+
+func TestUserClientVerifyPassword(t *testing.T) {
+	t.Parallel()
+	userID := "user_123"
+	password := "secret"
+	config := &clerk.ClientConfig{}
+	config.HTTPClient = &http.Client{
+		Transport: &clerktest.RoundTripper{
+			T:      t,
+			In:     json.RawMessage(fmt.Sprintf(`{"password":"%s"}`, password)),
+			Out:    json.RawMessage(`{"verified":true}`),
+			Method: http.MethodPost,
+			Path:   "/v1/users/" + userID + "/verify_password",
+		},
+	}
+	client := NewClient(config)
+	verification, err := client.VerifyPassword(context.Background(), &VerifyPasswordParams{
+		UserID:   userID,
+		Password: clerk.String(password),
+	})
+	require.NoError(t, err)
+	require.True(t, verification.Verified)
+}
+
+func TestUserClientVerifyPassword_Error(t *testing.T) {
+	t.Parallel()
+	for _, tc := range []struct {
+		code   string
+		status int
+		want   error
+	}{
+		{code: "form_password_incorrect", status: http.StatusUnprocessableEntity, want: ErrIncorrectPassword},
+		{code: "user_locked", status: http.StatusForbidden, want: ErrUserLocked},
+	} {
+		tc := tc
+		t.Run(tc.code, func(t *testing.T) {
+			t.Parallel()
+			config := &clerk.ClientConfig{}
+			config.HTTPClient = &http.Client{
+				Transport: &clerktest.RoundTripper{
+					T:      t,
+					Status: tc.status,
+					Out:    json.RawMessage(fmt.Sprintf(`{"errors":[{"code":"%s"}],"clerk_trace_id":"trace-id"}`, tc.code)),
+				},
+			}
+			client := NewClient(config)
+			_, err := client.VerifyPassword(context.Background(), &VerifyPasswordParams{
+				UserID:   "user_123",
+				Password: clerk.String("wrong"),
+			})
+			require.ErrorIs(t, err, tc.want)
+			var apiErr *clerk.APIErrorResponse
+			require.ErrorAs(t, err, &apiErr)
+			require.Equal(t, "trace-id", apiErr.TraceID)
+			require.Equal(t, tc.code, apiErr.Errors[0].Code)
+		})
+	}
+}
+
+func TestUserClientVerifyTOTP(t *testing.T) {
+	t.Parallel()
+	userID := "user_123"
+	code := "123456"
+	config := &clerk.ClientConfig{}
+	config.HTTPClient = &http.Client{
+		Transport: &clerktest.RoundTripper{
+			T:      t,
+			In:     json.RawMessage(fmt.Sprintf(`{"code":"%s"}`, code)),
+			Out:    json.RawMessage(`{"verified":true,"code_type":"backup_code"}`),
+			Method: http.MethodPost,
+			Path:   "/v1/users/" + userID + "/verify_totp",
+		},
+	}
+	client := NewClient(config)
+	verification, err := client.VerifyTOTP(context.Background(), &VerifyTOTPParams{
+		UserID: userID,
+		Code:   clerk.String(code),
+	})
+	require.NoError(t, err)
+	require.True(t, verification.Verified)
+	require.Equal(t, TOTPCodeTypeBackupCode, verification.CodeType)
+}
+
+func TestUserClientVerifyTOTP_Error(t *testing.T) {
+	t.Parallel()
+	config := &clerk.ClientConfig{}
+	config.HTTPClient = &http.Client{
+		Transport: &clerktest.RoundTripper{
+			T:      t,
+			Status: http.StatusUnprocessableEntity,
+			Out:    json.RawMessage(`{"errors":[{"code":"form_code_incorrect"}]}`),
+		},
+	}
+	client := NewClient(config)
+	_, err := client.VerifyTOTP(context.Background(), &VerifyTOTPParams{
+		UserID: "user_123",
+		Code:   clerk.String("000000"),
+	})
+	require.ErrorIs(t, err, ErrIncorrectCode)
+	require.False(t, errors.Is(err, ErrUserLocked))
+
+	// Unknown errors are returned as is.
+	config.HTTPClient = &http.Client{
+		Transport: &clerktest.RoundTripper{
+			T:      t,
+			Status: http.StatusNotFound,
+			Out:    json.RawMessage(`{"errors":[{"code":"resource_not_found"}]}`),
+		},
+	}
+	client = NewClient(config)
+	_, err = client.VerifyTOTP(context.Background(), &VerifyTOTPParams{UserID: "user_123"})
+	_, ok := err.(*clerk.APIErrorResponse)
+	require.True(t, ok)
+}
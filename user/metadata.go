@@ -0,0 +1,46 @@
+package user
+
+import (
+	"context"
+
+	"github.com/clerk/clerk-sdk-go/v2"
+)
+
+// UpdatePublicMetadata encodes the metadata and merges it with the
+// user's existing public metadata. Use a clerk.MetadataPatch to
+// delete keys.
+func UpdatePublicMetadata[T any](ctx context.Context, id string, metadata T) (*clerk.User, error) {
+	params := &UpdateMetadataParams{}
+	var err error
+	params.PublicMetadata, err = clerk.MarshalMetadata(metadata)
+	if err != nil {
+		return nil, err
+	}
+	return getClient().UpdateMetadata(ctx, id, params)
+}
+
+// UpdatePrivateMetadata encodes the metadata and merges it with the
+// user's existing private metadata. Use a clerk.MetadataPatch to
+// delete keys.
+func UpdatePrivateMetadata[T any](ctx context.Context, id string, metadata T) (*clerk.User, error) {
+	params := &UpdateMetadataParams{}
+	var err error
+	params.PrivateMetadata, err = clerk.MarshalMetadata(metadata)
+	if err != nil {
+		return nil, err
+	}
+	return getClient().UpdateMetadata(ctx, id, params)
+}
+
+// UpdateUnsafeMetadata encodes the metadata and merges it with the
+// user's existing unsafe metadata. Use a clerk.MetadataPatch to
+// delete keys.
+func UpdateUnsafeMetadata[T any](ctx context.Context, id string, metadata T) (*clerk.User, error) {
+	params := &UpdateMetadataParams{}
+	var err error
+	params.UnsafeMetadata, err = clerk.MarshalMetadata(metadata)
+	if err != nil {
+		return nil, err
+	}
+	return getClient().UpdateMetadata(ctx, id, params)
+}
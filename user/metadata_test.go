@@ -0,0 +1,196 @@
+package user
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/clerk/clerk-sdk-go/v2"
+	"github.com/clerk/clerk-sdk-go/v2/clerktest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUpdatePublicMetadata(t *testing.T) {
+	type preferences struct {
+		Theme string `json:"theme"`
+	}
+	clerk.SetBackend(clerk.NewBackend(&clerk.BackendConfig{
+		HTTPClient: &http.Client{
+			Transport: &clerktest.RoundTripper{
+				T:      t,
+				In:     json.RawMessage(`{"public_metadata":{"theme":"dark"}}`),
+				Out:    json.RawMessage(`{"id":"user_123","public_metadata":{"theme":"dark"}}`),
+				Method: http.MethodPatch,
+				Path:   "/v1/users/user_123/metadata",
+			},
+		},
+	}))
+
+	user, err := UpdatePublicMetadata(context.Background(), "user_123", preferences{Theme: "dark"})
+	require.NoError(t, err)
+	prefs, err := clerk.DecodeMetadata[preferences](user.PublicMetadata)
+	require.NoError(t, err)
+	require.Equal(t, "dark", prefs.Theme)
+}
+
+func TestUpdatePrivateMetadata_DeleteKeys(t *testing.T) {
+	clerk.SetBackend(clerk.NewBackend(&clerk.BackendConfig{
+		HTTPClient: &http.Client{
+			Transport: &clerktest.RoundTripper{
+				T:      t,
+				In:     json.RawMessage(`{"private_metadata":{"plan":"pro","trial":null}}`),
+				Out:    json.RawMessage(`{"id":"user_123","private_metadata":{"plan":"pro"}}`),
+				Method: http.MethodPatch,
+				Path:   "/v1/users/user_123/metadata",
+			},
+		},
+	}))
+
+	_, err := UpdatePrivateMetadata(context.Background(), "user_123", clerk.MetadataPatch{}.Set("plan", "pro").Delete("trial"))
+	require.NoError(t, err)
+}
+
+func TestUserClientModifyMetadata(t *testing.T) {
+	t.Parallel()
+	totalGets := 0
+	var updateBody string
+	clerkAPI := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPatch {
+			require.Equal(t, "/users/user_123/metadata", r.URL.Path)
+			body, err := io.ReadAll(r.Body)
+			require.NoError(t, err)
+			updateBody = string(body)
+			_, err = w.Write([]byte(`{"id":"user_123","updated_at":3,"private_metadata":{"count":3,"other":"value"}}`))
+			require.NoError(t, err)
+			return
+		}
+		require.Equal(t, "/users/user_123", r.URL.Path)
+		totalGets++
+		// The user is modified by somebody else between the first two
+		// requests.
+		updatedAt, count := 1, 1
+		if totalGets > 1 {
+			updatedAt, count = 2, 2
+		}
+		_, err := fmt.Fprintf(w, `{"id":"user_123","updated_at":%d,"private_metadata":{"count":%d,"legacy":true,"other":"value"}}`, updatedAt, count)
+		require.NoError(t, err)
+	}))
+	defer clerkAPI.Close()
+	config := &clerk.ClientConfig{}
+	config.HTTPClient = clerkAPI.Client()
+	config.URL = &clerkAPI.URL
+	client := NewClient(config)
+
+	type counter struct {
+		Count  int    `json:"count"`
+		Other  string `json:"other"`
+		Legacy *bool  `json:"legacy,omitempty"`
+	}
+	params := &ModifyMetadataParams{
+		Modify: func(user *clerk.User) error {
+			c, err := clerk.DecodeMetadata[counter](user.PrivateMetadata)
+			if err != nil {
+				return err
+			}
+			c.Count++
+			c.Legacy = nil
+			data, err := json.Marshal(c)
+			user.PrivateMetadata = data
+			return err
+		},
+	}
+	params.MinBackoff = time.Millisecond
+	user, err := client.ModifyMetadata(context.Background(), "user_123", params)
+	require.NoError(t, err)
+	require.Equal(t, int64(3), user.UpdatedAt)
+	// The first attempt conflicted and the second attempt read the
+	// user twice.
+	require.Equal(t, 4, totalGets)
+	require.JSONEq(t, `{"private_metadata":{"count":3,"legacy":null}}`, updateBody)
+}
+
+func TestUserClientModifyMetadata_Conflict(t *testing.T) {
+	t.Parallel()
+	updatedAt := 0
+	clerkAPI := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, http.MethodGet, r.Method)
+		updatedAt++
+		_, err := fmt.Fprintf(w, `{"id":"user_123","updated_at":%d}`, updatedAt)
+		require.NoError(t, err)
+	}))
+	defer clerkAPI.Close()
+	config := &clerk.ClientConfig{}
+	config.HTTPClient = clerkAPI.Client()
+	config.URL = &clerkAPI.URL
+	client := NewClient(config)
+
+	params := &ModifyMetadataParams{
+		Modify: func(user *clerk.User) error {
+			user.PublicMetadata = json.RawMessage(`{"a":1}`)
+			return nil
+		},
+	}
+	params.MaxAttempts = 2
+	params.MinBackoff = time.Millisecond
+	_, err := client.ModifyMetadata(context.Background(), "user_123", params)
+	require.ErrorIs(t, err, clerk.ErrMetadataConflict)
+}
+
+func TestUserClientModifyMetadata_MissingModify(t *testing.T) {
+	t.Parallel()
+	client := NewClient(&clerk.ClientConfig{})
+	_, err := client.ModifyMetadata(context.Background(), "user_123", nil)
+	require.Error(t, err)
+	_, err = client.ModifyMetadata(context.Background(), "user_123", &ModifyMetadataParams{})
+	require.Error(t, err)
+}
+
+func TestUserClientUpdateMetadata_Validation(t *testing.T) {
+	schema, err := clerk.NewMetadataSchema([]byte(`{"type":"object","properties":{"age":{"type":"integer","minimum":0}},"required":["age"]}`))
+	require.NoError(t, err)
+	clerk.RegisterMetadataValidator(clerk.MetadataResourceUser, clerk.MetadataKindPublic, schema)
+	t.Cleanup(func() {
+		clerk.UnregisterMetadataValidators(clerk.MetadataResourceUser, clerk.MetadataKindPublic)
+	})
+
+	totalRequests := 0
+	clerkAPI := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		totalRequests++
+		_, err := w.Write([]byte(`{"id":"user_123"}`))
+		require.NoError(t, err)
+	}))
+	defer clerkAPI.Close()
+	config := &clerk.ClientConfig{}
+	config.HTTPClient = clerkAPI.Client()
+	config.URL = &clerkAPI.URL
+	client := NewClient(config)
+
+	// Invalid metadata are rejected before the request is sent.
+	metadata := json.RawMessage(`{"age":-1}`)
+	_, err = client.UpdateMetadata(context.Background(), "user_123", &UpdateMetadataParams{
+		PublicMetadata: &metadata,
+	})
+	var validationErr *clerk.MetadataValidationError
+	require.ErrorAs(t, err, &validationErr)
+	require.Equal(t, []clerk.MetadataFieldError{{Path: "/age", Message: "must be greater than or equal to 0"}}, validationErr.Errors)
+	require.Equal(t, 0, totalRequests)
+
+	// Partial updates don't need to include required fields, but
+	// complete documents do.
+	metadata = json.RawMessage(`{}`)
+	_, err = client.UpdateMetadata(context.Background(), "user_123", &UpdateMetadataParams{
+		PublicMetadata: &metadata,
+	})
+	require.NoError(t, err)
+	require.Equal(t, 1, totalRequests)
+	_, err = client.Update(context.Background(), "user_123", &UpdateParams{
+		PublicMetadata: &metadata,
+	})
+	require.ErrorAs(t, err, &validationErr)
+	require.Equal(t, 1, totalRequests)
+}
@@ -18,6 +18,34 @@ func Create(ctx context.Context, params *CreateParams) (*clerk.WaitlistEntry, er
 	return getClient().Create(ctx, params)
 }
 
+// Invite creates an invitation for the waitlist entry's email
+// address. The created invitation is available in the Invitation
+// field of the returned entry.
+func Invite(ctx context.Context, id string, params *InviteParams) (*clerk.WaitlistEntry, error) {
+	return getClient().Invite(ctx, id, params)
+}
+
+// Reject rejects the waitlist entry.
+func Reject(ctx context.Context, id string) (*clerk.WaitlistEntry, error) {
+	return getClient().Reject(ctx, id)
+}
+
+// Delete removes the waitlist entry.
+func Delete(ctx context.Context, id string) (*clerk.DeletedResource, error) {
+	return getClient().Delete(ctx, id)
+}
+
+// BulkInvite invites all waitlist entries that match the params in
+// rate-limited batches, and reports the outcome for each entry.
+// The matching entries are listed before the first invitation is
+// sent, so that inviting entries doesn't affect pagination.
+// The returned error is set if the entries cannot be listed or the
+// context is done; in the latter case the results for the entries
+// that were processed so far are returned.
+func BulkInvite(ctx context.Context, params *BulkInviteParams) ([]*BulkInviteResult, error) {
+	return getClient().BulkInvite(ctx, params)
+}
+
 func getClient() *Client {
 	return &Client{
 		Backend: clerk.GetBackend(),
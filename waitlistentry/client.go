@@ -4,6 +4,8 @@ import (
 	"context"
 	"net/http"
 	"net/url"
+	"sync"
+	"time"
 
 	"github.com/clerk/clerk-sdk-go/v2"
 )
@@ -69,3 +71,162 @@ func (c *Client) Create(ctx context.Context, params *CreateParams) (*clerk.Waitl
 	err := c.Backend.Call(ctx, req, invitation)
 	return invitation, err
 }
+
+type InviteParams struct {
+	clerk.APIParams
+	// IgnoreExisting skips the creation of an invitation if there's
+	// already a pending invitation for the email address.
+	IgnoreExisting *bool `json:"ignore_existing,omitempty"`
+}
+
+// Invite creates an invitation for the waitlist entry's email
+// address. The created invitation is available in the Invitation
+// field of the returned entry.
+func (c *Client) Invite(ctx context.Context, id string, params *InviteParams) (*clerk.WaitlistEntry, error) {
+	path, err := clerk.JoinPath(path, id, "/invite")
+	if err != nil {
+		return nil, err
+	}
+	req := clerk.NewAPIRequest(http.MethodPost, path)
+	req.SetParams(params)
+	entry := &clerk.WaitlistEntry{}
+	err = c.Backend.Call(ctx, req, entry)
+	return entry, err
+}
+
+// Reject rejects the waitlist entry.
+func (c *Client) Reject(ctx context.Context, id string) (*clerk.WaitlistEntry, error) {
+	path, err := clerk.JoinPath(path, id, "/reject")
+	if err != nil {
+		return nil, err
+	}
+	req := clerk.NewAPIRequest(http.MethodPost, path)
+	entry := &clerk.WaitlistEntry{}
+	err = c.Backend.Call(ctx, req, entry)
+	return entry, err
+}
+
+// Delete removes the waitlist entry.
+func (c *Client) Delete(ctx context.Context, id string) (*clerk.DeletedResource, error) {
+	path, err := clerk.JoinPath(path, id)
+	if err != nil {
+		return nil, err
+	}
+	req := clerk.NewAPIRequest(http.MethodDelete, path)
+	resource := &clerk.DeletedResource{}
+	err = c.Backend.Call(ctx, req, resource)
+	return resource, err
+}
+
+const (
+	defaultBulkInviteBatchSize = 10
+	defaultBulkInviteInterval  = time.Second
+	bulkInviteListLimit        = 100
+)
+
+type BulkInviteParams struct {
+	// ListParams selects the waitlist entries to invite, for example
+	// by status or query. Limit and Offset are ignored, all matching
+	// entries are invited.
+	ListParams
+	InviteParams
+	// BatchSize is the number of entries that are invited
+	// concurrently. Defaults to 10.
+	BatchSize int
+	// Interval is the minimum duration between the start of two
+	// batches, which limits the rate of requests to the Clerk API.
+	// Defaults to one second.
+	Interval time.Duration
+}
+
+// BulkInviteResult holds the outcome of inviting a single waitlist
+// entry with BulkInvite. Either Invitation or Err is set.
+type BulkInviteResult struct {
+	WaitlistEntry *clerk.WaitlistEntry
+	Invitation    *clerk.Invitation
+	Err           error
+}
+
+// BulkInvite invites all waitlist entries that match the params in
+// rate-limited batches, and reports the outcome for each entry.
+// The matching entries are listed before the first invitation is
+// sent, so that inviting entries doesn't affect pagination.
+// The returned error is set if the entries cannot be listed or the
+// context is done; in the latter case the results for the entries
+// that were processed so far are returned.
+func (c *Client) BulkInvite(ctx context.Context, params *BulkInviteParams) ([]*BulkInviteResult, error) {
+	entries, err := c.listAll(ctx, &params.ListParams)
+	if err != nil {
+		return nil, err
+	}
+	batchSize := params.BatchSize
+	if batchSize <= 0 {
+		batchSize = defaultBulkInviteBatchSize
+	}
+	interval := params.Interval
+	if interval <= 0 {
+		interval = defaultBulkInviteInterval
+	}
+
+	results := make([]*BulkInviteResult, 0, len(entries))
+	for start := 0; start < len(entries); start += batchSize {
+		if start > 0 {
+			select {
+			case <-time.After(interval):
+			case <-ctx.Done():
+				return results, ctx.Err()
+			}
+		}
+		end := start + batchSize
+		if end > len(entries) {
+			end = len(entries)
+		}
+		results = append(results, c.inviteBatch(ctx, entries[start:end], &params.InviteParams)...)
+	}
+	return results, ctx.Err()
+}
+
+// Invites a batch of waitlist entries concurrently.
+func (c *Client) inviteBatch(ctx context.Context, entries []*clerk.WaitlistEntry, params *InviteParams) []*BulkInviteResult {
+	results := make([]*BulkInviteResult, len(entries))
+	var wg sync.WaitGroup
+	for i, entry := range entries {
+		wg.Add(1)
+		go func(i int, entry *clerk.WaitlistEntry) {
+			defer wg.Done()
+			res := &BulkInviteResult{WaitlistEntry: entry}
+			invited, err := c.Invite(ctx, entry.ID, &InviteParams{IgnoreExisting: params.IgnoreExisting})
+			if err != nil {
+				res.Err = err
+			} else {
+				res.WaitlistEntry = invited
+				res.Invitation = invited.Invitation
+			}
+			results[i] = res
+		}(i, entry)
+	}
+	wg.Wait()
+	return results
+}
+
+// Lists all waitlist entries that match the params.
+func (c *Client) listAll(ctx context.Context, params *ListParams) ([]*clerk.WaitlistEntry, error) {
+	var entries []*clerk.WaitlistEntry
+	for {
+		pageParams := &ListParams{
+			OrderBy:  params.OrderBy,
+			Query:    params.Query,
+			Statuses: params.Statuses,
+		}
+		pageParams.Limit = clerk.Int64(bulkInviteListLimit)
+		pageParams.Offset = clerk.Int64(int64(len(entries)))
+		list, err := c.List(ctx, pageParams)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, list.WaitlistEntries...)
+		if len(list.WaitlistEntries) < bulkInviteListLimit || int64(len(entries)) >= list.TotalCount {
+			return entries, nil
+		}
+	}
+}
@@ -0,0 +1,228 @@
+package waitlistentry
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/clerk/clerk-sdk-go/v2"
+	"github.com/clerk/clerk-sdk-go/v2/clerktest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWaitlistEntryClientInvite(t *testing.T) {
+	t.Parallel()
+	id := "wle_123"
+	config := &clerk.ClientConfig{}
+	config.HTTPClient = &http.Client{
+		Transport: &clerktest.RoundTripper{
+			T:      t,
+			In:     json.RawMessage(`{"ignore_existing":true}`),
+			Out:    json.RawMessage(fmt.Sprintf(`{"id":"%s","status":"invited","invitation":{"id":"inv_123","email_address":"foo@bar.com"}}`, id)),
+			Method: http.MethodPost,
+			Path:   "/v1/waitlist_entries/" + id + "/invite",
+		},
+	}
+	client := NewClient(config)
+	entry, err := client.Invite(context.Background(), id, &InviteParams{
+		IgnoreExisting: clerk.Bool(true),
+	})
+	require.NoError(t, err)
+	require.Equal(t, id, entry.ID)
+	require.Equal(t, "invited", entry.Status)
+	require.Equal(t, "inv_123", entry.Invitation.ID)
+}
+
+func TestWaitlistEntryClientReject(t *testing.T) {
+	t.Parallel()
+	id := "wle_123"
+	config := &clerk.ClientConfig{}
+	config.HTTPClient = &http.Client{
+		Transport: &clerktest.RoundTripper{
+			T:      t,
+			Out:    json.RawMessage(fmt.Sprintf(`{"id":"%s","status":"rejected"}`, id)),
+			Method: http.MethodPost,
+			Path:   "/v1/waitlist_entries/" + id + "/reject",
+		},
+	}
+	client := NewClient(config)
+	entry, err := client.Reject(context.Background(), id)
+	require.NoError(t, err)
+	require.Equal(t, id, entry.ID)
+	require.Equal(t, "rejected", entry.Status)
+}
+
+func TestWaitlistEntryClientDelete(t *testing.T) {
+	t.Parallel()
+	id := "wle_123"
+	config := &clerk.ClientConfig{}
+	config.HTTPClient = &http.Client{
+		Transport: &clerktest.RoundTripper{
+			T:      t,
+			Out:    json.RawMessage(fmt.Sprintf(`{"id":"%s","deleted":true}`, id)),
+			Method: http.MethodDelete,
+			Path:   "/v1/waitlist_entries/" + id,
+		},
+	}
+	client := NewClient(config)
+	entry, err := client.Delete(context.Background(), id)
+	require.NoError(t, err)
+	require.Equal(t, id, entry.ID)
+	require.True(t, entry.Deleted)
+}
+
+func TestWaitlistEntryClientDelete_Error(t *testing.T) {
+	t.Parallel()
+	config := &clerk.ClientConfig{}
+	config.HTTPClient = &http.Client{
+		Transport: &clerktest.RoundTripper{
+			T:      t,
+			Status: http.StatusNotFound,
+			Out: json.RawMessage(`{
+  "errors":[{
+		"code":"delete-error-code"
+	}],
+	"clerk_trace_id":"delete-trace-id"
+}`),
+		},
+	}
+	client := NewClient(config)
+	_, err := client.Delete(context.Background(), "wle_123")
+	require.Error(t, err)
+	apiErr, ok := err.(*clerk.APIErrorResponse)
+	require.True(t, ok)
+	require.Equal(t, "delete-trace-id", apiErr.TraceID)
+	require.Equal(t, "delete-error-code", apiErr.Errors[0].Code)
+}
+
+func TestWaitlistEntryClientBulkInvite(t *testing.T) {
+	t.Parallel()
+	total := 150
+	failingID := "wle_7"
+	var mu sync.Mutex
+	var listQueries []string
+	var invited []string
+	clerkAPI := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			require.Equal(t, "/waitlist_entries", r.URL.Path)
+			require.Equal(t, []string{"pending"}, r.URL.Query()["status"])
+			mu.Lock()
+			listQueries = append(listQueries, r.URL.RawQuery)
+			mu.Unlock()
+			var offset, limit int
+			_, err := fmt.Sscan(r.URL.Query().Get("offset"), &offset)
+			require.NoError(t, err)
+			_, err = fmt.Sscan(r.URL.Query().Get("limit"), &limit)
+			require.NoError(t, err)
+			var data []string
+			for i := offset; i < total && i < offset+limit; i++ {
+				data = append(data, fmt.Sprintf(`{"id":"wle_%d","status":"pending"}`, i))
+			}
+			_, _ = w.Write([]byte(fmt.Sprintf(`{"data":[%s],"total_count":%d}`, strings.Join(data, ","), total)))
+			return
+		}
+
+		require.Equal(t, http.MethodPost, r.Method)
+		id := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/waitlist_entries/"), "/invite")
+		if id == failingID {
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			_, _ = w.Write([]byte(`{"errors":[{"code":"invite-error-code"}]}`))
+			return
+		}
+		mu.Lock()
+		invited = append(invited, id)
+		mu.Unlock()
+		_, _ = w.Write([]byte(fmt.Sprintf(`{"id":"%s","status":"invited","invitation":{"id":"inv_%s"}}`, id, id)))
+	}))
+	defer clerkAPI.Close()
+
+	config := &clerk.ClientConfig{}
+	config.HTTPClient = clerkAPI.Client()
+	config.URL = &clerkAPI.URL
+	client := NewClient(config)
+
+	params := &BulkInviteParams{
+		BatchSize: 50,
+		Interval:  time.Millisecond,
+	}
+	params.Statuses = []string{"pending"}
+	results, err := client.BulkInvite(context.Background(), params)
+	require.NoError(t, err)
+	require.Equal(t, 2, len(listQueries))
+	require.Equal(t, total, len(results))
+	require.Equal(t, total-1, len(invited))
+	for i, res := range results {
+		id := fmt.Sprintf("wle_%d", i)
+		require.Equal(t, id, res.WaitlistEntry.ID)
+		if id == failingID {
+			require.Nil(t, res.Invitation)
+			apiErr, ok := res.Err.(*clerk.APIErrorResponse)
+			require.True(t, ok)
+			require.Equal(t, "invite-error-code", apiErr.Errors[0].Code)
+			require.Equal(t, "pending", res.WaitlistEntry.Status)
+			continue
+		}
+		require.NoError(t, res.Err)
+		require.Equal(t, "invited", res.WaitlistEntry.Status)
+		require.Equal(t, "inv_"+id, res.Invitation.ID)
+	}
+}
+
+func TestWaitlistEntryClientBulkInvite_ContextCanceled(t *testing.T) {
+	t.Parallel()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	clerkAPI := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			_, _ = w.Write([]byte(`{"data":[{"id":"wle_1"},{"id":"wle_2"}],"total_count":2}`))
+			return
+		}
+		// Cancel the context after the first batch.
+		cancel()
+		_, _ = w.Write([]byte(`{"id":"wle_1","status":"invited","invitation":{"id":"inv_1"}}`))
+	}))
+	defer clerkAPI.Close()
+
+	config := &clerk.ClientConfig{}
+	config.HTTPClient = clerkAPI.Client()
+	config.URL = &clerkAPI.URL
+	client := NewClient(config)
+
+	results, err := client.BulkInvite(ctx, &BulkInviteParams{
+		BatchSize: 1,
+		Interval:  time.Hour,
+	})
+	require.ErrorIs(t, err, context.Canceled)
+	require.Equal(t, 1, len(results))
+}
+
+func TestWaitlistEntryClientBulkInvite_ContextCanceledLastBatch(t *testing.T) {
+	t.Parallel()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	clerkAPI := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			_, _ = w.Write([]byte(`{"data":[{"id":"wle_1"}],"total_count":1}`))
+			return
+		}
+		// Cancel the context during the only batch.
+		cancel()
+		_, _ = w.Write([]byte(`{"id":"wle_1","status":"invited","invitation":{"id":"inv_1"}}`))
+	}))
+	defer clerkAPI.Close()
+
+	config := &clerk.ClientConfig{}
+	config.HTTPClient = clerkAPI.Client()
+	config.URL = &clerkAPI.URL
+	client := NewClient(config)
+
+	results, err := client.BulkInvite(ctx, &BulkInviteParams{})
+	require.ErrorIs(t, err, context.Canceled)
+	require.Equal(t, 1, len(results))
+}
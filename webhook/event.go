@@ -0,0 +1,205 @@
+package webhook
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/clerk/clerk-sdk-go/v2"
+)
+
+// Event types for the webhook events that can be decoded into typed
+// events.
+const (
+	EventUserCreated = "user.created"
+	EventUserUpdated = "user.updated"
+	EventUserDeleted = "user.deleted"
+
+	EventOrganizationCreated = "organization.created"
+	EventOrganizationUpdated = "organization.updated"
+	EventOrganizationDeleted = "organization.deleted"
+
+	EventOrganizationMembershipCreated = "organizationMembership.created"
+	EventOrganizationMembershipUpdated = "organizationMembership.updated"
+	EventOrganizationMembershipDeleted = "organizationMembership.deleted"
+
+	EventOrganizationInvitationCreated  = "organizationInvitation.created"
+	EventOrganizationInvitationAccepted = "organizationInvitation.accepted"
+	EventOrganizationInvitationRevoked  = "organizationInvitation.revoked"
+
+	EventSessionCreated = "session.created"
+	EventSessionEnded   = "session.ended"
+	EventSessionRemoved = "session.removed"
+	EventSessionRevoked = "session.revoked"
+
+	EventEmailCreated = "email.created"
+
+	EventWaitlistEntryCreated = "waitlistEntry.created"
+	EventWaitlistEntryUpdated = "waitlistEntry.updated"
+)
+
+// Event is the envelope of all webhook events. The Data holds the
+// event's resource and its schema depends on the event Type.
+type Event struct {
+	Object     string          `json:"object"`
+	Type       string          `json:"type"`
+	InstanceID string          `json:"instance_id,omitempty"`
+	Timestamp  int64           `json:"timestamp"`
+	Data       json.RawMessage `json:"data"`
+	// MessageID is the unique identifier of the delivered message,
+	// taken from the svix-id header. It's the same for all delivery
+	// attempts of an event.
+	MessageID string `json:"-"`
+}
+
+// ParseEvent decodes the webhook payload into an Event.
+// The payload should be verified with Verify first.
+func ParseEvent(payload []byte) (*Event, error) {
+	event := &Event{}
+	err := json.Unmarshal(payload, event)
+	if err != nil {
+		return nil, err
+	}
+	if event.Type == "" {
+		return nil, fmt.Errorf("missing webhook event type")
+	}
+	return event, nil
+}
+
+// UserEvent is sent for user.created and user.updated events.
+type UserEvent struct {
+	*Event
+	User *clerk.User
+}
+
+// DeletedEvent is sent for events that delete a resource, like
+// user.deleted and organization.deleted. The resource is no longer
+// available, so only its identifier is included.
+type DeletedEvent struct {
+	*Event
+	DeletedResource *clerk.DeletedResource
+}
+
+// OrganizationEvent is sent for organization.created and
+// organization.updated events.
+type OrganizationEvent struct {
+	*Event
+	Organization *clerk.Organization
+}
+
+// OrganizationMembershipEvent is sent for all organizationMembership
+// events.
+type OrganizationMembershipEvent struct {
+	*Event
+	OrganizationMembership *clerk.OrganizationMembership
+}
+
+// OrganizationInvitationEvent is sent for all organizationInvitation
+// events.
+type OrganizationInvitationEvent struct {
+	*Event
+	OrganizationInvitation *clerk.OrganizationInvitation
+}
+
+// SessionEvent is sent for all session events.
+type SessionEvent struct {
+	*Event
+	Session *clerk.Session
+}
+
+// EmailEvent is sent for email.created events.
+type EmailEvent struct {
+	*Event
+	Email *Email
+}
+
+// WaitlistEntryEvent is sent for all waitlistEntry events.
+type WaitlistEntryEvent struct {
+	*Event
+	WaitlistEntry *clerk.WaitlistEntry
+}
+
+// Email describes an email message that Clerk sends, or that needs
+// to be delivered by the application if delivery by Clerk is
+// disabled.
+type Email struct {
+	Object           string          `json:"object"`
+	ID               string          `json:"id"`
+	Slug             *string         `json:"slug"`
+	FromEmailName    string          `json:"from_email_name"`
+	ToEmailAddress   string          `json:"to_email_address"`
+	EmailAddressID   *string         `json:"email_address_id"`
+	UserID           *string         `json:"user_id"`
+	Subject          string          `json:"subject"`
+	Body             string          `json:"body"`
+	BodyPlain        *string         `json:"body_plain"`
+	Status           string          `json:"status"`
+	DeliveredByClerk bool            `json:"delivered_by_clerk"`
+	Data             json.RawMessage `json:"data"`
+}
+
+// UserEvent decodes the event data into a UserEvent.
+func (e *Event) UserEvent() (*UserEvent, error) {
+	user := &clerk.User{}
+	err := e.decode(user)
+	return &UserEvent{Event: e, User: user}, err
+}
+
+// DeletedEvent decodes the event data into a DeletedEvent.
+func (e *Event) DeletedEvent() (*DeletedEvent, error) {
+	resource := &clerk.DeletedResource{}
+	err := e.decode(resource)
+	return &DeletedEvent{Event: e, DeletedResource: resource}, err
+}
+
+// OrganizationEvent decodes the event data into an
+// OrganizationEvent.
+func (e *Event) OrganizationEvent() (*OrganizationEvent, error) {
+	organization := &clerk.Organization{}
+	err := e.decode(organization)
+	return &OrganizationEvent{Event: e, Organization: organization}, err
+}
+
+// OrganizationMembershipEvent decodes the event data into an
+// OrganizationMembershipEvent.
+func (e *Event) OrganizationMembershipEvent() (*OrganizationMembershipEvent, error) {
+	membership := &clerk.OrganizationMembership{}
+	err := e.decode(membership)
+	return &OrganizationMembershipEvent{Event: e, OrganizationMembership: membership}, err
+}
+
+// OrganizationInvitationEvent decodes the event data into an
+// OrganizationInvitationEvent.
+func (e *Event) OrganizationInvitationEvent() (*OrganizationInvitationEvent, error) {
+	invitation := &clerk.OrganizationInvitation{}
+	err := e.decode(invitation)
+	return &OrganizationInvitationEvent{Event: e, OrganizationInvitation: invitation}, err
+}
+
+// SessionEvent decodes the event data into a SessionEvent.
+func (e *Event) SessionEvent() (*SessionEvent, error) {
+	session := &clerk.Session{}
+	err := e.decode(session)
+	return &SessionEvent{Event: e, Session: session}, err
+}
+
+// EmailEvent decodes the event data into an EmailEvent.
+func (e *Event) EmailEvent() (*EmailEvent, error) {
+	email := &Email{}
+	err := e.decode(email)
+	return &EmailEvent{Event: e, Email: email}, err
+}
+
+// WaitlistEntryEvent decodes the event data into a
+// WaitlistEntryEvent.
+func (e *Event) WaitlistEntryEvent() (*WaitlistEntryEvent, error) {
+	entry := &clerk.WaitlistEntry{}
+	err := e.decode(entry)
+	return &WaitlistEntryEvent{Event: e, WaitlistEntry: entry}, err
+}
+
+func (e *Event) decode(v any) error {
+	if len(e.Data) == 0 {
+		return fmt.Errorf("missing data for webhook event %s", e.Type)
+	}
+	return json.Unmarshal(e.Data, v)
+}
@@ -0,0 +1,37 @@
+package webhook
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseEvent(t *testing.T) {
+	t.Parallel()
+	event, err := ParseEvent([]byte(`{"object":"event","type":"email.created","instance_id":"ins_123","timestamp":1654012591835,"data":{"object":"email","id":"ema_123","to_email_address":"foo@bar.com","status":"queued","delivered_by_clerk":false}}`))
+	require.NoError(t, err)
+	require.Equal(t, EventEmailCreated, event.Type)
+	require.Equal(t, "ins_123", event.InstanceID)
+	require.Equal(t, int64(1654012591835), event.Timestamp)
+
+	emailEvent, err := event.EmailEvent()
+	require.NoError(t, err)
+	require.Equal(t, "ema_123", emailEvent.Email.ID)
+	require.Equal(t, "foo@bar.com", emailEvent.Email.ToEmailAddress)
+	require.False(t, emailEvent.Email.DeliveredByClerk)
+
+	event, err = ParseEvent([]byte(`{"object":"event","type":"waitlistEntry.created","data":{"object":"waitlist_entry","id":"wle_123","email_address":"foo@bar.com","status":"pending"}}`))
+	require.NoError(t, err)
+	entryEvent, err := event.WaitlistEntryEvent()
+	require.NoError(t, err)
+	require.Equal(t, "wle_123", entryEvent.WaitlistEntry.ID)
+	require.Equal(t, "pending", entryEvent.WaitlistEntry.Status)
+
+	_, err = ParseEvent([]byte(`{"object":"event","data":{}}`))
+	require.Error(t, err)
+
+	event, err = ParseEvent([]byte(`{"object":"event","type":"organization.created"}`))
+	require.NoError(t, err)
+	_, err = event.OrganizationEvent()
+	require.Error(t, err)
+}
@@ -0,0 +1,327 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/clerk/clerk-sdk-go/v2"
+)
+
+// EventHandlerFunc handles a webhook event. Returning an error
+// results in a 500 Internal Server Error response, so that the event
+// delivery is retried.
+type EventHandlerFunc func(ctx context.Context, event *Event) error
+
+// DefaultMaxPayloadSize is the default maximum size of a webhook
+// delivery payload that the Router accepts.
+const DefaultMaxPayloadSize int64 = 1 << 20
+
+type RouterParams struct {
+	// Secret is the signing secret of the webhook endpoint. Required.
+	Secret string
+	// Tolerance is the allowed difference between the message
+	// timestamp and the current time. Defaults to DefaultTolerance.
+	Tolerance time.Duration
+	// Clock can be used to keep track of time and will replace usage
+	// of the [time] package.
+	Clock clerk.Clock
+	// Store enables deduplication of webhook deliveries. Messages are
+	// claimed in the Store while their handler runs and marked as
+	// processed after it succeeds, and later deliveries of the same
+	// message are acknowledged without calling the handler again.
+	Store Store
+	// ClaimTTL is the duration for which a message is claimed in the
+	// Store while its handler runs. Handlers that take longer might
+	// run concurrently with a redelivery of the same message.
+	// Defaults to DefaultClaimTTL.
+	ClaimTTL time.Duration
+	// MaxPayloadSize is the maximum size of a delivery payload in
+	// bytes. Larger deliveries get a 413 Request Entity Too Large
+	// response. Defaults to DefaultMaxPayloadSize.
+	MaxPayloadSize int64
+}
+
+// Router is an http.Handler which verifies webhook deliveries and
+// dispatches the events to the handlers that are registered for
+// their event type.
+//
+//	router := webhook.NewRouter(&webhook.RouterParams{Secret: "whsec_..."})
+//	router.OnUser(webhook.EventUserCreated, func(ctx context.Context, event *webhook.UserEvent) error {
+//		return createAccount(ctx, event.User)
+//	})
+//	http.Handle("/webhooks/clerk", router)
+//
+// The Router responds with 400 Bad Request for deliveries that
+// cannot be verified or parsed, including event data that cannot be
+// decoded by the typed handlers, 413 Request Entity Too Large for
+// payloads over the MaxPayloadSize, 500 Internal Server Error if the
+// event handler fails and 200 OK otherwise. Events without a
+// registered handler are acknowledged, unless a fallback handler is
+// set with Fallback.
+//
+// If a Store is configured, duplicate deliveries of processed
+// messages are acknowledged with 200 OK and deliveries of a message
+// that is claimed by another delivery get a 409 Conflict response,
+// so that they are retried later. With a shared Store, this holds
+// across all the processes that serve the webhook endpoint. Failures of the Store result in a 500
+// Internal Server Error response.
+type Router struct {
+	params *RouterParams
+
+	mu       sync.RWMutex
+	handlers map[string]EventHandlerFunc
+	fallback EventHandlerFunc
+}
+
+// NewRouter returns a Router which verifies deliveries with the
+// provided params. A Router without params rejects all deliveries,
+// since they cannot be verified without a Secret.
+func NewRouter(params *RouterParams) *Router {
+	if params == nil {
+		params = &RouterParams{}
+	}
+	return &Router{
+		params:   params,
+		handlers: map[string]EventHandlerFunc{},
+	}
+}
+
+// On registers a handler for the event type. The event data can be
+// decoded with the typed Event methods.
+// Registering a handler for an event type replaces any existing
+// handler.
+func (router *Router) On(eventType string, fn EventHandlerFunc) {
+	router.mu.Lock()
+	defer router.mu.Unlock()
+	router.handlers[eventType] = fn
+}
+
+// Fallback registers a handler for events without a registered
+// handler, including event types that are not known to the SDK.
+func (router *Router) Fallback(fn EventHandlerFunc) {
+	router.mu.Lock()
+	defer router.mu.Unlock()
+	router.fallback = fn
+}
+
+// OnUser registers a handler for user.created or user.updated
+// events.
+func (router *Router) OnUser(eventType string, fn func(context.Context, *UserEvent) error) {
+	router.On(eventType, func(ctx context.Context, event *Event) error {
+		typed, err := event.UserEvent()
+		if err != nil {
+			return &eventDataError{err: err}
+		}
+		return fn(ctx, typed)
+	})
+}
+
+// OnDeleted registers a handler for events that delete a resource,
+// like user.deleted or organization.deleted.
+func (router *Router) OnDeleted(eventType string, fn func(context.Context, *DeletedEvent) error) {
+	router.On(eventType, func(ctx context.Context, event *Event) error {
+		typed, err := event.DeletedEvent()
+		if err != nil {
+			return &eventDataError{err: err}
+		}
+		return fn(ctx, typed)
+	})
+}
+
+// OnOrganization registers a handler for organization.created or
+// organization.updated events.
+func (router *Router) OnOrganization(eventType string, fn func(context.Context, *OrganizationEvent) error) {
+	router.On(eventType, func(ctx context.Context, event *Event) error {
+		typed, err := event.OrganizationEvent()
+		if err != nil {
+			return &eventDataError{err: err}
+		}
+		return fn(ctx, typed)
+	})
+}
+
+// OnOrganizationMembership registers a handler for
+// organizationMembership events.
+func (router *Router) OnOrganizationMembership(eventType string, fn func(context.Context, *OrganizationMembershipEvent) error) {
+	router.On(eventType, func(ctx context.Context, event *Event) error {
+		typed, err := event.OrganizationMembershipEvent()
+		if err != nil {
+			return &eventDataError{err: err}
+		}
+		return fn(ctx, typed)
+	})
+}
+
+// OnOrganizationInvitation registers a handler for
+// organizationInvitation events.
+func (router *Router) OnOrganizationInvitation(eventType string, fn func(context.Context, *OrganizationInvitationEvent) error) {
+	router.On(eventType, func(ctx context.Context, event *Event) error {
+		typed, err := event.OrganizationInvitationEvent()
+		if err != nil {
+			return &eventDataError{err: err}
+		}
+		return fn(ctx, typed)
+	})
+}
+
+// OnSession registers a handler for session events.
+func (router *Router) OnSession(eventType string, fn func(context.Context, *SessionEvent) error) {
+	router.On(eventType, func(ctx context.Context, event *Event) error {
+		typed, err := event.SessionEvent()
+		if err != nil {
+			return &eventDataError{err: err}
+		}
+		return fn(ctx, typed)
+	})
+}
+
+// OnEmail registers a handler for email.created events.
+func (router *Router) OnEmail(eventType string, fn func(context.Context, *EmailEvent) error) {
+	router.On(eventType, func(ctx context.Context, event *Event) error {
+		typed, err := event.EmailEvent()
+		if err != nil {
+			return &eventDataError{err: err}
+		}
+		return fn(ctx, typed)
+	})
+}
+
+// OnWaitlistEntry registers a handler for waitlistEntry events.
+func (router *Router) OnWaitlistEntry(eventType string, fn func(context.Context, *WaitlistEntryEvent) error) {
+	router.On(eventType, func(ctx context.Context, event *Event) error {
+		typed, err := event.WaitlistEntryEvent()
+		if err != nil {
+			return &eventDataError{err: err}
+		}
+		return fn(ctx, typed)
+	})
+}
+
+// ServeHTTP verifies the webhook delivery and dispatches the event.
+func (router *Router) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	maxPayloadSize := router.params.MaxPayloadSize
+	if maxPayloadSize <= 0 {
+		maxPayloadSize = DefaultMaxPayloadSize
+	}
+	payload, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxPayloadSize))
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			w.WriteHeader(http.StatusRequestEntityTooLarge)
+			return
+		}
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	err = Verify(&VerifyParams{
+		Payload:   payload,
+		Header:    r.Header,
+		Secret:    router.params.Secret,
+		Tolerance: router.params.Tolerance,
+		Clock:     router.params.Clock,
+	})
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	event, err := ParseEvent(payload)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	event.MessageID = r.Header.Get(HeaderID)
+
+	w.WriteHeader(router.process(r.Context(), event))
+}
+
+// Dispatches the event, deduplicating deliveries if there's a Store
+// configured. Returns the HTTP status code for the response.
+func (router *Router) process(ctx context.Context, event *Event) int {
+	store := router.params.Store
+	if store == nil {
+		return dispatchStatus(router.Dispatch(ctx, event))
+	}
+
+	claimTTL := router.params.ClaimTTL
+	if claimTTL <= 0 {
+		claimTTL = DefaultClaimTTL
+	}
+	claimed, err := store.Claim(ctx, event.MessageID, claimTTL)
+	if err != nil {
+		return http.StatusInternalServerError
+	}
+	// The message is checked while it's claimed, since it's marked as
+	// processed before the claim is released.
+	processed, err := store.IsProcessed(ctx, event.MessageID)
+	if claimed {
+		defer func() {
+			_ = store.Release(ctx, event.MessageID)
+		}()
+	}
+	if err != nil {
+		return http.StatusInternalServerError
+	}
+	if processed {
+		return http.StatusOK
+	}
+	if !claimed {
+		return http.StatusConflict
+	}
+	err = router.Dispatch(ctx, event)
+	if err != nil {
+		return dispatchStatus(err)
+	}
+	err = store.MarkProcessed(ctx, event.MessageID)
+	if err != nil {
+		return http.StatusInternalServerError
+	}
+	return http.StatusOK
+}
+
+// Returns the HTTP status code for the outcome of Dispatch. Event
+// data that cannot be decoded is rejected, since redelivering the
+// same payload would fail again.
+func dispatchStatus(err error) int {
+	if err == nil {
+		return http.StatusOK
+	}
+	var dataErr *eventDataError
+	if errors.As(err, &dataErr) {
+		return http.StatusBadRequest
+	}
+	return http.StatusInternalServerError
+}
+
+// Returned by the typed handlers when the event data cannot be
+// decoded.
+type eventDataError struct {
+	err error
+}
+
+func (e *eventDataError) Error() string {
+	return fmt.Sprintf("invalid event data: %s", e.err)
+}
+
+func (e *eventDataError) Unwrap() error {
+	return e.err
+}
+
+// Dispatch calls the handler that is registered for the event type,
+// or the fallback handler. Events without any handler are ignored.
+func (router *Router) Dispatch(ctx context.Context, event *Event) error {
+	router.mu.RLock()
+	fn, ok := router.handlers[event.Type]
+	if !ok {
+		fn = router.fallback
+	}
+	router.mu.RUnlock()
+	if fn == nil {
+		return nil
+	}
+	return fn(ctx, event)
+}
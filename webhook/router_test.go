@@ -0,0 +1,148 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// Creates a signed webhook delivery request for the payload.
+func newSignedRequest(t *testing.T, msgID, payload string) *http.Request {
+	t.Helper()
+	key, err := decodeSecret(testSecret)
+	require.NoError(t, err)
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	signature := base64.StdEncoding.EncodeToString(Sign(key, msgID, timestamp, []byte(payload)))
+
+	req := httptest.NewRequest(http.MethodPost, "/webhooks", bytes.NewReader([]byte(payload)))
+	req.Header.Set(HeaderID, msgID)
+	req.Header.Set(HeaderTimestamp, timestamp)
+	req.Header.Set(HeaderSignature, "v1,"+signature)
+	return req
+}
+
+func TestRouter(t *testing.T) {
+	t.Parallel()
+	router := NewRouter(&RouterParams{Secret: testSecret})
+	var received []string
+	router.OnUser(EventUserCreated, func(_ context.Context, event *UserEvent) error {
+		require.Equal(t, "msg_1", event.MessageID)
+		received = append(received, event.Type+":"+event.User.ID)
+		return nil
+	})
+	router.OnDeleted(EventUserDeleted, func(_ context.Context, event *DeletedEvent) error {
+		require.True(t, event.DeletedResource.Deleted)
+		received = append(received, event.Type+":"+event.DeletedResource.ID)
+		return nil
+	})
+	router.OnOrganizationMembership(EventOrganizationMembershipCreated, func(_ context.Context, event *OrganizationMembershipEvent) error {
+		return fmt.Errorf("handler failed")
+	})
+
+	for _, tc := range []struct {
+		msgID   string
+		payload string
+		want    int
+	}{
+		{
+			msgID:   "msg_1",
+			payload: `{"object":"event","type":"user.created","data":{"object":"user","id":"user_123"}}`,
+			want:    http.StatusOK,
+		},
+		{
+			msgID:   "msg_2",
+			payload: `{"object":"event","type":"user.deleted","data":{"object":"user","id":"user_123","deleted":true}}`,
+			want:    http.StatusOK,
+		},
+		{
+			msgID:   "msg_3",
+			payload: `{"object":"event","type":"organizationMembership.created","data":{"object":"organization_membership","id":"orgmem_123"}}`,
+			want:    http.StatusInternalServerError,
+		},
+		{
+			msgID:   "msg_4",
+			payload: `{"object":"event","type":"unknown.event","data":{}}`,
+			want:    http.StatusOK,
+		},
+		{
+			msgID:   "msg_5",
+			payload: `not json`,
+			want:    http.StatusBadRequest,
+		},
+		{
+			msgID:   "msg_6",
+			payload: `{"object":"event","type":"user.created","data":{"object":"user","id":123}}`,
+			want:    http.StatusBadRequest,
+		},
+	} {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, newSignedRequest(t, tc.msgID, tc.payload))
+		require.Equal(t, tc.want, w.Code, tc.payload)
+	}
+	require.Equal(t, []string{"user.created:user_123", "user.deleted:user_123"}, received)
+
+	// Deliveries with invalid signatures are rejected.
+	req := newSignedRequest(t, "msg_7", `{"object":"event","type":"user.created","data":{}}`)
+	req.Header.Set(HeaderSignature, "v1,invalid")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestRouter_Fallback(t *testing.T) {
+	t.Parallel()
+	router := NewRouter(&RouterParams{Secret: testSecret})
+	var fallbackTypes []string
+	router.Fallback(func(_ context.Context, event *Event) error {
+		fallbackTypes = append(fallbackTypes, event.Type)
+		return nil
+	})
+	router.OnSession(EventSessionCreated, func(_ context.Context, event *SessionEvent) error {
+		require.Equal(t, "sess_123", event.Session.ID)
+		return nil
+	})
+
+	for _, payload := range []string{
+		`{"object":"event","type":"session.created","data":{"object":"session","id":"sess_123"}}`,
+		`{"object":"event","type":"sms.created","data":{"object":"sms_message"}}`,
+	} {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, newSignedRequest(t, "msg_1", payload))
+		require.Equal(t, http.StatusOK, w.Code)
+	}
+	require.Equal(t, []string{"sms.created"}, fallbackTypes)
+}
+
+func TestRouter_MaxPayloadSize(t *testing.T) {
+	t.Parallel()
+	router := NewRouter(&RouterParams{Secret: testSecret, MaxPayloadSize: 64})
+	router.On(EventUserCreated, func(context.Context, *Event) error {
+		return nil
+	})
+
+	payload := `{"object":"event","type":"user.created","data":{"object":"user","id":"user_123"}}`
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, newSignedRequest(t, "msg_1", payload))
+	require.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+
+	router = NewRouter(&RouterParams{Secret: testSecret})
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, newSignedRequest(t, "msg_1", payload))
+	require.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestRouter_NilParams(t *testing.T) {
+	t.Parallel()
+	router := NewRouter(nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, newSignedRequest(t, "msg_1", `{"object":"event","type":"user.created","data":{}}`))
+	require.Equal(t, http.StatusBadRequest, w.Code)
+}
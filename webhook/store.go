@@ -0,0 +1,134 @@
+package webhook
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/clerk/clerk-sdk-go/v2"
+)
+
+// DefaultStoreTTL is the default duration for which processed
+// messages are remembered. It covers the complete retry schedule of
+// failed webhook deliveries.
+const DefaultStoreTTL = 72 * time.Hour
+
+// DefaultClaimTTL is the default duration for which a message is
+// claimed by a delivery. Claims of deliveries that don't finish in
+// time, for example because the process crashed, expire after this
+// duration, so that the message can be processed again.
+const DefaultClaimTTL = 5 * time.Minute
+
+// Store keeps track of the webhook messages that have been
+// processed, so that duplicate deliveries of the same message can
+// be detected. Messages are identified by the svix-id header.
+// Implementations must be safe for concurrent use. Use a shared
+// store, like a database or cache, if the webhook endpoint is served
+// by multiple processes.
+//
+// Before a message is processed, it's claimed with Claim, so that
+// concurrent deliveries of the same message, possibly to different
+// processes, don't run the handler at the same time.
+type Store interface {
+	// Claim atomically claims the message for processing until the
+	// claim is released or the ttl expires. It reports false if the
+	// message is already claimed.
+	Claim(ctx context.Context, messageID string, ttl time.Duration) (bool, error)
+	// Release releases the claim of the message.
+	Release(ctx context.Context, messageID string) error
+	// IsProcessed reports whether the message has been processed.
+	IsProcessed(ctx context.Context, messageID string) (bool, error)
+	// MarkProcessed records that the message has been processed.
+	MarkProcessed(ctx context.Context, messageID string) error
+}
+
+type MemoryStoreParams struct {
+	// TTL is the duration for which processed messages are
+	// remembered. Defaults to DefaultStoreTTL.
+	TTL time.Duration
+	// Clock can be used to keep track of time and will replace usage
+	// of the [time] package.
+	Clock clerk.Clock
+}
+
+// MemoryStore is a Store which keeps processed messages in memory
+// until their TTL expires. Claims are only visible to the process,
+// so a MemoryStore deduplicates deliveries to a single process.
+type MemoryStore struct {
+	ttl   time.Duration
+	clock clerk.Clock
+
+	mu        sync.Mutex
+	entries   map[string]time.Time
+	claims    map[string]time.Time
+	lastPurge time.Time
+}
+
+// NewMemoryStore returns a MemoryStore configured with the provided
+// params.
+func NewMemoryStore(params *MemoryStoreParams) *MemoryStore {
+	if params == nil {
+		params = &MemoryStoreParams{}
+	}
+	store := &MemoryStore{
+		ttl:     params.TTL,
+		clock:   params.Clock,
+		entries: map[string]time.Time{},
+		claims:  map[string]time.Time{},
+	}
+	if store.ttl == 0 {
+		store.ttl = DefaultStoreTTL
+	}
+	if store.clock == nil {
+		store.clock = clerk.NewClock()
+	}
+	return store
+}
+
+// Claim claims the message, unless it has a claim that hasn't
+// expired.
+func (s *MemoryStore) Claim(_ context.Context, messageID string, ttl time.Duration) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := s.clock.Now()
+	if expiresAt, ok := s.claims[messageID]; ok && expiresAt.After(now) {
+		return false, nil
+	}
+	s.claims[messageID] = now.Add(ttl)
+	return true, nil
+}
+
+// Release releases the claim of the message.
+func (s *MemoryStore) Release(_ context.Context, messageID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.claims, messageID)
+	return nil
+}
+
+// IsProcessed reports whether the message has been marked as
+// processed and its entry has not expired.
+func (s *MemoryStore) IsProcessed(_ context.Context, messageID string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	expiresAt, ok := s.entries[messageID]
+	return ok && expiresAt.After(s.clock.Now()), nil
+}
+
+// MarkProcessed records the message as processed. Expired entries
+// are purged at most once per minute.
+func (s *MemoryStore) MarkProcessed(_ context.Context, messageID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := s.clock.Now()
+	if now.Sub(s.lastPurge) >= time.Minute {
+		for id, expiresAt := range s.entries {
+			if !expiresAt.After(now) {
+				delete(s.entries, id)
+			}
+		}
+		s.lastPurge = now
+	}
+	s.entries[messageID] = now.Add(s.ttl)
+	return nil
+}
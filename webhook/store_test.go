@@ -0,0 +1,151 @@
+package webhook
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/clerk/clerk-sdk-go/v2/clerktest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryStore(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	clock := clerktest.NewClockAt(time.Now().UTC())
+	store := NewMemoryStore(&MemoryStoreParams{TTL: time.Hour, Clock: clock})
+
+	processed, err := store.IsProcessed(ctx, "msg_1")
+	require.NoError(t, err)
+	require.False(t, processed)
+
+	require.NoError(t, store.MarkProcessed(ctx, "msg_1"))
+	processed, err = store.IsProcessed(ctx, "msg_1")
+	require.NoError(t, err)
+	require.True(t, processed)
+
+	clock.Advance(2 * time.Hour)
+	processed, err = store.IsProcessed(ctx, "msg_1")
+	require.NoError(t, err)
+	require.False(t, processed)
+
+	// Expired entries are purged.
+	require.NoError(t, store.MarkProcessed(ctx, "msg_2"))
+	require.Equal(t, 1, len(store.entries))
+}
+
+func TestMemoryStore_Claim(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	clock := clerktest.NewClockAt(time.Now().UTC())
+	store := NewMemoryStore(&MemoryStoreParams{Clock: clock})
+
+	claimed, err := store.Claim(ctx, "msg_1", time.Minute)
+	require.NoError(t, err)
+	require.True(t, claimed)
+	claimed, err = store.Claim(ctx, "msg_1", time.Minute)
+	require.NoError(t, err)
+	require.False(t, claimed)
+
+	// Released claims can be claimed again.
+	require.NoError(t, store.Release(ctx, "msg_1"))
+	claimed, err = store.Claim(ctx, "msg_1", time.Minute)
+	require.NoError(t, err)
+	require.True(t, claimed)
+
+	// Claims expire after their ttl.
+	clock.Advance(2 * time.Minute)
+	claimed, err = store.Claim(ctx, "msg_1", time.Minute)
+	require.NoError(t, err)
+	require.True(t, claimed)
+}
+
+func TestRouter_Deduplication(t *testing.T) {
+	t.Parallel()
+	router := NewRouter(&RouterParams{
+		Secret: testSecret,
+		Store:  NewMemoryStore(nil),
+	})
+	calls := map[string]int{}
+	router.OnUser(EventUserCreated, func(_ context.Context, event *UserEvent) error {
+		calls[event.MessageID]++
+		if event.User.ID == "user_fail" && calls[event.MessageID] == 1 {
+			return fmt.Errorf("handler failed")
+		}
+		return nil
+	})
+
+	for _, tc := range []struct {
+		msgID string
+		user  string
+		want  int
+	}{
+		{msgID: "msg_1", user: "user_123", want: http.StatusOK},
+		// Duplicate deliveries are acknowledged without calling the
+		// handler.
+		{msgID: "msg_1", user: "user_123", want: http.StatusOK},
+		// Failed events are not marked as processed, so they are
+		// retried.
+		{msgID: "msg_2", user: "user_fail", want: http.StatusInternalServerError},
+		{msgID: "msg_2", user: "user_fail", want: http.StatusOK},
+		{msgID: "msg_2", user: "user_fail", want: http.StatusOK},
+	} {
+		payload := fmt.Sprintf(`{"object":"event","type":"user.created","data":{"object":"user","id":%q}}`, tc.user)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, newSignedRequest(t, tc.msgID, payload))
+		require.Equal(t, tc.want, w.Code)
+	}
+	require.Equal(t, map[string]int{"msg_1": 1, "msg_2": 2}, calls)
+}
+
+func TestRouter_DeduplicationInFlight(t *testing.T) {
+	t.Parallel()
+	// Two routers with a shared store, like the replicas of a
+	// webhook endpoint.
+	store := NewMemoryStore(nil)
+	router := NewRouter(&RouterParams{
+		Secret: testSecret,
+		Store:  store,
+	})
+	replica := NewRouter(&RouterParams{
+		Secret: testSecret,
+		Store:  store,
+	})
+	started := make(chan struct{})
+	done := make(chan struct{})
+	router.OnUser(EventUserCreated, func(_ context.Context, _ *UserEvent) error {
+		close(started)
+		<-done
+		return nil
+	})
+	payload := `{"object":"event","type":"user.created","data":{"object":"user","id":"user_123"}}`
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	first := httptest.NewRecorder()
+	go func() {
+		defer wg.Done()
+		router.ServeHTTP(first, newSignedRequest(t, "msg_1", payload))
+	}()
+	<-started
+
+	// A concurrent delivery of the same message is rejected, so that
+	// it's retried later.
+	w := httptest.NewRecorder()
+	replica.ServeHTTP(w, newSignedRequest(t, "msg_1", payload))
+	require.Equal(t, http.StatusConflict, w.Code)
+
+	close(done)
+	wg.Wait()
+	require.Equal(t, http.StatusOK, first.Code)
+
+	// Once the message is processed, later deliveries are
+	// acknowledged.
+	w = httptest.NewRecorder()
+	replica.ServeHTTP(w, newSignedRequest(t, "msg_1", payload))
+	require.Equal(t, http.StatusOK, w.Code)
+}
@@ -0,0 +1,166 @@
+// Package webhook provides verification for Clerk webhook deliveries.
+// Clerk webhooks are delivered by Svix and signed with the signing
+// secret of the webhook endpoint, which has the "whsec_" prefix.
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/clerk/clerk-sdk-go/v2"
+)
+
+const (
+	// HeaderID is the header that holds the unique message ID. The ID
+	// is the same when a message is retried.
+	HeaderID = "svix-id"
+	// HeaderTimestamp is the header that holds the time when the
+	// message was sent, in seconds since the epoch.
+	HeaderTimestamp = "svix-timestamp"
+	// HeaderSignature is the header that holds a space delimited list
+	// of signatures.
+	HeaderSignature = "svix-signature"
+
+	// DefaultTolerance is the default allowed difference between the
+	// message timestamp and the current time.
+	DefaultTolerance = 5 * time.Minute
+
+	secretPrefix     = "whsec_"
+	signatureVersion = "v1"
+)
+
+var (
+	// ErrMissingHeaders is returned when any of the svix-id,
+	// svix-timestamp or svix-signature headers is missing.
+	ErrMissingHeaders = errors.New("missing required webhook headers")
+	// ErrInvalidSecret is returned when the signing secret cannot be
+	// decoded.
+	ErrInvalidSecret = errors.New("invalid webhook signing secret")
+	// ErrInvalidTimestamp is returned when the svix-timestamp header
+	// cannot be parsed.
+	ErrInvalidTimestamp = errors.New("invalid webhook timestamp")
+	// ErrTimestampTooOld is returned when the message timestamp is
+	// older than the allowed tolerance.
+	ErrTimestampTooOld = errors.New("webhook timestamp too old")
+	// ErrTimestampTooNew is returned when the message timestamp is
+	// further in the future than the allowed tolerance.
+	ErrTimestampTooNew = errors.New("webhook timestamp too new")
+	// ErrInvalidSignature is returned when none of the signatures in
+	// the svix-signature header match the payload.
+	ErrInvalidSignature = errors.New("no matching webhook signature found")
+)
+
+type VerifyParams struct {
+	// Payload is the raw request body. Required.
+	// The payload must not be modified in any way, not even parsed and
+	// re-serialized, otherwise the signature will not match.
+	Payload []byte
+	// Header holds the request headers. Required.
+	Header http.Header
+	// Secret is the signing secret of the webhook endpoint. Required.
+	Secret string
+	// Tolerance is the allowed difference between the message
+	// timestamp and the current time. Protects against replay attacks.
+	// Defaults to DefaultTolerance.
+	Tolerance time.Duration
+	// Clock can be used to keep track of time and will replace usage
+	// of the [time] package.
+	Clock clerk.Clock
+}
+
+// Verify checks that the webhook payload is signed with the secret
+// and that the message timestamp is within the allowed tolerance.
+// The svix-signature header can contain multiple signatures, for
+// example while the signing secret is being rotated. The payload is
+// considered valid if any of the signatures matches.
+// The returned errors can be checked with errors.Is against the
+// package errors.
+func Verify(params *VerifyParams) error {
+	msgID := params.Header.Get(HeaderID)
+	msgTimestamp := params.Header.Get(HeaderTimestamp)
+	msgSignature := params.Header.Get(HeaderSignature)
+	if msgID == "" || msgTimestamp == "" || msgSignature == "" {
+		return ErrMissingHeaders
+	}
+
+	key, err := decodeSecret(params.Secret)
+	if err != nil {
+		return err
+	}
+
+	err = verifyTimestamp(msgTimestamp, params.Tolerance, params.Clock)
+	if err != nil {
+		return err
+	}
+
+	expected := Sign(key, msgID, msgTimestamp, params.Payload)
+	for _, versionedSignature := range strings.Fields(msgSignature) {
+		version, signature, found := strings.Cut(versionedSignature, ",")
+		if !found || version != signatureVersion {
+			continue
+		}
+		decoded, err := base64.StdEncoding.DecodeString(signature)
+		if err != nil {
+			continue
+		}
+		if hmac.Equal(decoded, expected) {
+			return nil
+		}
+	}
+	return ErrInvalidSignature
+}
+
+// Sign returns the HMAC-SHA256 signature of the message, as
+// computed by Svix. The key is the decoded signing secret.
+func Sign(key []byte, msgID, msgTimestamp string, payload []byte) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(msgID))
+	mac.Write([]byte("."))
+	mac.Write([]byte(msgTimestamp))
+	mac.Write([]byte("."))
+	mac.Write(payload)
+	return mac.Sum(nil)
+}
+
+// Decodes the base64 signing secret. The "whsec_" prefix is
+// optional.
+func decodeSecret(secret string) ([]byte, error) {
+	secret = strings.TrimPrefix(secret, secretPrefix)
+	if secret == "" {
+		return nil, ErrInvalidSecret
+	}
+	key, err := base64.StdEncoding.DecodeString(secret)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidSecret, err)
+	}
+	return key, nil
+}
+
+func verifyTimestamp(msgTimestamp string, tolerance time.Duration, clock clerk.Clock) error {
+	seconds, err := strconv.ParseInt(msgTimestamp, 10, 64)
+	if err != nil {
+		return ErrInvalidTimestamp
+	}
+	if tolerance == 0 {
+		tolerance = DefaultTolerance
+	}
+	if clock == nil {
+		clock = clerk.NewClock()
+	}
+	now := clock.Now().UTC()
+	timestamp := time.Unix(seconds, 0).UTC()
+	if timestamp.Before(now.Add(-tolerance)) {
+		return ErrTimestampTooOld
+	}
+	if timestamp.After(now.Add(tolerance)) {
+		return ErrTimestampTooNew
+	}
+	return nil
+}
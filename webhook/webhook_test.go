@@ -0,0 +1,140 @@
+package webhook
+
+import (
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/clerk/clerk-sdk-go/v2/clerktest"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testSecret    = "whsec_MfKQ9r8GKYqrTwjUPD8ILPZIo2LaLaSw"
+	testMsgID     = "msg_p5jXN8AQM9LWM0D4loKWxJek"
+	testTimestamp = "1614265330"
+	testPayload   = `{"test": 2432232314}`
+	testSignature = "v1,g0hM9SsE+OTPJTGt/tmIKtSyZlE3uFJELVlNIOLJ1OE="
+)
+
+func newTestHeader(signature string) http.Header {
+	header := http.Header{}
+	header.Set(HeaderID, testMsgID)
+	header.Set(HeaderTimestamp, testTimestamp)
+	header.Set(HeaderSignature, signature)
+	return header
+}
+
+func TestVerify(t *testing.T) {
+	t.Parallel()
+	clock := clerktest.NewClockAt(time.Unix(1614265330, 0))
+	err := Verify(&VerifyParams{
+		Payload: []byte(testPayload),
+		Header:  newTestHeader(testSignature),
+		Secret:  testSecret,
+		Clock:   clock,
+	})
+	require.NoError(t, err)
+
+	// The secret prefix is optional.
+	err = Verify(&VerifyParams{
+		Payload: []byte(testPayload),
+		Header:  newTestHeader(testSignature),
+		Secret:  "MfKQ9r8GKYqrTwjUPD8ILPZIo2LaLaSw",
+		Clock:   clock,
+	})
+	require.NoError(t, err)
+
+	// Multiple signatures, only one of them matches.
+	err = Verify(&VerifyParams{
+		Payload: []byte(testPayload),
+		Header:  newTestHeader("v1,Ceo5qEr07ixe2NLpvHk3FH9bwy/WavXrAFQ/9tdO6mc= v2,foo " + testSignature),
+		Secret:  testSecret,
+		Clock:   clock,
+	})
+	require.NoError(t, err)
+}
+
+func TestVerify_Errors(t *testing.T) {
+	t.Parallel()
+	clock := clerktest.NewClockAt(time.Unix(1614265330, 0))
+	for _, tc := range []struct {
+		name    string
+		payload string
+		header  http.Header
+		secret  string
+		clock   *clerktest.Clock
+		want    error
+	}{
+		{
+			name:    "missing headers",
+			payload: testPayload,
+			header:  http.Header{},
+			secret:  testSecret,
+			clock:   clock,
+			want:    ErrMissingHeaders,
+		},
+		{
+			name:    "invalid secret",
+			payload: testPayload,
+			header:  newTestHeader(testSignature),
+			secret:  "whsec_!!!",
+			clock:   clock,
+			want:    ErrInvalidSecret,
+		},
+		{
+			name:    "tampered payload",
+			payload: `{"test": 2432232315}`,
+			header:  newTestHeader(testSignature),
+			secret:  testSecret,
+			clock:   clock,
+			want:    ErrInvalidSignature,
+		},
+		{
+			name:    "wrong secret",
+			payload: testPayload,
+			header:  newTestHeader(testSignature),
+			secret:  "whsec_" + base64.StdEncoding.EncodeToString([]byte("another-secret")),
+			clock:   clock,
+			want:    ErrInvalidSignature,
+		},
+		{
+			name:    "timestamp too old",
+			payload: testPayload,
+			header:  newTestHeader(testSignature),
+			secret:  testSecret,
+			clock:   clerktest.NewClockAt(time.Unix(1614265330, 0).Add(10 * time.Minute)),
+			want:    ErrTimestampTooOld,
+		},
+		{
+			name:    "timestamp too new",
+			payload: testPayload,
+			header:  newTestHeader(testSignature),
+			secret:  testSecret,
+			clock:   clerktest.NewClockAt(time.Unix(1614265330, 0).Add(-10 * time.Minute)),
+			want:    ErrTimestampTooNew,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			err := Verify(&VerifyParams{
+				Payload: []byte(tc.payload),
+				Header:  tc.header,
+				Secret:  tc.secret,
+				Clock:   tc.clock,
+			})
+			require.True(t, errors.Is(err, tc.want), err)
+		})
+	}
+
+	// A custom tolerance accepts older timestamps.
+	err := Verify(&VerifyParams{
+		Payload:   []byte(testPayload),
+		Header:    newTestHeader(testSignature),
+		Secret:    testSecret,
+		Clock:     clerktest.NewClockAt(time.Unix(1614265330, 0).Add(10 * time.Minute)),
+		Tolerance: time.Hour,
+	})
+	require.NoError(t, err)
+}